@@ -0,0 +1,47 @@
+// Package cache define los backends de caché usados para sesiones, api keys,
+// integraciones y variables. Por defecto cada réplica usa una caché en memoria;
+// con un Store compartido (por ejemplo Redis) las réplicas comparten las
+// sesiones validadas y las revocaciones.
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/entities"
+)
+
+var ErrNotFound = errors.New("cache: entry not found")
+
+// Store es un almacenamiento clave/valor con expiración por entrada.
+type Store interface {
+	// Get devuelve ErrNotFound si la clave no existe o ya expiró.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+type SessionCache interface {
+	Set(ctx context.Context, token string, data entities.JwtData)
+	Get(ctx context.Context, token string) *entities.JwtData
+	// Lookup devuelve la sesión almacenada aunque ya no esté fresca, junto con la fecha en que se guardó.
+	Lookup(ctx context.Context, token string) (*entities.JwtData, time.Time)
+	Delete(ctx context.Context, tokenID string)
+}
+
+type ApiKeyCache interface {
+	Set(ctx context.Context, token string, data entities.ApikeyData)
+	Get(ctx context.Context, token string) *entities.ApikeyData
+	Delete(ctx context.Context, tokenID string)
+}
+
+type IntegracionCache interface {
+	Set(ctx context.Context, empresa string, state entities.IntegracionState)
+	Get(ctx context.Context, empresa string) *entities.IntegracionState
+}
+
+type VariablesCache interface {
+	SetVariables(ctx context.Context, empresa string, variables []entities.Variable)
+	GetVariable(ctx context.Context, empresa string, variableName string) *string
+}
@@ -0,0 +1,87 @@
+// Package cachetest provides an in-memory cache.Store fake for tests.
+package cachetest
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/cache"
+)
+
+type item struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// Store is a map backed cache.Store. It is safe for concurrent use and lets
+// tests inspect what was written.
+type Store struct {
+	mu    sync.Mutex
+	items map[string]item
+	now   func() time.Time
+}
+
+var _ cache.Store = (*Store)(nil)
+
+func NewStore() *Store {
+	return &Store{items: map[string]item{}, now: time.Now}
+}
+
+// SetClock replaces the clock used to expire entries.
+func (s *Store) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	it, found := s.items[key]
+	if !found {
+		return nil, cache.ErrNotFound
+	}
+	if !it.expiresAt.IsZero() && !s.now().Before(it.expiresAt) {
+		delete(s.items, key)
+		return nil, cache.ErrNotFound
+	}
+	return slices.Clone(it.value), nil
+}
+
+func (s *Store) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	it := item{value: slices.Clone(value)}
+	if ttl > 0 {
+		it.expiresAt = s.now().Add(ttl)
+	}
+	s.items[key] = it
+	return nil
+}
+
+func (s *Store) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.items, key)
+	return nil
+}
+
+// Keys returns the stored keys in lexical order.
+func (s *Store) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.items))
+	for key := range s.items {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+)
+
+const expiryHeaderSize = 8
+
+type memoryStore struct {
+	cache *bigcache.BigCache
+}
+
+// NewMemoryStore crea un Store en memoria del proceso. maxTTL es el tiempo
+// máximo que una entrada puede vivir; cada Set puede usar un ttl menor.
+func NewMemoryStore(maxTTL time.Duration) (Store, error) {
+	c, err := bigcache.New(context.Background(), bigcache.DefaultConfig(maxTTL))
+	if err != nil {
+		return nil, err
+	}
+	return &memoryStore{cache: c}, nil
+}
+
+func (m *memoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := m.cache.Get(key)
+	if err != nil {
+		if errors.Is(err, bigcache.ErrEntryNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if len(data) < expiryHeaderSize {
+		return nil, ErrNotFound
+	}
+	expiresAt := int64(binary.BigEndian.Uint64(data[:expiryHeaderSize]))
+	if expiresAt > 0 && time.Now().UnixNano() > expiresAt {
+		_ = m.cache.Delete(key)
+		return nil, ErrNotFound
+	}
+	return data[expiryHeaderSize:], nil
+}
+
+func (m *memoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl).UnixNano()
+	}
+	data := make([]byte, expiryHeaderSize+len(value))
+	binary.BigEndian.PutUint64(data, uint64(expiresAt))
+	copy(data[expiryHeaderSize:], value)
+	return m.cache.Set(key, data)
+}
+
+func (m *memoryStore) Delete(ctx context.Context, key string) error {
+	if err := m.cache.Delete(key); err != nil && !errors.Is(err, bigcache.ErrEntryNotFound) {
+		return err
+	}
+	return nil
+}
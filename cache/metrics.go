@@ -0,0 +1,25 @@
+package cache
+
+import "github.com/sfperusacdev/identitysdk/metrics"
+
+// Nombres de las cachés en la métrica cache_lookups_total.
+const (
+	NameSession   = "session"
+	NameApiKey    = "apikey"
+	NameVariables = "variables"
+)
+
+var lookups = metrics.NewCounter(
+	"cache_lookups_total",
+	"Consultas a las cachés de sesiones, api keys y variables por resultado (hit/miss).",
+	"cache", "result",
+)
+
+// ObserveLookup registra si la consulta a la caché name encontró el valor.
+func ObserveLookup(name string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	lookups.With(name, result).Inc()
+}
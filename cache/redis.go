@@ -0,0 +1,272 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// RedisConfig configura un Store sobre cualquier servidor que hable el
+// protocolo RESP (Redis, Valkey, KeyDB, Dragonfly...).
+type RedisConfig struct {
+	Address  string
+	Username string
+	Password string
+	DB       int
+	// Prefix se antepone a todas las claves, útil para compartir un servidor entre ambientes.
+	Prefix      string
+	PoolSize    int
+	DialTimeout time.Duration
+	// IOTimeout aplica a cada comando cuando el contexto no tiene deadline.
+	IOTimeout time.Duration
+	TLS       *tls.Config
+}
+
+// RedisError es un error devuelto por el servidor (respuesta "-ERR ...").
+type RedisError string
+
+func (e RedisError) Error() string { return "redis: " + string(e) }
+
+type RedisStore struct {
+	cfg  RedisConfig
+	pool chan *redisConn
+}
+
+var _ Store = (*RedisStore)(nil)
+
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+func NewRedisStore(cfg RedisConfig) (*RedisStore, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("redis: address is required")
+	}
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = 10
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = 5 * time.Second
+	}
+	if cfg.IOTimeout <= 0 {
+		cfg.IOTimeout = 3 * time.Second
+	}
+	s := &RedisStore{cfg: cfg, pool: make(chan *redisConn, cfg.PoolSize)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DialTimeout)
+	defer cancel()
+	if _, err := s.Do(ctx, "PING"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := s.Do(ctx, "GET", s.cfg.Prefix+key)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, ErrNotFound
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("redis: unexpected reply %T for GET", reply)
+	}
+	return value, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []any{"SET", s.cfg.Prefix + key, value}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := s.Do(ctx, args...)
+	return err
+}
+
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	_, err := s.Do(ctx, "DEL", s.cfg.Prefix+key)
+	return err
+}
+
+// Do ejecuta un comando arbitrario. Los argumentos pueden ser string, []byte
+// o enteros; la respuesta es nil, string, int64, []byte o []any.
+func (s *RedisStore) Do(ctx context.Context, args ...any) (any, error) {
+	conn, err := s.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := conn.do(ctx, s.cfg.IOTimeout, args...)
+	var redisErr RedisError
+	if err != nil && !errors.As(err, &redisErr) {
+		conn.conn.Close()
+		return nil, err
+	}
+	s.release(conn)
+	return reply, err
+}
+
+// Close cierra las conexiones inactivas del pool.
+func (s *RedisStore) Close() error {
+	for {
+		select {
+		case conn := <-s.pool:
+			conn.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (s *RedisStore) acquire(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-s.pool:
+		return conn, nil
+	default:
+	}
+
+	dialer := &net.Dialer{Timeout: s.cfg.DialTimeout}
+	var (
+		netConn net.Conn
+		err     error
+	)
+	if s.cfg.TLS != nil {
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: s.cfg.TLS}).DialContext(ctx, "tcp", s.cfg.Address)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", s.cfg.Address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis: dial %s: %w", s.cfg.Address, err)
+	}
+	conn := &redisConn{conn: netConn, r: bufio.NewReader(netConn), w: bufio.NewWriter(netConn)}
+
+	if s.cfg.Password != "" {
+		args := []any{"AUTH", s.cfg.Password}
+		if s.cfg.Username != "" {
+			args = []any{"AUTH", s.cfg.Username, s.cfg.Password}
+		}
+		if _, err := conn.do(ctx, s.cfg.IOTimeout, args...); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	if s.cfg.DB != 0 {
+		if _, err := conn.do(ctx, s.cfg.IOTimeout, "SELECT", strconv.Itoa(s.cfg.DB)); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (s *RedisStore) release(conn *redisConn) {
+	select {
+	case s.pool <- conn:
+	default:
+		conn.conn.Close()
+	}
+}
+
+func (c *redisConn) do(ctx context.Context, timeout time.Duration, args ...any) (any, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(timeout)
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	if err := writeCommand(c.w, args...); err != nil {
+		return nil, err
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+	return readReply(c.r)
+}
+
+func writeCommand(w *bufio.Writer, args ...any) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var value []byte
+		switch v := arg.(type) {
+		case string:
+			value = []byte(v)
+		case []byte:
+			value = v
+		case int:
+			value = strconv.AppendInt(nil, int64(v), 10)
+		case int64:
+			value = strconv.AppendInt(nil, v, 10)
+		case float64:
+			value = strconv.AppendFloat(nil, v, 'f', -1, 64)
+		default:
+			return fmt.Errorf("redis: unsupported argument type %T", arg)
+		}
+		fmt.Fprintf(w, "$%d\r\n", len(value))
+		w.Write(value)
+		w.WriteString("\r\n")
+	}
+	return nil
+}
+
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("redis: malformed reply")
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, RedisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:size], nil
+	case '*':
+		size, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		items := make([]any, size)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				var redisErr RedisError
+				if !errors.As(err, &redisErr) {
+					return nil, err
+				}
+				items[i] = redisErr
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+	}
+}
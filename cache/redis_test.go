@@ -0,0 +1,178 @@
+package cache_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/cache"
+)
+
+// fakeRedis implements the few RESP commands used by RedisStore.
+type fakeRedis struct {
+	mu       sync.Mutex
+	data     map[string]string
+	password string
+	commands []string
+}
+
+func startFakeRedis(t *testing.T, password string) (*fakeRedis, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	f := &fakeRedis{data: map[string]string{}, password: password}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f, ln.Addr().String()
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authenticated := f.password == ""
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(args[0])
+		f.mu.Lock()
+		f.commands = append(f.commands, cmd)
+		var reply string
+		switch {
+		case cmd == "AUTH":
+			authenticated = args[len(args)-1] == f.password
+			reply = "+OK\r\n"
+			if !authenticated {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			reply = "-NOAUTH Authentication required\r\n"
+		case cmd == "PING":
+			reply = "+PONG\r\n"
+		case cmd == "SET":
+			f.data[args[1]] = args[2]
+			reply = "+OK\r\n"
+		case cmd == "GET":
+			value, ok := f.data[args[1]]
+			reply = "$-1\r\n"
+			if ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			}
+		case cmd == "DEL":
+			_, ok := f.data[args[1]]
+			delete(f.data, args[1])
+			reply = ":0\r\n"
+			if ok {
+				reply = ":1\r\n"
+			}
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(header[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func TestRedisStoreRoundTrip(t *testing.T) {
+	fake, addr := startFakeRedis(t, "secret")
+	store, err := cache.NewRedisStore(cache.RedisConfig{Address: addr, Password: "secret", Prefix: "svc:"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	if err := store.Set(ctx, "k", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	got, err := store.Get(ctx, "k")
+	if err != nil || string(got) != "value" {
+		t.Fatalf("get: %q %v", got, err)
+	}
+	if _, ok := fake.data["svc:k"]; !ok {
+		t.Fatal("expected prefixed key in server")
+	}
+	if err := store.Delete(ctx, "k"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "k"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRedisStoreWrongPassword(t *testing.T) {
+	_, addr := startFakeRedis(t, "secret")
+	_, err := cache.NewRedisStore(cache.RedisConfig{Address: addr, Password: "otro"})
+	var redisErr cache.RedisError
+	if !errors.As(err, &redisErr) {
+		t.Fatalf("expected RedisError, got %v", err)
+	}
+}
+
+func TestMemoryStoreExpiresEntries(t *testing.T) {
+	store, err := cache.NewMemoryStore(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	_ = store.Set(ctx, "short", []byte("x"), 10*time.Millisecond)
+	_ = store.Set(ctx, "long", []byte("y"), time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := store.Get(ctx, "short"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected short entry to expire, got %v", err)
+	}
+	if got, err := store.Get(ctx, "long"); err != nil || string(got) != "y" {
+		t.Fatalf("unexpected long entry: %q %v", got, err)
+	}
+}
@@ -0,0 +1,37 @@
+package identitysdk
+
+import (
+	"github.com/sfperusacdev/identitysdk/cache"
+	apikeycache "github.com/sfperusacdev/identitysdk/internal/apikey_cache"
+	integracioncache "github.com/sfperusacdev/identitysdk/internal/integracion_cache"
+	"github.com/sfperusacdev/identitysdk/internal/revocation"
+	sessioncache "github.com/sfperusacdev/identitysdk/internal/session_cache"
+	variablecache "github.com/sfperusacdev/identitysdk/internal/variable_cache"
+)
+
+// SetCacheStore usa el store para las cachés de sesiones, api keys,
+// integraciones y variables, y para compartir las revocaciones entre réplicas.
+func SetCacheStore(store cache.Store) {
+	if store == nil {
+		return
+	}
+	sessioncache.DefaultCache = sessioncache.New(store)
+	apikeycache.DefaultCache = apikeycache.New(store)
+	integracioncache.DefaultCache = integracioncache.New(store)
+	variablecache.DefaultCache = variablecache.New(store)
+	revocation.Default.SetStore(store)
+}
+
+// SetSessionCache reemplaza la caché usada por ValidateTokenWithCache.
+func SetSessionCache(c cache.SessionCache) {
+	if c != nil {
+		sessioncache.DefaultCache = c
+	}
+}
+
+// SetApiKeyCache reemplaza la caché usada por ValidateApiKeyWithCache.
+func SetApiKeyCache(c cache.ApiKeyCache) {
+	if c != nil {
+		apikeycache.DefaultCache = c
+	}
+}
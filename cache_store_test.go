@@ -0,0 +1,60 @@
+package identitysdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/cache"
+	"github.com/sfperusacdev/identitysdk/cache/cachetest"
+	"github.com/sfperusacdev/identitysdk/entities"
+)
+
+func TestSharedCacheStoreAcrossReplicas(t *testing.T) {
+	var checks atomic.Int32
+	identity := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"type": "success",
+			"data": entities.JwtData{Jwt: entities.Jwt{Empresa: "sfperu", Username: "compartido"}},
+		})
+	}))
+	defer identity.Close()
+
+	previous := identitysdk.GetIdentityServer()
+	identitysdk.SetIdentityServer(identity.URL)
+
+	shared := cachetest.NewStore()
+	identitysdk.SetCacheStore(shared)
+	t.Cleanup(func() {
+		identitysdk.SetIdentityServer(previous)
+		store, _ := cache.NewMemoryStore(0)
+		identitysdk.SetCacheStore(store)
+	})
+
+	ctx := context.Background()
+	token := unsignedToken(t, "shared-session")
+	if _, err := identitysdk.ValidateTokenWithCache(ctx, token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// otra réplica: mismo store compartido, nueva instancia de las cachés
+	identitysdk.SetCacheStore(shared)
+	data, err := identitysdk.ValidateTokenWithCache(ctx, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data.Jwt.Username != "compartido" || checks.Load() != 1 {
+		t.Fatalf("expected session from shared store, identity calls: %d", checks.Load())
+	}
+
+	identitysdk.RevokeTokens(ctx, identitysdk.RevokedToken{ID: "shared-session"})
+	keys := shared.Keys()
+	if len(keys) != 1 || keys[0] != "revoked:token:shared-session" {
+		t.Fatalf("unexpected shared keys: %v", keys)
+	}
+}
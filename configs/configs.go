@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -29,16 +30,135 @@ type DatabaseConfigProvider interface {
 	GetLogLevel() string
 }
 
+// TokenVerificationConfigProvider es opcional; si el proveedor de configuración
+// lo implementa, los tokens se pueden verificar localmente con las llaves JWKS de identity.
+type TokenVerificationConfigProvider interface {
+	TokenVerification() TokenVerificationConfig
+}
+
+// HTTPClientConfigProvider es opcional; configura el cliente HTTP compartido
+// usado para identity y los servicios internos.
+type HTTPClientConfigProvider interface {
+	HTTPClient() HTTPClientConfig
+}
+
+// LoggingConfigProvider es opcional; define el formato y nivel de los logs.
+type LoggingConfigProvider interface {
+	Logging() LoggingConfig
+}
+
+// GRPCTLSConfigProvider es opcional; activa TLS/mTLS en el servidor y los clientes gRPC.
+type GRPCTLSConfigProvider interface {
+	GRPCTLS() GRPCTLSConfig
+}
+
+// GRPCClientConfigProvider es opcional; configura el descubrimiento, el
+// balanceo y los reintentos de los clientes gRPC.
+type GRPCClientConfigProvider interface {
+	GRPCClient() GRPCClientConfig
+}
+
 type ConfigsProviderFunc func(configPath ConfigPath) (GeneralServiceConfigProvider, DatabaseConfigProvider, error)
 
 type GeneralServiceConfig struct {
-	ListenAddressValue       string         `mapstructure:"address" yaml:"address"`
-	GRPCAddressValue         string         `mapstructure:"grpc_address" yaml:"grpc_address"`
-	IdentityValue            string         `mapstructure:"identity" yaml:"identity"`
-	IdentityAccessTokenValue string         `mapstructure:"identity_access_token" yaml:"identity_access_token"`
-	CacheDirVal              string         `mapstructure:"cache_dir" yaml:"cache_dir"`
-	StagingDirVal            string         `mapstructure:"staging_dir" yaml:"staging_dir"`
-	DatabaseEntity           DatabaseConfig `mapstructure:"database" yaml:"database"`
+	ListenAddressValue       string                  `mapstructure:"address" yaml:"address"`
+	GRPCAddressValue         string                  `mapstructure:"grpc_address" yaml:"grpc_address"`
+	IdentityValue            string                  `mapstructure:"identity" yaml:"identity"`
+	IdentityAccessTokenValue string                  `mapstructure:"identity_access_token" yaml:"identity_access_token"`
+	CacheDirVal              string                  `mapstructure:"cache_dir" yaml:"cache_dir"`
+	StagingDirVal            string                  `mapstructure:"staging_dir" yaml:"staging_dir"`
+	DatabaseEntity           DatabaseConfig          `mapstructure:"database" yaml:"database"`
+	TokenVerificationValue   TokenVerificationConfig `mapstructure:"token_verification" yaml:"token_verification"`
+	HTTPClientValue          HTTPClientConfig        `mapstructure:"http_client" yaml:"http_client"`
+	LoggingValue             LoggingConfig           `mapstructure:"logging" yaml:"logging"`
+	GRPCTLSValue             GRPCTLSConfig           `mapstructure:"grpc_tls" yaml:"grpc_tls"`
+	GRPCClientValue          GRPCClientConfig        `mapstructure:"grpc_client" yaml:"grpc_client"`
+}
+
+// HTTPClientConfig valores en cero usan los defaults de xreq.DefaultClientConfig.
+type HTTPClientConfig struct {
+	Timeout          time.Duration `mapstructure:"timeout" yaml:"timeout"`
+	MaxRetries       *int          `mapstructure:"max_retries" yaml:"max_retries"`
+	RetryBaseDelay   time.Duration `mapstructure:"retry_base_delay" yaml:"retry_base_delay"`
+	RetryMaxDelay    time.Duration `mapstructure:"retry_max_delay" yaml:"retry_max_delay"`
+	BreakerThreshold *int          `mapstructure:"breaker_threshold" yaml:"breaker_threshold"`
+	BreakerCooldown  time.Duration `mapstructure:"breaker_cooldown" yaml:"breaker_cooldown"`
+}
+
+type LoggingConfig struct {
+	// Format "json" o "text" (por defecto)
+	Format string `mapstructure:"format" yaml:"format"`
+	// Level "debug", "info" (por defecto), "warn" o "error"
+	Level string `mapstructure:"level" yaml:"level"`
+}
+
+// GRPCTLSConfig el par de llaves sale de CertFile/KeyFile o, si Company está
+// definido, del certificado de la empresa emitido por identity.
+type GRPCTLSConfig struct {
+	CertFile string `mapstructure:"cert_file" yaml:"cert_file"`
+	KeyFile  string `mapstructure:"key_file" yaml:"key_file"`
+	CAFile   string `mapstructure:"ca_file" yaml:"ca_file"`
+	Company  string `mapstructure:"company" yaml:"company"`
+	// ReloadInterval cada cuánto se revisan los certificados; por defecto 1m
+	ReloadInterval time.Duration       `mapstructure:"reload_interval" yaml:"reload_interval"`
+	Server         GRPCTLSServerConfig `mapstructure:"server" yaml:"server"`
+	Client         GRPCTLSClientConfig `mapstructure:"client" yaml:"client"`
+}
+
+type GRPCTLSServerConfig struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+	// ClientAuth "none" (por defecto), "request", "verify_if_given" o "require" (mTLS)
+	ClientAuth string `mapstructure:"client_auth" yaml:"client_auth"`
+}
+
+type GRPCTLSClientConfig struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+	// ServerName reemplaza el host de la ubicación al validar el certificado del servidor
+	ServerName string `mapstructure:"server_name" yaml:"server_name"`
+}
+
+// GRPCClientConfig valores en cero usan los defaults de grpc/client.
+type GRPCClientConfig struct {
+	// Balancer "round_robin" (por defecto) o "least_request"
+	Balancer string `mapstructure:"balancer" yaml:"balancer"`
+	// DisableHealthCheck deja de consultar grpc.health.v1 en cada endpoint
+	DisableHealthCheck bool `mapstructure:"disable_health_check" yaml:"disable_health_check"`
+	// RefreshInterval cada cuánto se vuelven a pedir las ubicaciones a identity; por defecto 30s
+	RefreshInterval time.Duration `mapstructure:"refresh_interval" yaml:"refresh_interval"`
+	// IdleTimeout tiempo sin llamadas tras el que se cierra la conexión de una empresa; por defecto 10m
+	IdleTimeout time.Duration `mapstructure:"idle_timeout" yaml:"idle_timeout"`
+	// Timeout plazo de cada llamada unaria cuando el contexto no tiene uno menor;
+	// 0 sin límite. Los streams no tienen plazo ni reintentos
+	Timeout time.Duration         `mapstructure:"timeout" yaml:"timeout"`
+	Retry   GRPCClientRetryConfig `mapstructure:"retry" yaml:"retry"`
+}
+
+type GRPCClientRetryConfig struct {
+	// MaxAttempts incluye el intento original; 0 o 1 desactiva los reintentos
+	MaxAttempts       int           `mapstructure:"max_attempts" yaml:"max_attempts"`
+	InitialBackoff    time.Duration `mapstructure:"initial_backoff" yaml:"initial_backoff"`
+	MaxBackoff        time.Duration `mapstructure:"max_backoff" yaml:"max_backoff"`
+	BackoffMultiplier float64       `mapstructure:"backoff_multiplier" yaml:"backoff_multiplier"`
+	// RetryableCodes por defecto ["UNAVAILABLE"]
+	RetryableCodes []string `mapstructure:"retryable_codes" yaml:"retryable_codes"`
+}
+
+const (
+	TokenVerificationRemote = "remote" // cada token se valida contra /v1/check-token
+	TokenVerificationJWKS   = "jwks"   // firma y claims se validan localmente
+)
+
+type TokenVerificationConfig struct {
+	Mode string `mapstructure:"mode" yaml:"mode"`
+	// JwksURL por defecto es <identity>/.well-known/jwks.json
+	JwksURL  string   `mapstructure:"jwks_url" yaml:"jwks_url"`
+	Audience []string `mapstructure:"audience" yaml:"audience"`
+	// SessionRefresh indica cada cuánto se refrescan permisos y sucursales desde identity.
+	SessionRefresh time.Duration `mapstructure:"session_refresh" yaml:"session_refresh"`
+	Leeway         time.Duration `mapstructure:"leeway" yaml:"leeway"`
+	// MaxTokenLifetime vida máxima de los tokens de identity; las revocaciones
+	// por usuario se conservan ese tiempo. Por defecto 24 horas.
+	MaxTokenLifetime time.Duration `mapstructure:"max_token_lifetime" yaml:"max_token_lifetime"`
 }
 
 type DatabaseConfig struct {
@@ -52,6 +172,11 @@ type DatabaseConfig struct {
 
 var _ GeneralServiceConfigProvider = (*GeneralServiceConfig)(nil)
 var _ DatabaseConfigProvider = (*GeneralServiceConfig)(nil)
+var _ TokenVerificationConfigProvider = (*GeneralServiceConfig)(nil)
+var _ HTTPClientConfigProvider = (*GeneralServiceConfig)(nil)
+var _ LoggingConfigProvider = (*GeneralServiceConfig)(nil)
+var _ GRPCTLSConfigProvider = (*GeneralServiceConfig)(nil)
+var _ GRPCClientConfigProvider = (*GeneralServiceConfig)(nil)
 
 // ListenAddress implements GeneralServiceConfigProvider.
 func (c *GeneralServiceConfig) ListenAddress() string {
@@ -92,6 +217,39 @@ func (c *GeneralServiceConfig) IdentityAccessToken() string {
 	return c.IdentityAccessTokenValue
 }
 
+// TokenVerification implements TokenVerificationConfigProvider.
+func (c *GeneralServiceConfig) TokenVerification() TokenVerificationConfig {
+	cfg := c.TokenVerificationValue
+	cfg.Mode = strings.ToLower(strings.TrimSpace(cfg.Mode))
+	if cfg.Mode == "" {
+		cfg.Mode = TokenVerificationRemote
+	}
+	if cfg.JwksURL == "" {
+		cfg.JwksURL = strings.TrimSuffix(c.Identity(), "/") + "/.well-known/jwks.json"
+	}
+	return cfg
+}
+
+// HTTPClient implements HTTPClientConfigProvider.
+func (c *GeneralServiceConfig) HTTPClient() HTTPClientConfig {
+	return c.HTTPClientValue
+}
+
+// Logging implements LoggingConfigProvider.
+func (c *GeneralServiceConfig) Logging() LoggingConfig {
+	return c.LoggingValue
+}
+
+// GRPCTLS implements GRPCTLSConfigProvider.
+func (c *GeneralServiceConfig) GRPCTLS() GRPCTLSConfig {
+	return c.GRPCTLSValue
+}
+
+// GRPCClient implements GRPCClientConfigProvider.
+func (c *GeneralServiceConfig) GRPCClient() GRPCClientConfig {
+	return c.GRPCClientValue
+}
+
 // GetDBName implements DatabaseConfigProvider.
 func (c *GeneralServiceConfig) GetDBName() string {
 	return c.DatabaseEntity.DBName
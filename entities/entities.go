@@ -42,3 +42,8 @@ type JwtPublicClientData struct {
 type JwtPublicClientDataSession struct {
 	Username string `json:"username"`
 }
+
+type IntegracionState struct {
+	ExternalReff   string `json:"external_reff"`
+	IntegrationURL string `json:"integration_url"`
+}
@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"sort"
+
+	"github.com/sfperusacdev/identitysdk/permissions"
+)
+
+// MethodAnnotation describe un método gRPC. Permissions sigue el formato de
+// httpapi.PermissionChecker (todas requeridas, "g:" global) y PermissionRule
+// el de httpapi.PermissionRuleChecker; se evalúan ambos.
+type MethodAnnotation struct {
+	Auth           AuthMode
+	Permissions    []string
+	PermissionRule permissions.Rule
+	Description    string
+}
+
+// MethodAnnotator es opcional para los GrpcServiceRegister; las claves son los
+// nombres completos de los métodos, p. ej. "/identity.v1.RevocationService/Revoke".
+type MethodAnnotator interface {
+	MethodAnnotations() map[string]MethodAnnotation
+}
+
+// MethodCatalog reúne las anotaciones de todos los servicios registrados.
+type MethodCatalog map[string]MethodAnnotation
+
+func NewMethodCatalog(services []GrpcServiceRegister) MethodCatalog {
+	catalog := MethodCatalog{}
+	for _, service := range services {
+		annotator, ok := service.(MethodAnnotator)
+		if !ok {
+			continue
+		}
+		for method, annotation := range annotator.MethodAnnotations() {
+			catalog[method] = annotation
+		}
+	}
+	return catalog
+}
+
+// ReferencedPermissions devuelve los permisos usados por los métodos, sin prefijos.
+func (c MethodCatalog) ReferencedPermissions() []string {
+	methods := make([]string, 0, len(c))
+	for method := range c {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	var ids []string
+	for _, method := range methods {
+		ids = append(ids, permissions.Referenced(permissions.FromList(c[method].Permissions))...)
+		if rule := c[method].PermissionRule; rule != nil {
+			ids = append(ids, permissions.Referenced(rule)...)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,158 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/permissions"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthMode indica cómo se autentica un método, igual que los modos de las rutas httpapi.
+type AuthMode string
+
+const (
+	// AuthAccessKey (por defecto) exige el access token del servicio y toma la
+	// identidad de la metadata x-empresa, x-username y authorization.
+	AuthAccessKey AuthMode = ""
+	// AuthJwt exige un token de usuario en authorization; no requiere access token
+	// y la empresa y el usuario salen solo del token.
+	AuthJwt AuthMode = "jwt"
+	// AuthApiKey exige una api key en x-api-key.
+	AuthApiKey AuthMode = "apikey"
+	// AuthPublicClient exige un token de cliente público en authorization.
+	AuthPublicClient AuthMode = "public_client"
+)
+
+// authenticate construye el contexto de identidad según el modo del método.
+func (c MethodCatalog) authenticate(ctx context.Context, method string) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "metadata no encontrada")
+	}
+
+	var err error
+	switch mode := c[method].Auth; mode {
+	case AuthAccessKey:
+		ctx, err = contextWithAccessTokenMetadata(ctx, md)
+	case AuthJwt:
+		ctx, err = contextWithJwt(ctx, md)
+	case AuthApiKey:
+		ctx, err = contextWithApiKey(ctx, md)
+	case AuthPublicClient:
+		ctx, err = contextWithPublicClient(ctx, md)
+	default:
+		slog.Error("unknown gRPC auth mode", "method", method, "auth", mode)
+		return nil, status.Error(codes.Internal, "modo de autenticación desconocido")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if sucursal := firstMetadataValue(md, MetadataSucursal); sucursal != "" {
+		ctx = identitysdk.CtxWithSucursal(ctx, sucursal)
+	}
+	if origin := firstMetadataValue(md, MetadataRequestOrigin); origin != "" {
+		ctx = identitysdk.CtxWithRequestOrigin(ctx, origin)
+	}
+	if requestID := firstMetadataValue(md, MetadataRequestID); requestID != "" {
+		ctx = identitysdk.CtxWithRequestID(ctx, requestID)
+	}
+	return ctx, nil
+}
+
+// checkPermissions aplica los permisos del método con el contexto ya autenticado.
+func (c MethodCatalog) checkPermissions(ctx context.Context, method string) error {
+	annotation, found := c[method]
+	if !found {
+		return nil
+	}
+	if len(annotation.Permissions) > 0 {
+		if missing := permissions.Missing(ctx, permissions.FromList(annotation.Permissions), nil); missing != "" {
+			return status.Error(codes.PermissionDenied, "permisos requeridos: "+missing)
+		}
+	}
+	if annotation.PermissionRule != nil {
+		if missing := permissions.Missing(ctx, annotation.PermissionRule, nil); missing != "" {
+			return status.Error(codes.PermissionDenied, "permisos requeridos: "+missing)
+		}
+	}
+	return nil
+}
+
+func contextWithAccessTokenMetadata(ctx context.Context, md metadata.MD) (context.Context, error) {
+	accessToken := firstMetadataValue(md, MetadataAccessToken)
+	if accessToken == "" {
+		return nil, status.Error(codes.Unauthenticated, "access-token no encontrado")
+	}
+
+	if err := identitysdk.ValidateAccessKey(ctx, accessToken); err != nil {
+		slog.Warn("gRPC access token validation failed", "error", err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	newCtx := ctx
+
+	if empresa := firstMetadataValue(md, MetadataEmpresa); empresa != "" {
+		newCtx = identitysdk.CtxWithDomain(newCtx, empresa)
+	}
+
+	if username := firstMetadataValue(md, MetadataUsername); username != "" {
+		newCtx = identitysdk.CtxWithUsername(newCtx, username)
+	}
+
+	if token := firstMetadataValue(md, MetadataToken); token != "" {
+		var err error
+		newCtx, err = identitysdk.BuildContext(newCtx, token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+	}
+
+	return newCtx, nil
+}
+
+func contextWithJwt(ctx context.Context, md metadata.MD) (context.Context, error) {
+	token := firstMetadataValue(md, MetadataToken)
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "[close] token no encontrado")
+	}
+	newCtx, err := identitysdk.BuildContext(ctx, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return newCtx, nil
+}
+
+func contextWithApiKey(ctx context.Context, md metadata.MD) (context.Context, error) {
+	apikey := firstMetadataValue(md, MetadataApiKey)
+	if apikey == "" {
+		return nil, status.Error(codes.Unauthenticated, "[close] API KEY no encontrado")
+	}
+	data, err := identitysdk.ValidateApiKeyWithCache(ctx, apikey)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if data == nil {
+		return nil, status.Error(codes.Unauthenticated, "[close] api key session invalida")
+	}
+	return identitysdk.BuildApikeyContext(ctx, apikey, &data.Apikey), nil
+}
+
+func contextWithPublicClient(ctx context.Context, md metadata.MD) (context.Context, error) {
+	token := firstMetadataValue(md, MetadataToken)
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "[close] token no encontrado")
+	}
+	data, err := identitysdk.ValidatePublicClientToken(ctx, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if data == nil {
+		return nil, status.Error(codes.Unauthenticated, "[close] session invalida")
+	}
+	return identitysdk.BuildPublicClientContext(ctx, token, data), nil
+}
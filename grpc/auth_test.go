@@ -0,0 +1,133 @@
+package grpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/entities"
+	identitygrpc "github.com/sfperusacdev/identitysdk/grpc"
+	"github.com/sfperusacdev/identitysdk/jwks"
+	"github.com/sfperusacdev/identitysdk/jwks/jwkstest"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	methodAccessKey = "/test.Whoami/AccessKey"
+	methodJwt       = "/test.Whoami/Jwt"
+	methodApiKey    = "/test.Whoami/ApiKey"
+	methodAdmin     = "/test.Whoami/Admin"
+)
+
+// whoamiService responde "empresa/usuario" del contexto autenticado.
+type whoamiService struct{}
+
+func (whoamiService) Register(server gogrpc.ServiceRegistrar) {
+	methods := []gogrpc.MethodDesc{}
+	for _, fullMethod := range []string{methodAccessKey, methodJwt, methodApiKey, methodAdmin} {
+		methods = append(methods, gogrpc.MethodDesc{
+			MethodName: fullMethod[len("/test.Whoami/"):],
+			Handler:    whoamiHandler(fullMethod),
+		})
+	}
+	server.RegisterService(&gogrpc.ServiceDesc{
+		ServiceName: "test.Whoami",
+		HandlerType: (*any)(nil),
+		Methods:     methods,
+	}, whoamiService{})
+}
+
+func (whoamiService) MethodAnnotations() map[string]identitygrpc.MethodAnnotation {
+	return map[string]identitygrpc.MethodAnnotation{
+		methodJwt:    {Auth: identitygrpc.AuthJwt},
+		methodApiKey: {Auth: identitygrpc.AuthApiKey},
+		methodAdmin:  {Auth: identitygrpc.AuthJwt, Permissions: []string{"planilla.aprobar"}},
+	}
+}
+
+func whoamiHandler(fullMethod string) func(any, context.Context, func(any) error, gogrpc.UnaryServerInterceptor) (any, error) {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor gogrpc.UnaryServerInterceptor) (any, error) {
+		in := new(emptypb.Empty)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		handler := func(ctx context.Context, _ any) (any, error) {
+			return wrapperspb.String(identitysdk.Empresa(ctx) + "/" + identitysdk.Username(ctx)), nil
+		}
+		return interceptor(ctx, in, &gogrpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}, handler)
+	}
+}
+
+func TestMethodAuthModes(t *testing.T) {
+	keys := jwkstest.NewServer(t)
+	identity := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"type": "success",
+			"data": entities.JwtData{Jwt: entities.Jwt{Empresa: "sfperu", Username: "kevin"}},
+		})
+	}))
+	defer identity.Close()
+
+	previous := identitysdk.GetIdentityServer()
+	identitysdk.SetIdentityServer(identity.URL)
+	identitysdk.SetTokenVerifier(jwks.NewVerifier(jwks.NewKeySet(keys.URL())))
+	identitysdk.SetSessionRefreshInterval(time.Hour)
+	t.Cleanup(func() {
+		identitysdk.SetIdentityServer(previous)
+		identitysdk.SetTokenVerifier(nil)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := identitygrpc.NewServer([]identitygrpc.GrpcServiceRegister{whoamiService{}}, nil)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := gogrpc.NewClient(listener.Addr().String(), gogrpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	call := func(method string, pairs ...string) (string, codes.Code) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		ctx = metadata.AppendToOutgoingContext(ctx, pairs...)
+		out := new(wrapperspb.StringValue)
+		err := conn.Invoke(ctx, method, &emptypb.Empty{}, out)
+		return out.GetValue(), status.Code(err)
+	}
+
+	token := keys.Sign(t, nil)
+
+	// la identidad sale del token, no de la metadata de confianza
+	who, code := call(methodJwt, identitygrpc.MetadataToken, token, identitygrpc.MetadataEmpresa, "otra")
+	if code != codes.OK || who != "sfperu/kevin" {
+		t.Fatalf("expected sfperu/kevin, got %q (%s)", who, code)
+	}
+	if _, code := call(methodJwt, identitygrpc.MetadataEmpresa, "sfperu"); code != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without token, got %s", code)
+	}
+	if _, code := call(methodAccessKey, identitygrpc.MetadataToken, token); code != codes.Unauthenticated {
+		t.Fatalf("expected access key mode to require x-access-token, got %s", code)
+	}
+	if _, code := call(methodApiKey, identitygrpc.MetadataToken, token); code != codes.Unauthenticated {
+		t.Fatalf("expected api key mode to require x-api-key, got %s", code)
+	}
+	if _, code := call(methodAdmin, identitygrpc.MetadataToken, token); code != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %s", code)
+	}
+}
@@ -3,43 +3,92 @@ package client
 import (
 	"context"
 	"errors"
+	"io"
 	"log/slog"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sfperusacdev/identitysdk"
 	"github.com/sfperusacdev/identitysdk/configs"
 	identitygrpc "github.com/sfperusacdev/identitysdk/grpc"
+	"github.com/sfperusacdev/identitysdk/tracing"
 	"github.com/sfperusacdev/identitysdk/xreq"
 	"go.uber.org/fx"
 	gogrpc "google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 const grpcReadyTimeout = 5 * time.Second
 
 var errGrpcConnShutdown = errors.New("grpc connection is shutdown")
 
+// GrpcClient mantiene una conexión por empresa y resource code; cada conexión
+// balancea entre todas las ubicaciones que devuelve identity y las vuelve a
+// consultar periódicamente y cuando falla un endpoint. Las conexiones sin uso
+// durante IdleTimeout se cierran.
 type GrpcClient struct {
-	config configs.GeneralServiceConfigProvider
+	config   configs.GeneralServiceConfigProvider
+	creds    credentials.TransportCredentials
+	options  configs.GRPCClientConfig
+	lookup   Resolver
+	dialOpts []gogrpc.DialOption
+
+	serviceConfig    string
+	serviceConfigErr error
 
 	mu           sync.RWMutex
-	conns        map[string]*gogrpc.ClientConn
+	conns        map[string]*clientConn
 	resourceCode string
+
+	evictOnce sync.Once
+	stopOnce  sync.Once
+	stop      chan struct{}
 }
 
 var _ gogrpc.ClientConnInterface = (*GrpcClient)(nil)
 
-func NewGrpcClient(lc fx.Lifecycle, resourceCode string, config configs.GeneralServiceConfigProvider) *GrpcClient {
+type ClientOption func(*GrpcClient)
+
+// WithTransportCredentials conecta con TLS, por ejemplo con TLSProvider.ClientCredentials.
+func WithTransportCredentials(creds credentials.TransportCredentials) ClientOption {
+	return func(g *GrpcClient) {
+		if creds == nil {
+			slog.Warn("gRPC transport credentials are nil, operation skipped")
+			return
+		}
+		g.creds = creds
+	}
+}
+
+func NewGrpcClient(lc fx.Lifecycle, resourceCode string, config configs.GeneralServiceConfigProvider, opts ...ClientOption) *GrpcClient {
 	client := &GrpcClient{
 		resourceCode: resourceCode,
 		config:       config,
-		conns:        make(map[string]*gogrpc.ClientConn),
+		creds:        insecure.NewCredentials(),
+		conns:        make(map[string]*clientConn),
+		stop:         make(chan struct{}),
+	}
+	client.lookup = client.requestGrpcLocations
+	if provider, ok := config.(configs.GRPCClientConfigProvider); ok {
+		client.options = provider.GRPCClient()
+	}
+	for _, apply := range opts {
+		apply(client)
 	}
+	if client.options.RefreshInterval <= 0 {
+		client.options.RefreshInterval = DefaultRefreshInterval
+	}
+	if client.options.IdleTimeout <= 0 {
+		client.options.IdleTimeout = DefaultIdleTimeout
+	}
+	client.serviceConfig, client.serviceConfigErr = serviceConfig(client.options)
 
 	lc.Append(fx.Hook{
 		OnStop: func(ctx context.Context) error {
@@ -71,52 +120,39 @@ func (g *GrpcClient) NewStream(ctx context.Context, desc *gogrpc.StreamDesc, met
 }
 
 func (g *GrpcClient) Connection(ctx context.Context, resourceCode string) (*gogrpc.ClientConn, error) {
-	grpcURL, err := g.requestGrpcLocation(ctx, resourceCode)
-	if err != nil {
-		return nil, err
+	if g.serviceConfigErr != nil {
+		return nil, g.serviceConfigErr
 	}
+	companyCode := identitysdk.Empresa(ctx)
+	key := companyCode + "/" + resourceCode
 
-	g.mu.RLock()
-	conn := g.conns[grpcURL]
-	g.mu.RUnlock()
-
-	if conn != nil {
-		readyConn, err := g.ensureConnection(ctx, resourceCode, grpcURL, conn)
-		if err == nil {
-			return readyConn, nil
-		}
-
+	if conn := g.cached(key); conn != nil {
+		readyConn, err := g.ensureConnection(ctx, resourceCode, key, conn)
 		if !errors.Is(err, errGrpcConnShutdown) {
-			return nil, err
+			return readyConn, err
 		}
 	}
 
-	g.mu.Lock()
-	defer g.mu.Unlock()
-
-	if conn := g.conns[grpcURL]; conn != nil {
-		if conn.GetState() != connectivity.Shutdown {
-			readyConn, err := g.ensureConnection(ctx, resourceCode, grpcURL, conn)
-			if err == nil {
-				return readyConn, nil
-			}
-
-			if !errors.Is(err, errGrpcConnShutdown) {
-				return nil, err
-			}
-		}
-
-		delete(g.conns, grpcURL)
-		if err := conn.Close(); err != nil {
-			slog.Warn("failed to close shutdown grpc connection", "empresa", identitysdk.Empresa(ctx), "resource_code", resourceCode, "grpc_url", grpcURL, "error", err)
-		}
+	// identity se consulta sin tomar g.mu para que una empresa lenta no
+	// detenga las llamadas de las demás
+	endpoints := &discovery{
+		companyCode:  companyCode,
+		resourceCode: resourceCode,
+		lookup:       g.lookup,
+		interval:     g.options.RefreshInterval,
+	}
+	if _, err := endpoints.refresh(ctx); err != nil {
+		slog.Warn("failed to resolve grpc locations", "empresa", companyCode, "resource_code", resourceCode, "error", err)
+		return nil, err
 	}
 
-	conn, err = gogrpc.NewClient(
-		grpcURL,
-		gogrpc.WithTransportCredentials(insecure.NewCredentials()),
+	dialOpts := append([]gogrpc.DialOption{
+		gogrpc.WithResolvers(endpoints),
+		gogrpc.WithDefaultServiceConfig(g.serviceConfig),
+		gogrpc.WithTransportCredentials(g.creds),
 		gogrpc.WithUnaryInterceptor(g.unaryContextInterceptor),
 		gogrpc.WithStreamInterceptor(g.streamContextInterceptor),
+		gogrpc.WithIdleTimeout(g.options.IdleTimeout),
 		gogrpc.WithConnectParams(gogrpc.ConnectParams{
 			Backoff: backoff.Config{
 				BaseDelay:  200 * time.Millisecond,
@@ -126,15 +162,78 @@ func (g *GrpcClient) Connection(ctx context.Context, resourceCode string) (*gogr
 			},
 			MinConnectTimeout: grpcReadyTimeout,
 		}),
-	)
+	}, g.dialOpts...)
+	conn, err := gogrpc.NewClient(discoveryScheme+":///"+resourceCode, dialOpts...)
 	if err != nil {
-		slog.Warn("failed to create grpc client", "empresa", identitysdk.Empresa(ctx), "resource_code", resourceCode, "grpc_url", grpcURL, "error", err)
+		slog.Warn("failed to create grpc client", "empresa", companyCode, "resource_code", resourceCode, "error", err)
 		return nil, err
 	}
 
-	g.conns[grpcURL] = conn
+	g.mu.Lock()
+	if current := g.conns[key]; current != nil && current.conn.GetState() != connectivity.Shutdown {
+		// otra llamada creó la conexión mientras se consultaba identity
+		current.touch()
+		g.mu.Unlock()
+		conn.Close()
+		return g.ensureConnection(ctx, resourceCode, key, current.conn)
+	}
+	entry := &clientConn{conn: conn}
+	entry.touch()
+	g.conns[key] = entry
+	g.evictOnce.Do(func() { go g.evictIdle() })
+	g.mu.Unlock()
 
-	return g.ensureConnection(ctx, resourceCode, grpcURL, conn)
+	return g.ensureConnection(ctx, resourceCode, key, conn)
+}
+
+// cached devuelve la conexión de key y la marca como usada.
+func (g *GrpcClient) cached(key string) *gogrpc.ClientConn {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	entry := g.conns[key]
+	if entry == nil {
+		return nil
+	}
+	entry.touch()
+	return entry.conn
+}
+
+// evictIdle cierra las conexiones que gRPC ya pasó a Idle (sin llamadas ni
+// streams activos) y que nadie pidió durante IdleTimeout, para no mantener
+// health checks contra cada endpoint por cada empresa que llamó alguna vez.
+func (g *GrpcClient) evictIdle() {
+	ticker := time.NewTicker(max(g.options.IdleTimeout/2, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.stop:
+			return
+		case <-ticker.C:
+		}
+		var idle []*gogrpc.ClientConn
+		g.mu.Lock()
+		for key, entry := range g.conns {
+			if entry.idleFor() >= g.options.IdleTimeout && entry.conn.GetState() == connectivity.Idle {
+				delete(g.conns, key)
+				idle = append(idle, entry.conn)
+			}
+		}
+		g.mu.Unlock()
+		for _, conn := range idle {
+			conn.Close()
+		}
+	}
+}
+
+type clientConn struct {
+	conn     *gogrpc.ClientConn
+	lastUsed atomic.Int64
+}
+
+func (c *clientConn) touch() { c.lastUsed.Store(time.Now().UnixNano()) }
+
+func (c *clientConn) idleFor() time.Duration {
+	return time.Since(time.Unix(0, c.lastUsed.Load()))
 }
 
 func (g *GrpcClient) unaryContextInterceptor(
@@ -146,7 +245,15 @@ func (g *GrpcClient) unaryContextInterceptor(
 	invoker gogrpc.UnaryInvoker,
 	opts ...gogrpc.CallOption,
 ) error {
-	return invoker(g.outgoingContext(ctx), method, req, reply, cc, opts...)
+	ctx, span := tracing.Start(ctx, method, tracing.SpanKindClient)
+	defer span.End()
+	span.SetAttribute("rpc.method", method)
+	span.SetAttribute("net.peer.name", cc.Target())
+
+	err := invoker(g.outgoingContext(ctx), method, req, reply, cc, opts...)
+	span.SetAttribute("rpc.grpc.status_code", status.Code(err).String())
+	span.SetError(err)
+	return err
 }
 
 func (g *GrpcClient) streamContextInterceptor(
@@ -157,11 +264,60 @@ func (g *GrpcClient) streamContextInterceptor(
 	streamer gogrpc.Streamer,
 	opts ...gogrpc.CallOption,
 ) (gogrpc.ClientStream, error) {
-	return streamer(g.outgoingContext(ctx), desc, cc, method, opts...)
+	ctx, span := tracing.Start(ctx, method, tracing.SpanKindClient)
+	span.SetAttribute("rpc.method", method)
+	span.SetAttribute("net.peer.name", cc.Target())
+
+	stream, err := streamer(g.outgoingContext(ctx), desc, cc, method, opts...)
+	if err != nil {
+		span.SetAttribute("rpc.grpc.status_code", status.Code(err).String())
+		span.SetError(err)
+		span.End()
+		return nil, err
+	}
+	traced := &tracedClientStream{ClientStream: stream, span: span, serverStreams: desc.ServerStreams}
+	// si el llamador cancela sin leer hasta el final el span igual se cierra
+	traced.stop = context.AfterFunc(ctx, func() {
+		traced.finish(status.FromContextError(ctx.Err()).Err())
+	})
+	return traced, nil
+}
+
+// tracedClientStream cierra el span del stream cuando RecvMsg devuelve el
+// estado final o cuando se cancela el contexto de la llamada.
+type tracedClientStream struct {
+	gogrpc.ClientStream
+	span          *tracing.Span
+	serverStreams bool
+	stop          func() bool
+	once          sync.Once
+}
+
+func (s *tracedClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil && s.serverStreams {
+		return nil
+	}
+	// io.EOF o, sin stream del servidor, la única respuesta cierran la llamada
+	s.stop()
+	if errors.Is(err, io.EOF) {
+		s.finish(nil)
+	} else {
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *tracedClientStream) finish(err error) {
+	s.once.Do(func() {
+		s.span.SetAttribute("rpc.grpc.status_code", status.Code(err).String())
+		s.span.SetError(err)
+		s.span.End()
+	})
 }
 
 func (g *GrpcClient) outgoingContext(ctx context.Context) context.Context {
-	pairs := make([]string, 0, 12)
+	pairs := make([]string, 0, 16)
 	appendPair := func(key, value string) {
 		value = strings.TrimSpace(value)
 		if value == "" || strings.Contains(value, "####") {
@@ -175,28 +331,33 @@ func (g *GrpcClient) outgoingContext(ctx context.Context) context.Context {
 	appendPair(identitygrpc.MetadataEmpresa, identitysdk.Empresa(ctx))
 	appendPair(identitygrpc.MetadataUsername, identitysdk.Username(ctx))
 	appendPair(identitygrpc.MetadataRequestOrigin, identitysdk.RequestOrigin(ctx))
+	appendPair(identitygrpc.MetadataRequestID, identitysdk.RequestID(ctx))
 
 	_, sucursal := identitysdk.Empresa_Sucursal(ctx)
 	appendPair(identitygrpc.MetadataSucursal, sucursal)
 
+	sc := tracing.SpanContextFromContext(ctx)
+	appendPair(identitygrpc.MetadataTraceParent, sc.TraceParent())
+	appendPair(identitygrpc.MetadataTraceState, sc.TraceState)
+
 	if len(pairs) == 0 {
 		return ctx
 	}
 	return metadata.AppendToOutgoingContext(ctx, pairs...)
 }
 
-func (g *GrpcClient) ensureConnection(ctx context.Context, resourceCode string, grpcURL string, conn *gogrpc.ClientConn) (*gogrpc.ClientConn, error) {
+func (g *GrpcClient) ensureConnection(ctx context.Context, resourceCode string, key string, conn *gogrpc.ClientConn) (*gogrpc.ClientConn, error) {
 	state := conn.GetState()
 
 	if state == connectivity.Shutdown {
 		g.mu.Lock()
-		if current := g.conns[grpcURL]; current == conn {
-			delete(g.conns, grpcURL)
+		if current := g.conns[key]; current != nil && current.conn == conn {
+			delete(g.conns, key)
 		}
 		g.mu.Unlock()
 
 		if err := conn.Close(); err != nil {
-			slog.Warn("failed to close shutdown grpc connection", "empresa", identitysdk.Empresa(ctx), "resource_code", resourceCode, "grpc_url", grpcURL, "error", err)
+			slog.Warn("failed to close shutdown grpc connection", "empresa", identitysdk.Empresa(ctx), "resource_code", resourceCode, "error", err)
 		}
 
 		return nil, errGrpcConnShutdown
@@ -207,7 +368,7 @@ func (g *GrpcClient) ensureConnection(ctx context.Context, resourceCode string,
 	}
 
 	if err := waitForReady(ctx, conn); err != nil {
-		slog.Warn("grpc connection is not ready", "empresa", identitysdk.Empresa(ctx), "resource_code", resourceCode, "grpc_url", grpcURL, "state", conn.GetState(), "error", err)
+		slog.Warn("grpc connection is not ready", "empresa", identitysdk.Empresa(ctx), "resource_code", resourceCode, "state", conn.GetState(), "error", err)
 		return nil, err
 	}
 
@@ -245,34 +406,37 @@ func waitForReady(ctx context.Context, conn *gogrpc.ClientConn) error {
 }
 
 func (g *GrpcClient) close() error {
+	g.stopOnce.Do(func() { close(g.stop) })
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
 	var closeErr error
 
-	for grpcURL, conn := range g.conns {
-		if err := conn.Close(); err != nil {
-			slog.Warn("failed to close grpc connection", "grpc_url", grpcURL, "error", err)
+	for key, entry := range g.conns {
+		if err := entry.conn.Close(); err != nil {
+			slog.Warn("failed to close grpc connection", "connection", key, "error", err)
 
 			if closeErr == nil {
 				closeErr = err
 			}
 		}
 
-		delete(g.conns, grpcURL)
+		delete(g.conns, key)
 	}
 
 	return closeErr
 }
 
-func (g *GrpcClient) requestGrpcLocation(ctx context.Context, resourceCode string) (string, error) {
+// requestGrpcLocations consulta a identity; acepta "locations" con varias
+// instancias o "location" con una sola.
+func (g *GrpcClient) requestGrpcLocations(ctx context.Context, companyCode, resourceCode string) ([]string, error) {
 	accessToken := g.config.IdentityAccessToken()
-	companyCode := identitysdk.Empresa(ctx)
 
 	var apiResponse struct {
 		Message string `json:"message"`
 		Data    struct {
-			Location string `json:"location"`
+			Location  string   `json:"location"`
+			Locations []string `json:"locations"`
 		} `json:"data"`
 	}
 
@@ -287,15 +451,18 @@ func (g *GrpcClient) requestGrpcLocation(ctx context.Context, resourceCode strin
 		xreq.WithUnmarshalResponseInto(&apiResponse),
 	); err != nil {
 		slog.Warn("failed to request grpc location", "empresa", companyCode, "resource_code", resourceCode, "error", err)
-		return "", err
+		return nil, err
 	}
 
-	location := strings.TrimSpace(apiResponse.Data.Location)
-	if location == "" {
+	locations := apiResponse.Data.Locations
+	if location := strings.TrimSpace(apiResponse.Data.Location); location != "" {
+		locations = append(locations, location)
+	}
+	if len(locations) == 0 {
 		err := errors.New("grpc location is empty")
 		slog.Warn("invalid grpc location response", "empresa", companyCode, "resource_code", resourceCode, "message", apiResponse.Message)
-		return "", err
+		return nil, err
 	}
 
-	return location, nil
+	return locations, nil
 }
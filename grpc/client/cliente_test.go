@@ -0,0 +1,439 @@
+package client_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/configs"
+	identitygrpc "github.com/sfperusacdev/identitysdk/grpc"
+	"github.com/sfperusacdev/identitysdk/grpc/client"
+	"github.com/sfperusacdev/identitysdk/tracing"
+	"go.uber.org/fx/fxtest"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const nameMethod = "/test.Name/Get"
+
+// backend servidor en memoria que responde su nombre.
+type backend struct {
+	name     string
+	listener *bufconn.Listener
+	server   *gogrpc.Server
+	health   *grpchealth.Server
+	// handle permite simular fallos y demoras
+	handle atomic.Pointer[func(ctx context.Context) error]
+}
+
+func newBackend(t *testing.T, name string, opts ...gogrpc.ServerOption) *backend {
+	t.Helper()
+	b := &backend{
+		name:     name,
+		listener: bufconn.Listen(1 << 20),
+		server:   gogrpc.NewServer(opts...),
+		health:   grpchealth.NewServer(),
+	}
+	healthpb.RegisterHealthServer(b.server, b.health)
+	b.server.RegisterService(&gogrpc.ServiceDesc{
+		ServiceName: "test.Name",
+		HandlerType: (*any)(nil),
+		Methods: []gogrpc.MethodDesc{{
+			MethodName: "Get",
+			Handler: func(_ any, ctx context.Context, dec func(any) error, _ gogrpc.UnaryServerInterceptor) (any, error) {
+				if err := dec(new(emptypb.Empty)); err != nil {
+					return nil, err
+				}
+				if handle := b.handle.Load(); handle != nil {
+					if err := (*handle)(ctx); err != nil {
+						return nil, err
+					}
+				}
+				return wrapperspb.String(b.name), nil
+			},
+		}},
+	}, b)
+	go b.server.Serve(b.listener)
+	t.Cleanup(b.server.Stop)
+	return b
+}
+
+// cluster resuelve las ubicaciones desde un mapa mutable.
+type cluster struct {
+	mu        sync.Mutex
+	backends  map[string]*backend
+	locations []string
+}
+
+func newCluster(backends ...*backend) *cluster {
+	c := &cluster{backends: map[string]*backend{}}
+	for _, b := range backends {
+		c.backends[b.name] = b
+		c.locations = append(c.locations, b.name)
+	}
+	return c
+}
+
+func (c *cluster) setLocations(locations ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.locations = locations
+}
+
+func (c *cluster) resolve(context.Context, string, string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string{}, c.locations...), nil
+}
+
+func (c *cluster) dial(ctx context.Context, addr string) (net.Conn, error) {
+	c.mu.Lock()
+	b := c.backends[addr]
+	c.mu.Unlock()
+	return b.listener.DialContext(ctx)
+}
+
+func newClient(t *testing.T, c *cluster, cfg configs.GRPCClientConfig, opts ...client.ClientOption) *client.GrpcClient {
+	t.Helper()
+	lc := fxtest.NewLifecycle(t)
+	grpcClient := client.NewGrpcClient(lc, "com.sfperusac.test", &configs.GeneralServiceConfig{}, append([]client.ClientOption{
+		client.WithResolver(c.resolve),
+		client.WithClientConfig(cfg),
+		client.WithDialOptions(gogrpc.WithContextDialer(c.dial)),
+	}, opts...)...)
+	t.Cleanup(func() { lc.RequireStop() })
+	return grpcClient
+}
+
+func callName(grpcClient *client.GrpcClient) (string, error) {
+	return callNameAs(context.Background(), grpcClient)
+}
+
+func callNameAs(ctx context.Context, grpcClient *client.GrpcClient) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	out := new(wrapperspb.StringValue)
+	err := grpcClient.Invoke(ctx, nameMethod, &emptypb.Empty{}, out)
+	return out.GetValue(), err
+}
+
+// eventually llama hasta que las últimas n respuestas cumplan want; los
+// errores cuentan como respuesta "" mientras el balanceador converge.
+func eventually(t *testing.T, grpcClient *client.GrpcClient, n int, want func(map[string]int) bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		seen := map[string]int{}
+		for range n {
+			name, _ := callName(grpcClient)
+			seen[name]++
+		}
+		if want(seen) {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("condition not reached")
+}
+
+func TestGrpcClientBalancesAndSkipsUnhealthyEndpoints(t *testing.T) {
+	a, b := newBackend(t, "a"), newBackend(t, "b")
+	grpcClient := newClient(t, newCluster(a, b), configs.GRPCClientConfig{})
+
+	eventually(t, grpcClient, 10, func(seen map[string]int) bool {
+		return seen["a"] > 0 && seen["b"] > 0
+	})
+
+	a.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	eventually(t, grpcClient, 10, func(seen map[string]int) bool { return seen["b"] == 10 })
+
+	a.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	eventually(t, grpcClient, 10, func(seen map[string]int) bool {
+		return seen["a"] > 0 && seen["b"] > 0
+	})
+	b.server.Stop()
+	eventually(t, grpcClient, 10, func(seen map[string]int) bool { return seen["a"] == 10 })
+}
+
+func TestGrpcClientRefreshesLocations(t *testing.T) {
+	a, b := newBackend(t, "a"), newBackend(t, "b")
+	c := newCluster(a, b)
+	c.setLocations("a")
+	grpcClient := newClient(t, c, configs.GRPCClientConfig{
+		Balancer:        client.BalancerLeastRequest,
+		RefreshInterval: 50 * time.Millisecond,
+	})
+
+	if name, err := callName(grpcClient); err != nil || name != "a" {
+		t.Fatalf("expected a, got %q (%v)", name, err)
+	}
+	c.setLocations("dns:///b")
+	eventually(t, grpcClient, 5, func(seen map[string]int) bool { return seen["b"] == 5 })
+}
+
+func TestGrpcClientRetryAndTimeout(t *testing.T) {
+	a := newBackend(t, "a")
+	var attempts atomic.Int32
+	flaky := func(ctx context.Context) error {
+		if attempts.Add(1)%2 == 1 {
+			return status.Error(codes.Unavailable, "try again")
+		}
+		return nil
+	}
+	a.handle.Store(&flaky)
+	grpcClient := newClient(t, newCluster(a), configs.GRPCClientConfig{
+		Timeout: 200 * time.Millisecond,
+		Retry:   configs.GRPCClientRetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+	})
+	if name, err := callName(grpcClient); err != nil || name != "a" {
+		t.Fatalf("expected retry to succeed, got %q (%v)", name, err)
+	}
+
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	a.handle.Store(&slow)
+	if _, err := callName(grpcClient); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded from configured timeout, got %v", err)
+	}
+}
+
+func TestGrpcClientTimeoutSkipsStreams(t *testing.T) {
+	a := newBackend(t, "a")
+	grpcClient := newClient(t, newCluster(a), configs.GRPCClientConfig{
+		Timeout: 100 * time.Millisecond,
+		Retry:   configs.GRPCClientRetryConfig{MaxAttempts: 2},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	watch, err := healthpb.NewHealthClient(grpcClient).Watch(ctx, &healthpb.HealthCheckRequest{Service: "planillas"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := watch.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	a.health.SetServingStatus("planillas", healthpb.HealthCheckResponse_SERVING)
+	if res, err := watch.Recv(); err != nil || res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("stream must outlive the unary timeout, got %v (%v)", res.GetStatus(), err)
+	}
+}
+
+func TestGrpcClientTracesStreams(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracing.SetExporter(exporter)
+	t.Cleanup(func() { tracing.SetExporter(nil) })
+
+	a := newBackend(t, "a")
+	grpcClient := newClient(t, newCluster(a), configs.GRPCClientConfig{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watch, err := healthpb.NewHealthClient(grpcClient).Watch(ctx, &healthpb.HealthCheckRequest{Service: "planillas"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := watch.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	const watchMethod = "/grpc.health.v1.Health/Watch"
+	streamSpan := func() (tracing.SpanData, bool) {
+		for _, span := range exporter.Spans() {
+			if span.Name == watchMethod && span.Kind == tracing.SpanKindClient {
+				return span, true
+			}
+		}
+		return tracing.SpanData{}, false
+	}
+	if _, found := streamSpan(); found {
+		t.Fatal("stream span must stay open while the stream is active")
+	}
+
+	cancel()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		span, found := streamSpan()
+		if found {
+			if span.Attributes["rpc.grpc.status_code"] != codes.Canceled.String() || span.Error == "" {
+				t.Fatalf("expected cancelled stream span, got %+v", span)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stream span was not ended after cancellation")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGrpcClientRejectsInvalidBalancer(t *testing.T) {
+	grpcClient := newClient(t, newCluster(newBackend(t, "a")), configs.GRPCClientConfig{Balancer: "random"})
+	if _, err := callName(grpcClient); err == nil {
+		t.Fatal("expected invalid balancer error")
+	}
+}
+
+func TestGrpcClientLookupDoesNotBlockOtherCompanies(t *testing.T) {
+	a := newBackend(t, "a")
+	c := newCluster(a)
+	release := make(chan struct{})
+	var lookups atomic.Int32
+	slowResolver := func(ctx context.Context, companyCode, resourceCode string) ([]string, error) {
+		lookups.Add(1)
+		if companyCode == "lenta" {
+			<-release
+		}
+		return c.resolve(ctx, companyCode, resourceCode)
+	}
+	grpcClient := newClient(t, c, configs.GRPCClientConfig{RefreshInterval: time.Hour}, client.WithResolver(slowResolver))
+
+	sfperu := identitysdk.CtxWithDomain(context.Background(), "sfperu")
+	if name, err := callNameAs(sfperu, grpcClient); err != nil || name != "a" {
+		t.Fatalf("expected a, got %q (%v)", name, err)
+	}
+
+	slowDone := make(chan error, 1)
+	go func() {
+		_, err := callNameAs(identitysdk.CtxWithDomain(context.Background(), "lenta"), grpcClient)
+		slowDone <- err
+	}()
+	for lookups.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	if name, err := callNameAs(sfperu, grpcClient); err != nil || name != "a" {
+		t.Fatalf("expected existing connection to keep working, got %q (%v)", name, err)
+	}
+	close(release)
+	if err := <-slowDone; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGrpcClientEvictsIdleConnections(t *testing.T) {
+	a := newBackend(t, "a")
+	c := newCluster(a)
+	var lookups atomic.Int32
+	countingResolver := func(ctx context.Context, companyCode, resourceCode string) ([]string, error) {
+		lookups.Add(1)
+		return c.resolve(ctx, companyCode, resourceCode)
+	}
+	grpcClient := newClient(t, c, configs.GRPCClientConfig{
+		RefreshInterval: time.Hour,
+		IdleTimeout:     100 * time.Millisecond,
+	}, client.WithResolver(countingResolver))
+
+	if _, err := callName(grpcClient); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := callName(grpcClient); err != nil || lookups.Load() != 1 {
+		t.Fatalf("expected the connection to be reused, got %d lookups (%v)", lookups.Load(), err)
+	}
+	// el evictor revisa cada segundo como mínimo
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := callName(grpcClient); err != nil || lookups.Load() != 2 {
+		t.Fatalf("expected the idle connection to be replaced, got %d lookups (%v)", lookups.Load(), err)
+	}
+}
+
+func TestGrpcClientTLSWithDiscovery(t *testing.T) {
+	caCert, caKey, caPEM := newTestCA(t)
+	certPEM, keyPEM := issueCert(t, caCert, caKey, "localhost")
+	serverTLS, err := identitygrpc.NewTLSProvider(context.Background(), identitygrpc.TLSOptions{
+		KeyPair: func(context.Context) ([]byte, []byte, error) { return certPEM, keyPEM, nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sin server_name: el certificado se valida contra el host descubierto
+	clientTLS, err := identitygrpc.NewTLSProvider(context.Background(), identitygrpc.TLSOptions{
+		CA: func(context.Context) ([]byte, error) { return caPEM, nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secure := newBackend(t, "localhost:8443", gogrpc.Creds(serverTLS.ServerCredentials()))
+	grpcClient := newClient(t, newCluster(secure), configs.GRPCClientConfig{},
+		client.WithTransportCredentials(clientTLS.ClientCredentials()))
+	if name, err := callName(grpcClient); err != nil || name != "localhost:8443" {
+		t.Fatalf("expected TLS call through discovered endpoint, got %q (%v)", name, err)
+	}
+
+	other := newBackend(t, "otro.local:8443", gogrpc.Creds(serverTLS.ServerCredentials()))
+	mismatch := newClient(t, newCluster(other), configs.GRPCClientConfig{},
+		client.WithTransportCredentials(clientTLS.ClientCredentials()))
+	if _, err := callName(mismatch); err == nil {
+		t.Fatal("expected certificate name mismatch to be rejected")
+	}
+}
+
+func newTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func issueCert(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, host string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
@@ -0,0 +1,319 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/configs"
+	gogrpc "google.golang.org/grpc"
+	_ "google.golang.org/grpc/balancer/leastrequest"
+	"google.golang.org/grpc/balancer/roundrobin"
+	_ "google.golang.org/grpc/health"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	BalancerRoundRobin   = "round_robin"
+	BalancerLeastRequest = "least_request"
+)
+
+// DefaultRefreshInterval cada cuánto se vuelven a pedir las ubicaciones.
+const DefaultRefreshInterval = 30 * time.Second
+
+// DefaultIdleTimeout tiempo sin llamadas tras el que se cierra la conexión de una empresa.
+const DefaultIdleTimeout = 10 * time.Minute
+
+const (
+	discoveryScheme  = "identitysdk"
+	discoveryTimeout = 10 * time.Second
+	// minResolveGap evita consultar identity en cada fallo de conexión
+	minResolveGap = time.Second
+)
+
+// Resolver devuelve las direcciones host:port donde atiende resourceCode para la empresa.
+type Resolver func(ctx context.Context, companyCode, resourceCode string) ([]string, error)
+
+// WithResolver reemplaza la consulta de ubicaciones a identity, por ejemplo con
+// direcciones fijas o servidores bufconn en pruebas.
+func WithResolver(lookup Resolver) ClientOption {
+	return func(g *GrpcClient) {
+		if lookup == nil {
+			slog.Warn("gRPC resolver is nil, operation skipped")
+			return
+		}
+		g.lookup = lookup
+	}
+}
+
+// WithClientConfig reemplaza la configuración de balanceo y reintentos que se
+// lee de configs.GRPCClientConfigProvider.
+func WithClientConfig(cfg configs.GRPCClientConfig) ClientOption {
+	return func(g *GrpcClient) {
+		g.options = cfg
+	}
+}
+
+// WithDialOptions agrega opciones a cada conexión que crea el cliente.
+func WithDialOptions(opts ...gogrpc.DialOption) ClientOption {
+	return func(g *GrpcClient) {
+		g.dialOpts = append(g.dialOpts, opts...)
+	}
+}
+
+// endpointServerName nombre que se valida en el certificado del endpoint; el
+// target de la conexión es el resource code y no sirve para TLS.
+func endpointServerName(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// endpointAddress admite ubicaciones con esquema (dns:///host:port) y devuelve host:port.
+func endpointAddress(location string) string {
+	location = strings.TrimSpace(location)
+	if i := strings.Index(location, "://"); i >= 0 {
+		location = strings.TrimLeft(location[i+3:], "/")
+	}
+	return strings.TrimSuffix(location, "/")
+}
+
+// discovery mantiene los endpoints de un resource code para una empresa y
+// construye los resolvers de sus conexiones.
+type discovery struct {
+	companyCode  string
+	resourceCode string
+	lookup       Resolver
+	interval     time.Duration
+
+	mu    sync.Mutex
+	addrs []string
+}
+
+var _ resolver.Builder = (*discovery)(nil)
+
+func (d *discovery) refresh(ctx context.Context) ([]string, error) {
+	locations, err := d.lookup(ctx, d.companyCode, d.resourceCode)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0, len(locations))
+	seen := map[string]bool{}
+	for _, location := range locations {
+		addr := endpointAddress(location)
+		if addr == "" || seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		addrs = append(addrs, addr)
+	}
+	if len(addrs) == 0 {
+		return nil, errors.New("grpc location is empty")
+	}
+	d.mu.Lock()
+	d.addrs = addrs
+	d.mu.Unlock()
+	return addrs, nil
+}
+
+func (d *discovery) current() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.addrs
+}
+
+// Build implements [resolver.Builder].
+func (d *discovery) Build(_ resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	r := &discoveryResolver{
+		discovery:  d,
+		cc:         cc,
+		resolveNow: make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	if addrs := d.current(); len(addrs) > 0 {
+		r.update(addrs)
+	} else {
+		r.ResolveNow(resolver.ResolveNowOptions{})
+	}
+	go r.watch()
+	return r, nil
+}
+
+// Scheme implements [resolver.Builder].
+func (d *discovery) Scheme() string { return discoveryScheme }
+
+type discoveryResolver struct {
+	*discovery
+	cc         resolver.ClientConn
+	resolveNow chan struct{}
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// ResolveNow lo llama gRPC cuando falla una conexión; la consulta se hace en watch.
+func (r *discoveryResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.resolveNow <- struct{}{}:
+	default:
+	}
+}
+
+func (r *discoveryResolver) Close() {
+	r.closeOnce.Do(func() { close(r.done) })
+}
+
+func (r *discoveryResolver) watch() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	var last time.Time
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		case <-r.resolveNow:
+			if wait := minResolveGap - time.Since(last); wait > 0 {
+				select {
+				case <-r.done:
+					return
+				case <-time.After(wait):
+				}
+			}
+		}
+		last = time.Now()
+		r.resolve()
+	}
+}
+
+func (r *discoveryResolver) resolve() {
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+	addrs, err := r.refresh(ctx)
+	if err != nil {
+		// se mantienen los endpoints anteriores mientras identity no responda
+		slog.Warn("failed to refresh grpc locations", "empresa", r.companyCode, "resource_code", r.resourceCode, "error", err)
+		if len(r.current()) == 0 {
+			r.cc.ReportError(err)
+		}
+		return
+	}
+	r.update(addrs)
+}
+
+func (r *discoveryResolver) update(addrs []string) {
+	state := resolver.State{Addresses: make([]resolver.Address, len(addrs))}
+	for i, addr := range addrs {
+		state.Addresses[i] = resolver.Address{Addr: addr, ServerName: endpointServerName(addr)}
+	}
+	if err := r.cc.UpdateState(state); err != nil {
+		slog.Debug("grpc resolver state rejected", "empresa", r.companyCode, "resource_code", r.resourceCode, "error", err)
+	}
+}
+
+// serviceConfig arma la configuración de servicio de gRPC: política de balanceo,
+// health checking por endpoint y plazo y reintentos para los métodos unarios.
+// Los métodos con streaming quedan sin plazo ni reintentos: un stream largo,
+// como el de trabajadores de una empresa grande, no debe cortarse por Timeout.
+func serviceConfig(cfg configs.GRPCClientConfig) (string, error) {
+	var policy map[string]any
+	switch strings.ToLower(strings.TrimSpace(cfg.Balancer)) {
+	case "", BalancerRoundRobin:
+		policy = map[string]any{roundrobin.Name: map[string]any{}}
+	case BalancerLeastRequest:
+		policy = map[string]any{"least_request_experimental": map[string]any{"choiceCount": 2}}
+	default:
+		return "", fmt.Errorf("grpc client: invalid balancer %q", cfg.Balancer)
+	}
+	config := map[string]any{"loadBalancingConfig": []any{policy}}
+	if !cfg.DisableHealthCheck {
+		config["healthCheckConfig"] = map[string]any{"serviceName": ""}
+	}
+
+	method := map[string]any{"name": []any{map[string]any{}}}
+	if cfg.Timeout > 0 {
+		method["timeout"] = durationJSON(cfg.Timeout)
+	}
+	if retry := cfg.Retry; retry.MaxAttempts > 1 {
+		initialBackoff, maxBackoff, multiplier := retry.InitialBackoff, retry.MaxBackoff, retry.BackoffMultiplier
+		if initialBackoff <= 0 {
+			initialBackoff = 100 * time.Millisecond
+		}
+		if maxBackoff < initialBackoff {
+			maxBackoff = max(2*time.Second, initialBackoff)
+		}
+		if multiplier <= 0 {
+			multiplier = 2
+		}
+		retryableCodes := []string{"UNAVAILABLE"}
+		if len(retry.RetryableCodes) > 0 {
+			retryableCodes = make([]string, len(retry.RetryableCodes))
+			for i, code := range retry.RetryableCodes {
+				retryableCodes[i] = strings.ToUpper(strings.TrimSpace(code))
+			}
+		}
+		method["retryPolicy"] = map[string]any{
+			"maxAttempts":          retry.MaxAttempts,
+			"initialBackoff":       durationJSON(initialBackoff),
+			"maxBackoff":           durationJSON(maxBackoff),
+			"backoffMultiplier":    multiplier,
+			"retryableStatusCodes": retryableCodes,
+		}
+	}
+	if len(method) > 1 {
+		methods := []any{method}
+		// la entrada por método tiene prioridad sobre la general
+		if streams := streamingMethods(); len(streams) > 0 {
+			methods = append(methods, map[string]any{"name": streams})
+		}
+		config["methodConfig"] = methods
+	}
+
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// streamingMethods devuelve los métodos con streaming de los servicios
+// protobuf enlazados en el binario, como nombres de la configuración de servicio.
+func streamingMethods() []any {
+	var names []string
+	protoregistry.GlobalFiles.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		services := file.Services()
+		for i := range services.Len() {
+			service := services.Get(i)
+			methods := service.Methods()
+			for j := range methods.Len() {
+				method := methods.Get(j)
+				if method.IsStreamingClient() || method.IsStreamingServer() {
+					names = append(names, string(service.FullName())+"/"+string(method.Name()))
+				}
+			}
+		}
+		return true
+	})
+	sort.Strings(names)
+	streams := make([]any, len(names))
+	for i, name := range names {
+		service, method, _ := strings.Cut(name, "/")
+		streams[i] = map[string]any{"service": service, "method": method}
+	}
+	return streams
+}
+
+// durationJSON formato de duración de la configuración de servicio ("1.5s").
+func durationJSON(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
@@ -2,18 +2,27 @@ package client
 
 import (
 	"github.com/sfperusacdev/identitysdk/configs"
+	identitygrpc "github.com/sfperusacdev/identitysdk/grpc"
 	asistenciapb "github.com/sfperusacdev/identitysdk/grpc/gen/asistencia"
 	contratospb "github.com/sfperusacdev/identitysdk/grpc/gen/contratos"
 	"go.uber.org/fx"
 	"google.golang.org/grpc"
 )
 
+type clientParams struct {
+	fx.In
+	Lifecycle   fx.Lifecycle
+	Config      configs.GeneralServiceConfigProvider
+	Credentials identitygrpc.ClientCredentials `optional:"true"`
+}
+
 func newGrpcClient(resourceCode string) any {
-	return func(
-		lc fx.Lifecycle,
-		config configs.GeneralServiceConfigProvider,
-	) grpc.ClientConnInterface {
-		return NewGrpcClient(lc, resourceCode, config)
+	return func(p clientParams) grpc.ClientConnInterface {
+		var opts []ClientOption
+		if p.Credentials != nil {
+			opts = append(opts, WithTransportCredentials(p.Credentials))
+		}
+		return NewGrpcClient(p.Lifecycle, resourceCode, p.Config, opts...)
 	}
 }
 
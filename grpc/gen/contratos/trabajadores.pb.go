@@ -110,6 +110,77 @@ func (x *Trabajadores) GetItems() []*Trabajadores_Item {
 	return nil
 }
 
+// StreamTrabajadoresRequest filtros de los RPC con streaming
+type StreamTrabajadoresRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	CompanyCode string                 `protobuf:"bytes,1,opt,name=company_code,json=companyCode,proto3" json:"company_code,omitempty"`
+	SoloActivos bool                   `protobuf:"varint,2,opt,name=solo_activos,json=soloActivos,proto3" json:"solo_activos,omitempty"`
+	// vacío para todas las planillas
+	PlanillaId string `protobuf:"bytes,3,opt,name=planilla_id,json=planillaId,proto3" json:"planilla_id,omitempty"`
+	// RFC3339; solo trabajadores modificados desde esa fecha
+	UpdatedSince  string `protobuf:"bytes,4,opt,name=updated_since,json=updatedSince,proto3" json:"updated_since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTrabajadoresRequest) Reset() {
+	*x = StreamTrabajadoresRequest{}
+	mi := &file_contratos_trabajadores_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTrabajadoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTrabajadoresRequest) ProtoMessage() {}
+
+func (x *StreamTrabajadoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contratos_trabajadores_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTrabajadoresRequest.ProtoReflect.Descriptor instead.
+func (*StreamTrabajadoresRequest) Descriptor() ([]byte, []int) {
+	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StreamTrabajadoresRequest) GetCompanyCode() string {
+	if x != nil {
+		return x.CompanyCode
+	}
+	return ""
+}
+
+func (x *StreamTrabajadoresRequest) GetSoloActivos() bool {
+	if x != nil {
+		return x.SoloActivos
+	}
+	return false
+}
+
+func (x *StreamTrabajadoresRequest) GetPlanillaId() string {
+	if x != nil {
+		return x.PlanillaId
+	}
+	return ""
+}
+
+func (x *StreamTrabajadoresRequest) GetUpdatedSince() string {
+	if x != nil {
+		return x.UpdatedSince
+	}
+	return ""
+}
+
 type Planillas struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Items         []*Planillas_Item      `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
@@ -119,7 +190,7 @@ type Planillas struct {
 
 func (x *Planillas) Reset() {
 	*x = Planillas{}
-	mi := &file_contratos_trabajadores_proto_msgTypes[2]
+	mi := &file_contratos_trabajadores_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -131,7 +202,7 @@ func (x *Planillas) String() string {
 func (*Planillas) ProtoMessage() {}
 
 func (x *Planillas) ProtoReflect() protoreflect.Message {
-	mi := &file_contratos_trabajadores_proto_msgTypes[2]
+	mi := &file_contratos_trabajadores_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -144,7 +215,7 @@ func (x *Planillas) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Planillas.ProtoReflect.Descriptor instead.
 func (*Planillas) Descriptor() ([]byte, []int) {
-	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{2}
+	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Planillas) GetItems() []*Planillas_Item {
@@ -172,7 +243,7 @@ type SimpleTrabajador struct {
 
 func (x *SimpleTrabajador) Reset() {
 	*x = SimpleTrabajador{}
-	mi := &file_contratos_trabajadores_proto_msgTypes[3]
+	mi := &file_contratos_trabajadores_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -184,7 +255,7 @@ func (x *SimpleTrabajador) String() string {
 func (*SimpleTrabajador) ProtoMessage() {}
 
 func (x *SimpleTrabajador) ProtoReflect() protoreflect.Message {
-	mi := &file_contratos_trabajadores_proto_msgTypes[3]
+	mi := &file_contratos_trabajadores_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -197,7 +268,7 @@ func (x *SimpleTrabajador) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SimpleTrabajador.ProtoReflect.Descriptor instead.
 func (*SimpleTrabajador) Descriptor() ([]byte, []int) {
-	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{3}
+	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *SimpleTrabajador) GetEmpresa() string {
@@ -279,7 +350,7 @@ type RequestDeleteTrabajador struct {
 
 func (x *RequestDeleteTrabajador) Reset() {
 	*x = RequestDeleteTrabajador{}
-	mi := &file_contratos_trabajadores_proto_msgTypes[4]
+	mi := &file_contratos_trabajadores_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -291,7 +362,7 @@ func (x *RequestDeleteTrabajador) String() string {
 func (*RequestDeleteTrabajador) ProtoMessage() {}
 
 func (x *RequestDeleteTrabajador) ProtoReflect() protoreflect.Message {
-	mi := &file_contratos_trabajadores_proto_msgTypes[4]
+	mi := &file_contratos_trabajadores_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -304,7 +375,7 @@ func (x *RequestDeleteTrabajador) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequestDeleteTrabajador.ProtoReflect.Descriptor instead.
 func (*RequestDeleteTrabajador) Descriptor() ([]byte, []int) {
-	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{4}
+	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *RequestDeleteTrabajador) GetCodigos() []string {
@@ -326,7 +397,7 @@ type RequestUpdateTrabajadorIdentityInfo struct {
 
 func (x *RequestUpdateTrabajadorIdentityInfo) Reset() {
 	*x = RequestUpdateTrabajadorIdentityInfo{}
-	mi := &file_contratos_trabajadores_proto_msgTypes[5]
+	mi := &file_contratos_trabajadores_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -338,7 +409,7 @@ func (x *RequestUpdateTrabajadorIdentityInfo) String() string {
 func (*RequestUpdateTrabajadorIdentityInfo) ProtoMessage() {}
 
 func (x *RequestUpdateTrabajadorIdentityInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_contratos_trabajadores_proto_msgTypes[5]
+	mi := &file_contratos_trabajadores_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -351,7 +422,7 @@ func (x *RequestUpdateTrabajadorIdentityInfo) ProtoReflect() protoreflect.Messag
 
 // Deprecated: Use RequestUpdateTrabajadorIdentityInfo.ProtoReflect.Descriptor instead.
 func (*RequestUpdateTrabajadorIdentityInfo) Descriptor() ([]byte, []int) {
-	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{5}
+	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *RequestUpdateTrabajadorIdentityInfo) GetEmpresa() string {
@@ -393,7 +464,7 @@ type RequestUpdateTrabajadorBioData struct {
 
 func (x *RequestUpdateTrabajadorBioData) Reset() {
 	*x = RequestUpdateTrabajadorBioData{}
-	mi := &file_contratos_trabajadores_proto_msgTypes[6]
+	mi := &file_contratos_trabajadores_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -405,7 +476,7 @@ func (x *RequestUpdateTrabajadorBioData) String() string {
 func (*RequestUpdateTrabajadorBioData) ProtoMessage() {}
 
 func (x *RequestUpdateTrabajadorBioData) ProtoReflect() protoreflect.Message {
-	mi := &file_contratos_trabajadores_proto_msgTypes[6]
+	mi := &file_contratos_trabajadores_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -418,7 +489,7 @@ func (x *RequestUpdateTrabajadorBioData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequestUpdateTrabajadorBioData.ProtoReflect.Descriptor instead.
 func (*RequestUpdateTrabajadorBioData) Descriptor() ([]byte, []int) {
-	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{6}
+	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *RequestUpdateTrabajadorBioData) GetEmpresa() string {
@@ -451,7 +522,7 @@ type FastResumenTrabajadores struct {
 
 func (x *FastResumenTrabajadores) Reset() {
 	*x = FastResumenTrabajadores{}
-	mi := &file_contratos_trabajadores_proto_msgTypes[7]
+	mi := &file_contratos_trabajadores_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -463,7 +534,7 @@ func (x *FastResumenTrabajadores) String() string {
 func (*FastResumenTrabajadores) ProtoMessage() {}
 
 func (x *FastResumenTrabajadores) ProtoReflect() protoreflect.Message {
-	mi := &file_contratos_trabajadores_proto_msgTypes[7]
+	mi := &file_contratos_trabajadores_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -476,7 +547,7 @@ func (x *FastResumenTrabajadores) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FastResumenTrabajadores.ProtoReflect.Descriptor instead.
 func (*FastResumenTrabajadores) Descriptor() ([]byte, []int) {
-	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{7}
+	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *FastResumenTrabajadores) GetRecords() []*FastResumenTrabajadores_Trabajador {
@@ -526,13 +597,16 @@ type Trabajadores_Item struct {
 	GradoInstruccion             string                 `protobuf:"bytes,36,opt,name=grado_instruccion,json=gradoInstruccion,proto3" json:"grado_instruccion,omitempty"`
 	FormatoContratoTrabajadors   string                 `protobuf:"bytes,37,opt,name=formato_contrato_trabajadors,json=formatoContratoTrabajadors,proto3" json:"formato_contrato_trabajadors,omitempty"`
 	PlanillaId                   string                 `protobuf:"bytes,38,opt,name=planilla_id,json=planillaId,proto3" json:"planilla_id,omitempty"`
+	CargoCodigo                  string                 `protobuf:"bytes,39,opt,name=cargo_codigo,json=cargoCodigo,proto3" json:"cargo_codigo,omitempty"`
+	Cargo                        string                 `protobuf:"bytes,40,opt,name=cargo,proto3" json:"cargo,omitempty"`
+	FechaIngreso                 string                 `protobuf:"bytes,41,opt,name=fecha_ingreso,json=fechaIngreso,proto3" json:"fecha_ingreso,omitempty"`
 	unknownFields                protoimpl.UnknownFields
 	sizeCache                    protoimpl.SizeCache
 }
 
 func (x *Trabajadores_Item) Reset() {
 	*x = Trabajadores_Item{}
-	mi := &file_contratos_trabajadores_proto_msgTypes[8]
+	mi := &file_contratos_trabajadores_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -544,7 +618,7 @@ func (x *Trabajadores_Item) String() string {
 func (*Trabajadores_Item) ProtoMessage() {}
 
 func (x *Trabajadores_Item) ProtoReflect() protoreflect.Message {
-	mi := &file_contratos_trabajadores_proto_msgTypes[8]
+	mi := &file_contratos_trabajadores_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -826,6 +900,27 @@ func (x *Trabajadores_Item) GetPlanillaId() string {
 	return ""
 }
 
+func (x *Trabajadores_Item) GetCargoCodigo() string {
+	if x != nil {
+		return x.CargoCodigo
+	}
+	return ""
+}
+
+func (x *Trabajadores_Item) GetCargo() string {
+	if x != nil {
+		return x.Cargo
+	}
+	return ""
+}
+
+func (x *Trabajadores_Item) GetFechaIngreso() string {
+	if x != nil {
+		return x.FechaIngreso
+	}
+	return ""
+}
+
 type Planillas_Item struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -836,7 +931,7 @@ type Planillas_Item struct {
 
 func (x *Planillas_Item) Reset() {
 	*x = Planillas_Item{}
-	mi := &file_contratos_trabajadores_proto_msgTypes[9]
+	mi := &file_contratos_trabajadores_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -848,7 +943,7 @@ func (x *Planillas_Item) String() string {
 func (*Planillas_Item) ProtoMessage() {}
 
 func (x *Planillas_Item) ProtoReflect() protoreflect.Message {
-	mi := &file_contratos_trabajadores_proto_msgTypes[9]
+	mi := &file_contratos_trabajadores_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -861,7 +956,7 @@ func (x *Planillas_Item) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Planillas_Item.ProtoReflect.Descriptor instead.
 func (*Planillas_Item) Descriptor() ([]byte, []int) {
-	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{2, 0}
+	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{3, 0}
 }
 
 func (x *Planillas_Item) GetId() string {
@@ -888,7 +983,7 @@ type RequestUpdateTrabajadorBioData_FPItem struct {
 
 func (x *RequestUpdateTrabajadorBioData_FPItem) Reset() {
 	*x = RequestUpdateTrabajadorBioData_FPItem{}
-	mi := &file_contratos_trabajadores_proto_msgTypes[10]
+	mi := &file_contratos_trabajadores_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -900,7 +995,7 @@ func (x *RequestUpdateTrabajadorBioData_FPItem) String() string {
 func (*RequestUpdateTrabajadorBioData_FPItem) ProtoMessage() {}
 
 func (x *RequestUpdateTrabajadorBioData_FPItem) ProtoReflect() protoreflect.Message {
-	mi := &file_contratos_trabajadores_proto_msgTypes[10]
+	mi := &file_contratos_trabajadores_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -913,7 +1008,7 @@ func (x *RequestUpdateTrabajadorBioData_FPItem) ProtoReflect() protoreflect.Mess
 
 // Deprecated: Use RequestUpdateTrabajadorBioData_FPItem.ProtoReflect.Descriptor instead.
 func (*RequestUpdateTrabajadorBioData_FPItem) Descriptor() ([]byte, []int) {
-	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{6, 0}
+	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{7, 0}
 }
 
 func (x *RequestUpdateTrabajadorBioData_FPItem) GetIndex() int64 {
@@ -943,7 +1038,7 @@ type FastResumenTrabajadores_Trabajador struct {
 
 func (x *FastResumenTrabajadores_Trabajador) Reset() {
 	*x = FastResumenTrabajadores_Trabajador{}
-	mi := &file_contratos_trabajadores_proto_msgTypes[11]
+	mi := &file_contratos_trabajadores_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -955,7 +1050,7 @@ func (x *FastResumenTrabajadores_Trabajador) String() string {
 func (*FastResumenTrabajadores_Trabajador) ProtoMessage() {}
 
 func (x *FastResumenTrabajadores_Trabajador) ProtoReflect() protoreflect.Message {
-	mi := &file_contratos_trabajadores_proto_msgTypes[11]
+	mi := &file_contratos_trabajadores_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -968,7 +1063,7 @@ func (x *FastResumenTrabajadores_Trabajador) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use FastResumenTrabajadores_Trabajador.ProtoReflect.Descriptor instead.
 func (*FastResumenTrabajadores_Trabajador) Descriptor() ([]byte, []int) {
-	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{7, 0}
+	return file_contratos_trabajadores_proto_rawDescGZIP(), []int{8, 0}
 }
 
 func (x *FastResumenTrabajadores_Trabajador) GetCodigo() string {
@@ -1012,9 +1107,9 @@ const file_contratos_trabajadores_proto_rawDesc = "" +
 	"\n" +
 	"\x1ccontratos/trabajadores.proto\x12\fcontratos.v1\x1a\x1bgoogle/protobuf/empty.proto\",\n" +
 	"\aRequest\x12!\n" +
-	"\fcompany_code\x18\x01 \x01(\tR\vcompanyCode\"\x84\f\n" +
+	"\fcompany_code\x18\x01 \x01(\tR\vcompanyCode\"\xe2\f\n" +
 	"\fTrabajadores\x125\n" +
-	"\x05items\x18\x01 \x03(\v2\x1f.contratos.v1.Trabajadores.ItemR\x05items\x1a\xbc\v\n" +
+	"\x05items\x18\x01 \x03(\v2\x1f.contratos.v1.Trabajadores.ItemR\x05items\x1a\x9a\f\n" +
 	"\x04Item\x12\x16\n" +
 	"\x06codigo\x18\x01 \x01(\tR\x06codigo\x12'\n" +
 	"\x0fnombre_completo\x18\x02 \x01(\tR\x0enombreCompleto\x12\x1f\n" +
@@ -1058,7 +1153,16 @@ const file_contratos_trabajadores_proto_rawDesc = "" +
 	"\x11grado_instruccion\x18$ \x01(\tR\x10gradoInstruccion\x12@\n" +
 	"\x1cformato_contrato_trabajadors\x18% \x01(\tR\x1aformatoContratoTrabajadors\x12\x1f\n" +
 	"\vplanilla_id\x18& \x01(\tR\n" +
-	"planillaId\"y\n" +
+	"planillaId\x12!\n" +
+	"\fcargo_codigo\x18' \x01(\tR\vcargoCodigo\x12\x14\n" +
+	"\x05cargo\x18( \x01(\tR\x05cargo\x12#\n" +
+	"\rfecha_ingreso\x18) \x01(\tR\ffechaIngreso\"\xa7\x01\n" +
+	"\x19StreamTrabajadoresRequest\x12!\n" +
+	"\fcompany_code\x18\x01 \x01(\tR\vcompanyCode\x12!\n" +
+	"\fsolo_activos\x18\x02 \x01(\bR\vsoloActivos\x12\x1f\n" +
+	"\vplanilla_id\x18\x03 \x01(\tR\n" +
+	"planillaId\x12#\n" +
+	"\rupdated_since\x18\x04 \x01(\tR\fupdatedSince\"y\n" +
 	"\tPlanillas\x122\n" +
 	"\x05items\x18\x01 \x03(\v2\x1c.contratos.v1.Planillas.ItemR\x05items\x1a8\n" +
 	"\x04Item\x12\x0e\n" +
@@ -1101,14 +1205,16 @@ const file_contratos_trabajadores_proto_rawDesc = "" +
 	"\x03dni\x18\x02 \x01(\tR\x03dni\x12\x16\n" +
 	"\x06nombre\x18\x03 \x01(\tR\x06nombre\x12)\n" +
 	"\x10apellido_paterno\x18\x04 \x01(\tR\x0fapellidoPaterno\x12)\n" +
-	"\x10apellido_materno\x18\x05 \x01(\tR\x0fapellidoMaterno2\x97\x04\n" +
+	"\x10apellido_materno\x18\x05 \x01(\tR\x0fapellidoMaterno2\xf7\x05\n" +
 	"\x17TrabajadoresGRPCService\x12>\n" +
 	"\fGetPlanillas\x12\x15.contratos.v1.Request\x1a\x17.contratos.v1.Planillas\x12D\n" +
 	"\x0fGetTrabajadores\x12\x15.contratos.v1.Request\x1a\x1a.contratos.v1.Trabajadores\x12N\n" +
 	"\x14FastCreateTrabajador\x12\x1e.contratos.v1.SimpleTrabajador\x1a\x16.google.protobuf.Empty\x12i\n" +
 	"\x1cUpdateTrabajadorIdentityInfo\x121.contratos.v1.RequestUpdateTrabajadorIdentityInfo\x1a\x16.google.protobuf.Empty\x12_\n" +
 	"\x17UpdateTrabajadorBioData\x12,.contratos.v1.RequestUpdateTrabajadorBioData\x1a\x16.google.protobuf.Empty\x12Z\n" +
-	"\x1aGetFastResumenTrabajadores\x12\x15.contratos.v1.Request\x1a%.contratos.v1.FastResumenTrabajadoresBDZBgithub.com/sfperusacdev/identitysdk/grpc/gen/contratos;contratospbb\x06proto3"
+	"\x1aGetFastResumenTrabajadores\x12\x15.contratos.v1.Request\x1a%.contratos.v1.FastResumenTrabajadores\x12`\n" +
+	"\x12StreamTrabajadores\x12'.contratos.v1.StreamTrabajadoresRequest\x1a\x1f.contratos.v1.Trabajadores.Item0\x01\x12|\n" +
+	"\x1dStreamFastResumenTrabajadores\x12'.contratos.v1.StreamTrabajadoresRequest\x1a0.contratos.v1.FastResumenTrabajadores.Trabajador0\x01BDZBgithub.com/sfperusacdev/identitysdk/grpc/gen/contratos;contratospbb\x06proto3"
 
 var (
 	file_contratos_trabajadores_proto_rawDescOnce sync.Once
@@ -1122,41 +1228,46 @@ func file_contratos_trabajadores_proto_rawDescGZIP() []byte {
 	return file_contratos_trabajadores_proto_rawDescData
 }
 
-var file_contratos_trabajadores_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_contratos_trabajadores_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_contratos_trabajadores_proto_goTypes = []any{
 	(*Request)(nil),                               // 0: contratos.v1.Request
 	(*Trabajadores)(nil),                          // 1: contratos.v1.Trabajadores
-	(*Planillas)(nil),                             // 2: contratos.v1.Planillas
-	(*SimpleTrabajador)(nil),                      // 3: contratos.v1.SimpleTrabajador
-	(*RequestDeleteTrabajador)(nil),               // 4: contratos.v1.RequestDeleteTrabajador
-	(*RequestUpdateTrabajadorIdentityInfo)(nil),   // 5: contratos.v1.RequestUpdateTrabajadorIdentityInfo
-	(*RequestUpdateTrabajadorBioData)(nil),        // 6: contratos.v1.RequestUpdateTrabajadorBioData
-	(*FastResumenTrabajadores)(nil),               // 7: contratos.v1.FastResumenTrabajadores
-	(*Trabajadores_Item)(nil),                     // 8: contratos.v1.Trabajadores.Item
-	(*Planillas_Item)(nil),                        // 9: contratos.v1.Planillas.Item
-	(*RequestUpdateTrabajadorBioData_FPItem)(nil), // 10: contratos.v1.RequestUpdateTrabajadorBioData.FPItem
-	(*FastResumenTrabajadores_Trabajador)(nil),    // 11: contratos.v1.FastResumenTrabajadores.Trabajador
-	(*emptypb.Empty)(nil),                         // 12: google.protobuf.Empty
+	(*StreamTrabajadoresRequest)(nil),             // 2: contratos.v1.StreamTrabajadoresRequest
+	(*Planillas)(nil),                             // 3: contratos.v1.Planillas
+	(*SimpleTrabajador)(nil),                      // 4: contratos.v1.SimpleTrabajador
+	(*RequestDeleteTrabajador)(nil),               // 5: contratos.v1.RequestDeleteTrabajador
+	(*RequestUpdateTrabajadorIdentityInfo)(nil),   // 6: contratos.v1.RequestUpdateTrabajadorIdentityInfo
+	(*RequestUpdateTrabajadorBioData)(nil),        // 7: contratos.v1.RequestUpdateTrabajadorBioData
+	(*FastResumenTrabajadores)(nil),               // 8: contratos.v1.FastResumenTrabajadores
+	(*Trabajadores_Item)(nil),                     // 9: contratos.v1.Trabajadores.Item
+	(*Planillas_Item)(nil),                        // 10: contratos.v1.Planillas.Item
+	(*RequestUpdateTrabajadorBioData_FPItem)(nil), // 11: contratos.v1.RequestUpdateTrabajadorBioData.FPItem
+	(*FastResumenTrabajadores_Trabajador)(nil),    // 12: contratos.v1.FastResumenTrabajadores.Trabajador
+	(*emptypb.Empty)(nil),                         // 13: google.protobuf.Empty
 }
 var file_contratos_trabajadores_proto_depIdxs = []int32{
-	8,  // 0: contratos.v1.Trabajadores.items:type_name -> contratos.v1.Trabajadores.Item
-	9,  // 1: contratos.v1.Planillas.items:type_name -> contratos.v1.Planillas.Item
-	10, // 2: contratos.v1.RequestUpdateTrabajadorBioData.data:type_name -> contratos.v1.RequestUpdateTrabajadorBioData.FPItem
-	11, // 3: contratos.v1.FastResumenTrabajadores.records:type_name -> contratos.v1.FastResumenTrabajadores.Trabajador
+	9,  // 0: contratos.v1.Trabajadores.items:type_name -> contratos.v1.Trabajadores.Item
+	10, // 1: contratos.v1.Planillas.items:type_name -> contratos.v1.Planillas.Item
+	11, // 2: contratos.v1.RequestUpdateTrabajadorBioData.data:type_name -> contratos.v1.RequestUpdateTrabajadorBioData.FPItem
+	12, // 3: contratos.v1.FastResumenTrabajadores.records:type_name -> contratos.v1.FastResumenTrabajadores.Trabajador
 	0,  // 4: contratos.v1.TrabajadoresGRPCService.GetPlanillas:input_type -> contratos.v1.Request
 	0,  // 5: contratos.v1.TrabajadoresGRPCService.GetTrabajadores:input_type -> contratos.v1.Request
-	3,  // 6: contratos.v1.TrabajadoresGRPCService.FastCreateTrabajador:input_type -> contratos.v1.SimpleTrabajador
-	5,  // 7: contratos.v1.TrabajadoresGRPCService.UpdateTrabajadorIdentityInfo:input_type -> contratos.v1.RequestUpdateTrabajadorIdentityInfo
-	6,  // 8: contratos.v1.TrabajadoresGRPCService.UpdateTrabajadorBioData:input_type -> contratos.v1.RequestUpdateTrabajadorBioData
+	4,  // 6: contratos.v1.TrabajadoresGRPCService.FastCreateTrabajador:input_type -> contratos.v1.SimpleTrabajador
+	6,  // 7: contratos.v1.TrabajadoresGRPCService.UpdateTrabajadorIdentityInfo:input_type -> contratos.v1.RequestUpdateTrabajadorIdentityInfo
+	7,  // 8: contratos.v1.TrabajadoresGRPCService.UpdateTrabajadorBioData:input_type -> contratos.v1.RequestUpdateTrabajadorBioData
 	0,  // 9: contratos.v1.TrabajadoresGRPCService.GetFastResumenTrabajadores:input_type -> contratos.v1.Request
-	2,  // 10: contratos.v1.TrabajadoresGRPCService.GetPlanillas:output_type -> contratos.v1.Planillas
-	1,  // 11: contratos.v1.TrabajadoresGRPCService.GetTrabajadores:output_type -> contratos.v1.Trabajadores
-	12, // 12: contratos.v1.TrabajadoresGRPCService.FastCreateTrabajador:output_type -> google.protobuf.Empty
-	12, // 13: contratos.v1.TrabajadoresGRPCService.UpdateTrabajadorIdentityInfo:output_type -> google.protobuf.Empty
-	12, // 14: contratos.v1.TrabajadoresGRPCService.UpdateTrabajadorBioData:output_type -> google.protobuf.Empty
-	7,  // 15: contratos.v1.TrabajadoresGRPCService.GetFastResumenTrabajadores:output_type -> contratos.v1.FastResumenTrabajadores
-	10, // [10:16] is the sub-list for method output_type
-	4,  // [4:10] is the sub-list for method input_type
+	2,  // 10: contratos.v1.TrabajadoresGRPCService.StreamTrabajadores:input_type -> contratos.v1.StreamTrabajadoresRequest
+	2,  // 11: contratos.v1.TrabajadoresGRPCService.StreamFastResumenTrabajadores:input_type -> contratos.v1.StreamTrabajadoresRequest
+	3,  // 12: contratos.v1.TrabajadoresGRPCService.GetPlanillas:output_type -> contratos.v1.Planillas
+	1,  // 13: contratos.v1.TrabajadoresGRPCService.GetTrabajadores:output_type -> contratos.v1.Trabajadores
+	13, // 14: contratos.v1.TrabajadoresGRPCService.FastCreateTrabajador:output_type -> google.protobuf.Empty
+	13, // 15: contratos.v1.TrabajadoresGRPCService.UpdateTrabajadorIdentityInfo:output_type -> google.protobuf.Empty
+	13, // 16: contratos.v1.TrabajadoresGRPCService.UpdateTrabajadorBioData:output_type -> google.protobuf.Empty
+	8,  // 17: contratos.v1.TrabajadoresGRPCService.GetFastResumenTrabajadores:output_type -> contratos.v1.FastResumenTrabajadores
+	9,  // 18: contratos.v1.TrabajadoresGRPCService.StreamTrabajadores:output_type -> contratos.v1.Trabajadores.Item
+	12, // 19: contratos.v1.TrabajadoresGRPCService.StreamFastResumenTrabajadores:output_type -> contratos.v1.FastResumenTrabajadores.Trabajador
+	12, // [12:20] is the sub-list for method output_type
+	4,  // [4:12] is the sub-list for method input_type
 	4,  // [4:4] is the sub-list for extension type_name
 	4,  // [4:4] is the sub-list for extension extendee
 	0,  // [0:4] is the sub-list for field type_name
@@ -1173,7 +1284,7 @@ func file_contratos_trabajadores_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_contratos_trabajadores_proto_rawDesc), len(file_contratos_trabajadores_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
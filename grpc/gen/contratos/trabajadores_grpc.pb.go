@@ -21,12 +21,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TrabajadoresGRPCService_GetPlanillas_FullMethodName                 = "/contratos.v1.TrabajadoresGRPCService/GetPlanillas"
-	TrabajadoresGRPCService_GetTrabajadores_FullMethodName              = "/contratos.v1.TrabajadoresGRPCService/GetTrabajadores"
-	TrabajadoresGRPCService_FastCreateTrabajador_FullMethodName         = "/contratos.v1.TrabajadoresGRPCService/FastCreateTrabajador"
-	TrabajadoresGRPCService_UpdateTrabajadorIdentityInfo_FullMethodName = "/contratos.v1.TrabajadoresGRPCService/UpdateTrabajadorIdentityInfo"
-	TrabajadoresGRPCService_UpdateTrabajadorBioData_FullMethodName      = "/contratos.v1.TrabajadoresGRPCService/UpdateTrabajadorBioData"
-	TrabajadoresGRPCService_GetFastResumenTrabajadores_FullMethodName   = "/contratos.v1.TrabajadoresGRPCService/GetFastResumenTrabajadores"
+	TrabajadoresGRPCService_GetPlanillas_FullMethodName                  = "/contratos.v1.TrabajadoresGRPCService/GetPlanillas"
+	TrabajadoresGRPCService_GetTrabajadores_FullMethodName               = "/contratos.v1.TrabajadoresGRPCService/GetTrabajadores"
+	TrabajadoresGRPCService_FastCreateTrabajador_FullMethodName          = "/contratos.v1.TrabajadoresGRPCService/FastCreateTrabajador"
+	TrabajadoresGRPCService_UpdateTrabajadorIdentityInfo_FullMethodName  = "/contratos.v1.TrabajadoresGRPCService/UpdateTrabajadorIdentityInfo"
+	TrabajadoresGRPCService_UpdateTrabajadorBioData_FullMethodName       = "/contratos.v1.TrabajadoresGRPCService/UpdateTrabajadorBioData"
+	TrabajadoresGRPCService_GetFastResumenTrabajadores_FullMethodName    = "/contratos.v1.TrabajadoresGRPCService/GetFastResumenTrabajadores"
+	TrabajadoresGRPCService_StreamTrabajadores_FullMethodName            = "/contratos.v1.TrabajadoresGRPCService/StreamTrabajadores"
+	TrabajadoresGRPCService_StreamFastResumenTrabajadores_FullMethodName = "/contratos.v1.TrabajadoresGRPCService/StreamFastResumenTrabajadores"
 )
 
 // TrabajadoresGRPCServiceClient is the client API for TrabajadoresGRPCService service.
@@ -39,6 +41,9 @@ type TrabajadoresGRPCServiceClient interface {
 	UpdateTrabajadorIdentityInfo(ctx context.Context, in *RequestUpdateTrabajadorIdentityInfo, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	UpdateTrabajadorBioData(ctx context.Context, in *RequestUpdateTrabajadorBioData, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	GetFastResumenTrabajadores(ctx context.Context, in *Request, opts ...grpc.CallOption) (*FastResumenTrabajadores, error)
+	// variantes con streaming para planteles grandes; un mensaje por trabajador
+	StreamTrabajadores(ctx context.Context, in *StreamTrabajadoresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Trabajadores_Item], error)
+	StreamFastResumenTrabajadores(ctx context.Context, in *StreamTrabajadoresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FastResumenTrabajadores_Trabajador], error)
 }
 
 type trabajadoresGRPCServiceClient struct {
@@ -109,6 +114,44 @@ func (c *trabajadoresGRPCServiceClient) GetFastResumenTrabajadores(ctx context.C
 	return out, nil
 }
 
+func (c *trabajadoresGRPCServiceClient) StreamTrabajadores(ctx context.Context, in *StreamTrabajadoresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Trabajadores_Item], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TrabajadoresGRPCService_ServiceDesc.Streams[0], TrabajadoresGRPCService_StreamTrabajadores_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamTrabajadoresRequest, Trabajadores_Item]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrabajadoresGRPCService_StreamTrabajadoresClient = grpc.ServerStreamingClient[Trabajadores_Item]
+
+func (c *trabajadoresGRPCServiceClient) StreamFastResumenTrabajadores(ctx context.Context, in *StreamTrabajadoresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FastResumenTrabajadores_Trabajador], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TrabajadoresGRPCService_ServiceDesc.Streams[1], TrabajadoresGRPCService_StreamFastResumenTrabajadores_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamTrabajadoresRequest, FastResumenTrabajadores_Trabajador]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrabajadoresGRPCService_StreamFastResumenTrabajadoresClient = grpc.ServerStreamingClient[FastResumenTrabajadores_Trabajador]
+
 // TrabajadoresGRPCServiceServer is the server API for TrabajadoresGRPCService service.
 // All implementations must embed UnimplementedTrabajadoresGRPCServiceServer
 // for forward compatibility.
@@ -119,6 +162,9 @@ type TrabajadoresGRPCServiceServer interface {
 	UpdateTrabajadorIdentityInfo(context.Context, *RequestUpdateTrabajadorIdentityInfo) (*emptypb.Empty, error)
 	UpdateTrabajadorBioData(context.Context, *RequestUpdateTrabajadorBioData) (*emptypb.Empty, error)
 	GetFastResumenTrabajadores(context.Context, *Request) (*FastResumenTrabajadores, error)
+	// variantes con streaming para planteles grandes; un mensaje por trabajador
+	StreamTrabajadores(*StreamTrabajadoresRequest, grpc.ServerStreamingServer[Trabajadores_Item]) error
+	StreamFastResumenTrabajadores(*StreamTrabajadoresRequest, grpc.ServerStreamingServer[FastResumenTrabajadores_Trabajador]) error
 	mustEmbedUnimplementedTrabajadoresGRPCServiceServer()
 }
 
@@ -147,6 +193,12 @@ func (UnimplementedTrabajadoresGRPCServiceServer) UpdateTrabajadorBioData(contex
 func (UnimplementedTrabajadoresGRPCServiceServer) GetFastResumenTrabajadores(context.Context, *Request) (*FastResumenTrabajadores, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetFastResumenTrabajadores not implemented")
 }
+func (UnimplementedTrabajadoresGRPCServiceServer) StreamTrabajadores(*StreamTrabajadoresRequest, grpc.ServerStreamingServer[Trabajadores_Item]) error {
+	return status.Error(codes.Unimplemented, "method StreamTrabajadores not implemented")
+}
+func (UnimplementedTrabajadoresGRPCServiceServer) StreamFastResumenTrabajadores(*StreamTrabajadoresRequest, grpc.ServerStreamingServer[FastResumenTrabajadores_Trabajador]) error {
+	return status.Error(codes.Unimplemented, "method StreamFastResumenTrabajadores not implemented")
+}
 func (UnimplementedTrabajadoresGRPCServiceServer) mustEmbedUnimplementedTrabajadoresGRPCServiceServer() {
 }
 func (UnimplementedTrabajadoresGRPCServiceServer) testEmbeddedByValue() {}
@@ -277,6 +329,28 @@ func _TrabajadoresGRPCService_GetFastResumenTrabajadores_Handler(srv interface{}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrabajadoresGRPCService_StreamTrabajadores_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTrabajadoresRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrabajadoresGRPCServiceServer).StreamTrabajadores(m, &grpc.GenericServerStream[StreamTrabajadoresRequest, Trabajadores_Item]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrabajadoresGRPCService_StreamTrabajadoresServer = grpc.ServerStreamingServer[Trabajadores_Item]
+
+func _TrabajadoresGRPCService_StreamFastResumenTrabajadores_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTrabajadoresRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrabajadoresGRPCServiceServer).StreamFastResumenTrabajadores(m, &grpc.GenericServerStream[StreamTrabajadoresRequest, FastResumenTrabajadores_Trabajador]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrabajadoresGRPCService_StreamFastResumenTrabajadoresServer = grpc.ServerStreamingServer[FastResumenTrabajadores_Trabajador]
+
 // TrabajadoresGRPCService_ServiceDesc is the grpc.ServiceDesc for TrabajadoresGRPCService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -309,6 +383,17 @@ var TrabajadoresGRPCService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _TrabajadoresGRPCService_GetFastResumenTrabajadores_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamTrabajadores",
+			Handler:       _TrabajadoresGRPCService_StreamTrabajadores_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamFastResumenTrabajadores",
+			Handler:       _TrabajadoresGRPCService_StreamFastResumenTrabajadores_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "contratos/trabajadores.proto",
 }
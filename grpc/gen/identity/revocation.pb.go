@@ -0,0 +1,249 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v7.35.0
+// source: identity/revocation.proto
+
+package identitypb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RevokedToken struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// unix timestamp del exp del token; 0 usa la retención por defecto
+	Exp           int64 `protobuf:"varint,2,opt,name=exp,proto3" json:"exp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokedToken) Reset() {
+	*x = RevokedToken{}
+	mi := &file_identity_revocation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokedToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokedToken) ProtoMessage() {}
+
+func (x *RevokedToken) ProtoReflect() protoreflect.Message {
+	mi := &file_identity_revocation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokedToken.ProtoReflect.Descriptor instead.
+func (*RevokedToken) Descriptor() ([]byte, []int) {
+	return file_identity_revocation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RevokedToken) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RevokedToken) GetExp() int64 {
+	if x != nil {
+		return x.Exp
+	}
+	return 0
+}
+
+type RevokeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []*RevokedToken        `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	Usernames     []string               `protobuf:"bytes,2,rep,name=usernames,proto3" json:"usernames,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeRequest) Reset() {
+	*x = RevokeRequest{}
+	mi := &file_identity_revocation_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeRequest) ProtoMessage() {}
+
+func (x *RevokeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_identity_revocation_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeRequest.ProtoReflect.Descriptor instead.
+func (*RevokeRequest) Descriptor() ([]byte, []int) {
+	return file_identity_revocation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RevokeRequest) GetTokens() []*RevokedToken {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+func (x *RevokeRequest) GetUsernames() []string {
+	if x != nil {
+		return x.Usernames
+	}
+	return nil
+}
+
+type RevokeResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RevokedTokens    int32                  `protobuf:"varint,1,opt,name=revoked_tokens,json=revokedTokens,proto3" json:"revoked_tokens,omitempty"`
+	RevokedUsernames int32                  `protobuf:"varint,2,opt,name=revoked_usernames,json=revokedUsernames,proto3" json:"revoked_usernames,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RevokeResponse) Reset() {
+	*x = RevokeResponse{}
+	mi := &file_identity_revocation_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeResponse) ProtoMessage() {}
+
+func (x *RevokeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_identity_revocation_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeResponse.ProtoReflect.Descriptor instead.
+func (*RevokeResponse) Descriptor() ([]byte, []int) {
+	return file_identity_revocation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RevokeResponse) GetRevokedTokens() int32 {
+	if x != nil {
+		return x.RevokedTokens
+	}
+	return 0
+}
+
+func (x *RevokeResponse) GetRevokedUsernames() int32 {
+	if x != nil {
+		return x.RevokedUsernames
+	}
+	return 0
+}
+
+var File_identity_revocation_proto protoreflect.FileDescriptor
+
+const file_identity_revocation_proto_rawDesc = "" +
+	"\n" +
+	"\x19identity/revocation.proto\x12\videntity.v1\"0\n" +
+	"\fRevokedToken\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03exp\x18\x02 \x01(\x03R\x03exp\"`\n" +
+	"\rRevokeRequest\x121\n" +
+	"\x06tokens\x18\x01 \x03(\v2\x19.identity.v1.RevokedTokenR\x06tokens\x12\x1c\n" +
+	"\tusernames\x18\x02 \x03(\tR\tusernames\"d\n" +
+	"\x0eRevokeResponse\x12%\n" +
+	"\x0erevoked_tokens\x18\x01 \x01(\x05R\rrevokedTokens\x12+\n" +
+	"\x11revoked_usernames\x18\x02 \x01(\x05R\x10revokedUsernames2V\n" +
+	"\x11RevocationService\x12A\n" +
+	"\x06Revoke\x12\x1a.identity.v1.RevokeRequest\x1a\x1b.identity.v1.RevokeResponseBBZ@github.com/sfperusacdev/identitysdk/grpc/gen/identity;identitypbb\x06proto3"
+
+var (
+	file_identity_revocation_proto_rawDescOnce sync.Once
+	file_identity_revocation_proto_rawDescData []byte
+)
+
+func file_identity_revocation_proto_rawDescGZIP() []byte {
+	file_identity_revocation_proto_rawDescOnce.Do(func() {
+		file_identity_revocation_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_identity_revocation_proto_rawDesc), len(file_identity_revocation_proto_rawDesc)))
+	})
+	return file_identity_revocation_proto_rawDescData
+}
+
+var file_identity_revocation_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_identity_revocation_proto_goTypes = []any{
+	(*RevokedToken)(nil),   // 0: identity.v1.RevokedToken
+	(*RevokeRequest)(nil),  // 1: identity.v1.RevokeRequest
+	(*RevokeResponse)(nil), // 2: identity.v1.RevokeResponse
+}
+var file_identity_revocation_proto_depIdxs = []int32{
+	0, // 0: identity.v1.RevokeRequest.tokens:type_name -> identity.v1.RevokedToken
+	1, // 1: identity.v1.RevocationService.Revoke:input_type -> identity.v1.RevokeRequest
+	2, // 2: identity.v1.RevocationService.Revoke:output_type -> identity.v1.RevokeResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_identity_revocation_proto_init() }
+func file_identity_revocation_proto_init() {
+	if File_identity_revocation_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_identity_revocation_proto_rawDesc), len(file_identity_revocation_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_identity_revocation_proto_goTypes,
+		DependencyIndexes: file_identity_revocation_proto_depIdxs,
+		MessageInfos:      file_identity_revocation_proto_msgTypes,
+	}.Build()
+	File_identity_revocation_proto = out.File
+	file_identity_revocation_proto_goTypes = nil
+	file_identity_revocation_proto_depIdxs = nil
+}
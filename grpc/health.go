@@ -0,0 +1,45 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sfperusacdev/identitysdk/health"
+	gogrpc "google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthService implementa grpc.health.v1.Health; Check sin servicio usa los
+// mismos checks que /readyz y Watch sigue el estado de health.Default.
+type healthService struct {
+	*grpchealth.Server
+}
+
+func registerHealthService(server gogrpc.ServiceRegistrar) {
+	service := &healthService{Server: grpchealth.NewServer()}
+	health.OnReadyChange(func(ready bool) {
+		status := healthpb.HealthCheckResponse_NOT_SERVING
+		if ready {
+			status = healthpb.HealthCheckResponse_SERVING
+		}
+		service.SetServingStatus("", status)
+	})
+	healthpb.RegisterHealthServer(server, service)
+}
+
+func (s *healthService) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if req.GetService() != "" {
+		return s.Server.Check(ctx, req)
+	}
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if health.Readiness(ctx).OK() {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	return &healthpb.HealthCheckResponse{Status: status}, nil
+}
+
+// isHealthMethod indica si la llamada es del protocolo de salud, que no requiere access token.
+func isHealthMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
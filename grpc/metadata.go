@@ -7,4 +7,8 @@ const (
 	MetadataUsername      = "x-username"
 	MetadataSucursal      = "x-sucursal"
 	MetadataRequestOrigin = "x-origin"
+	MetadataTraceParent   = "traceparent"
+	MetadataTraceState    = "tracestate"
+	MetadataRequestID     = "x-request-id"
+	MetadataApiKey        = "x-api-key"
 )
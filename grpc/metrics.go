@@ -0,0 +1,27 @@
+package grpc
+
+import (
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/metrics"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcRequests = metrics.NewCounter(
+		"grpc_server_requests_total",
+		"Llamadas gRPC atendidas por método y código de estado.",
+		"method", "code",
+	)
+	grpcRequestDuration = metrics.NewHistogram(
+		"grpc_server_request_duration_seconds",
+		"Latencia de las llamadas gRPC por método.",
+		nil,
+		"method",
+	)
+)
+
+func observeServerCall(fullMethod string, started time.Time, err error) {
+	grpcRequests.With(fullMethod, status.Code(err).String()).Inc()
+	grpcRequestDuration.With(fullMethod).Observe(time.Since(started).Seconds())
+}
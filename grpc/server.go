@@ -6,12 +6,14 @@ import (
 	"log/slog"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/labstack/gommon/color"
 	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/health"
+	"github.com/sfperusacdev/identitysdk/tracing"
 	"go.uber.org/fx"
 	gogrpc "google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
@@ -28,6 +30,10 @@ var Module = fx.Module("grpc-server",
 	fx.Provide(
 		fx.Annotate(
 			NewServer,
+			fx.ParamTags(ServiceTag, `optional:"true"`),
+		),
+		fx.Annotate(
+			NewMethodCatalog,
 			fx.ParamTags(ServiceTag),
 		),
 	),
@@ -41,43 +47,95 @@ func AsService(fn any) any {
 	)
 }
 
-func NewServer(services []GrpcServiceRegister) *gogrpc.Server {
-	server := gogrpc.NewServer(
-		gogrpc.UnaryInterceptor(accessTokenUnaryInterceptor),
-		gogrpc.StreamInterceptor(accessTokenStreamInterceptor),
-	)
+// NewServer crea el servidor gRPC; sin credenciales escucha en texto plano.
+func NewServer(services []GrpcServiceRegister, creds ServerCredentials) *gogrpc.Server {
+	catalog := NewMethodCatalog(services)
+	options := []gogrpc.ServerOption{
+		gogrpc.UnaryInterceptor(catalog.accessTokenUnaryInterceptor),
+		gogrpc.StreamInterceptor(catalog.accessTokenStreamInterceptor),
+	}
+	if creds != nil {
+		options = append(options, gogrpc.Creds(creds))
+	}
+	server := gogrpc.NewServer(options...)
 	for _, service := range services {
 		service.Register(server)
 	}
+	registerHealthService(server)
 	return server
 }
 
-func accessTokenUnaryInterceptor(
+func (c MethodCatalog) accessTokenUnaryInterceptor(
 	ctx context.Context,
 	req any,
 	info *gogrpc.UnaryServerInfo,
 	handler gogrpc.UnaryHandler,
-) (any, error) {
-	ctx, err := contextWithAccessTokenMetadata(ctx)
+) (res any, err error) {
+	if isHealthMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	ctx, span := startServerSpan(ctx, info.FullMethod)
+	defer func(started time.Time) {
+		endServerSpan(span, err)
+		observeServerCall(info.FullMethod, started, err)
+	}(time.Now())
+
+	ctx, err = c.authenticate(ctx, info.FullMethod)
 	if err != nil {
 		return nil, err
 	}
+	ctx = identitysdk.CtxWithOperation(ctx, info.FullMethod)
+	if err := c.checkPermissions(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
 	return handler(ctx, req)
 }
 
-func accessTokenStreamInterceptor(
+func (c MethodCatalog) accessTokenStreamInterceptor(
 	srv any,
 	stream gogrpc.ServerStream,
 	info *gogrpc.StreamServerInfo,
 	handler gogrpc.StreamHandler,
-) error {
-	ctx, err := contextWithAccessTokenMetadata(stream.Context())
+) (err error) {
+	if isHealthMethod(info.FullMethod) {
+		return handler(srv, stream)
+	}
+	ctx, span := startServerSpan(stream.Context(), info.FullMethod)
+	defer func(started time.Time) {
+		endServerSpan(span, err)
+		observeServerCall(info.FullMethod, started, err)
+	}(time.Now())
+
+	ctx, err = c.authenticate(ctx, info.FullMethod)
 	if err != nil {
 		return err
 	}
+	ctx = identitysdk.CtxWithOperation(ctx, info.FullMethod)
+	if err := c.checkPermissions(ctx, info.FullMethod); err != nil {
+		return err
+	}
 	return handler(srv, &contextServerStream{ServerStream: stream, ctx: ctx})
 }
 
+// startServerSpan continúa la traza recibida en la metadata traceparent.
+func startServerSpan(ctx context.Context, fullMethod string) (context.Context, *tracing.Span) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = tracing.ExtractValues(ctx,
+			firstMetadataValue(md, MetadataTraceParent),
+			firstMetadataValue(md, MetadataTraceState),
+		)
+	}
+	ctx, span := tracing.Start(ctx, fullMethod, tracing.SpanKindServer)
+	span.SetAttribute("rpc.method", fullMethod)
+	return ctx, span
+}
+
+func endServerSpan(span *tracing.Span, err error) {
+	span.SetAttribute("rpc.grpc.status_code", status.Code(err).String())
+	span.SetError(err)
+	span.End()
+}
+
 type contextServerStream struct {
 	gogrpc.ServerStream
 	ctx context.Context
@@ -87,49 +145,6 @@ func (s *contextServerStream) Context() context.Context {
 	return s.ctx
 }
 
-func contextWithAccessTokenMetadata(ctx context.Context) (context.Context, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, status.Error(codes.Unauthenticated, "metadata no encontrada")
-	}
-
-	accessToken := firstMetadataValue(md, MetadataAccessToken)
-	if accessToken == "" {
-		return nil, status.Error(codes.Unauthenticated, "access-token no encontrado")
-	}
-
-	if err := identitysdk.ValidateAccessKey(ctx, accessToken); err != nil {
-		slog.Warn("gRPC access token validation failed", "error", err)
-		return nil, status.Error(codes.Unauthenticated, err.Error())
-	}
-
-	newCtx := ctx
-
-	if empresa := firstMetadataValue(md, MetadataEmpresa); empresa != "" {
-		newCtx = identitysdk.CtxWithDomain(newCtx, empresa)
-	}
-
-	if username := firstMetadataValue(md, MetadataUsername); username != "" {
-		newCtx = identitysdk.CtxWithUsername(newCtx, username)
-	}
-	if sucursal := firstMetadataValue(md, MetadataSucursal); sucursal != "" {
-		newCtx = identitysdk.CtxWithSucursal(newCtx, sucursal)
-	}
-	if origin := firstMetadataValue(md, MetadataRequestOrigin); origin != "" {
-		newCtx = identitysdk.CtxWithRequestOrigin(newCtx, origin)
-	}
-
-	if token := firstMetadataValue(md, MetadataToken); token != "" {
-		var err error
-		newCtx, err = identitysdk.BuildContext(newCtx, token)
-		if err != nil {
-			return nil, status.Error(codes.Unauthenticated, err.Error())
-		}
-	}
-
-	return newCtx, nil
-}
-
 func firstMetadataValue(md metadata.MD, key string) string {
 	for _, value := range md.Get(key) {
 		if value = strings.TrimSpace(value); value != "" {
@@ -164,11 +179,22 @@ func StartServer(lc fx.Lifecycle, server *gogrpc.Server, address ServeAddress) {
 
 			return nil
 		},
-		OnStop: func(ctx context.Context) error {
+		OnStop: health.OnShutdown(health.PhaseServers, "grpc-server", func(ctx context.Context) error {
 			slog.Info("Shutting down gRPC server")
-			server.GracefulStop()
-			slog.Info("gRPC server stopped successfully")
-			return nil
-		},
+			stopped := make(chan struct{})
+			go func() {
+				server.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+				slog.Info("gRPC server stopped successfully")
+				return nil
+			case <-ctx.Done():
+				// las llamadas que no terminaron a tiempo se cortan
+				server.Stop()
+				return ctx.Err()
+			}
+		}),
 	})
 }
@@ -0,0 +1,289 @@
+package grpc_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	identitygrpc "github.com/sfperusacdev/identitysdk/grpc"
+	gogrpc "google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (ca *testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func staticKeyPair(certPEM, keyPEM []byte) identitygrpc.KeyPairSource {
+	return func(context.Context) ([]byte, []byte, error) { return certPEM, keyPEM, nil }
+}
+
+func staticCA(caPEM []byte) identitygrpc.CASource {
+	return func(context.Context) ([]byte, error) { return caPEM, nil }
+}
+
+// handshake devuelve el serial del certificado que presentó el servidor.
+func handshake(t *testing.T, server, client *tls.Config) (int64, error) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		raw, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		conn := tls.Server(raw, server)
+		serverErr <- conn.Handshake()
+		conn.Close()
+	}()
+
+	raw, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := tls.Client(raw, client)
+	defer conn.Close()
+	clientErr := conn.Handshake()
+	if clientErr != nil {
+		// el servidor puede quedar esperando datos del cliente que ya abortó
+		raw.Close()
+	}
+	// con TLS 1.3 el rechazo del certificado de cliente solo lo conoce el servidor
+	if err := <-serverErr; err != nil {
+		return 0, err
+	}
+	if clientErr != nil {
+		return 0, clientErr
+	}
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, 10, x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, 20, x509.ExtKeyUsageClientAuth)
+
+	server, err := identitygrpc.NewTLSProvider(context.Background(), identitygrpc.TLSOptions{
+		KeyPair:    staticKeyPair(serverCert, serverKey),
+		CA:         staticCA(ca.pem),
+		ClientAuth: tls.RequireAndVerifyClientCert,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := identitygrpc.NewTLSProvider(context.Background(), identitygrpc.TLSOptions{
+		KeyPair:    staticKeyPair(clientCert, clientKey),
+		CA:         staticCA(ca.pem),
+		ServerName: "localhost",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handshake(t, server.ServerConfig(), client.ClientConfig()); err != nil {
+		t.Fatalf("expected mTLS handshake to succeed: %v", err)
+	}
+
+	anonymous, err := identitygrpc.NewTLSProvider(context.Background(), identitygrpc.TLSOptions{
+		CA:         staticCA(ca.pem),
+		ServerName: "localhost",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handshake(t, server.ServerConfig(), anonymous.ClientConfig()); err == nil {
+		t.Fatal("expected handshake without client certificate to fail")
+	}
+
+	otherCA := newTestCA(t)
+	untrusted, err := identitygrpc.NewTLSProvider(context.Background(), identitygrpc.TLSOptions{
+		KeyPair:    staticKeyPair(clientCert, clientKey),
+		CA:         staticCA(otherCA.pem),
+		ServerName: "localhost",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handshake(t, server.ServerConfig(), untrusted.ClientConfig()); err == nil {
+		t.Fatal("expected client to reject a server signed by an unknown CA")
+	}
+}
+
+func TestTLSProviderReloadsFiles(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	write := func(serial int64) {
+		certPEM, keyPEM := ca.issue(t, serial, x509.ExtKeyUsageServerAuth)
+		if err := os.WriteFile(certFile, certPEM, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(1)
+
+	server, err := identitygrpc.NewTLSProvider(context.Background(), identitygrpc.TLSOptions{
+		KeyPair: identitygrpc.FileKeyPairSource(certFile, keyFile),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := identitygrpc.NewTLSProvider(context.Background(), identitygrpc.TLSOptions{
+		CA:         staticCA(ca.pem),
+		ServerName: "localhost",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConfig, clientConfig := server.ServerConfig(), client.ClientConfig()
+
+	if serial, err := handshake(t, serverConfig, clientConfig); err != nil || serial != 1 {
+		t.Fatalf("expected serial 1, got %d (%v)", serial, err)
+	}
+
+	write(2)
+	if err := server.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if serial, err := handshake(t, serverConfig, clientConfig); err != nil || serial != 2 {
+		t.Fatalf("expected reloaded serial 2, got %d (%v)", serial, err)
+	}
+
+	if err := os.WriteFile(keyFile, []byte("broken"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Reload(context.Background()); err == nil {
+		t.Fatal("expected reload of an invalid key to fail")
+	}
+	if serial, err := handshake(t, serverConfig, clientConfig); err != nil || serial != 2 {
+		t.Fatalf("expected previous certificate to be kept, got %d (%v)", serial, err)
+	}
+}
+
+func TestNewTLSProviderRequiresCAForClientVerification(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, 1, x509.ExtKeyUsageServerAuth)
+	_, err := identitygrpc.NewTLSProvider(context.Background(), identitygrpc.TLSOptions{
+		KeyPair:    staticKeyPair(certPEM, keyPEM),
+		ClientAuth: tls.RequireAndVerifyClientCert,
+	})
+	if err == nil {
+		t.Fatal("expected error without CA")
+	}
+}
+
+func TestServerOverTLS(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, 10, x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, 20, x509.ExtKeyUsageClientAuth)
+	serverTLS, err := identitygrpc.NewTLSProvider(context.Background(), identitygrpc.TLSOptions{
+		KeyPair:    staticKeyPair(serverCert, serverKey),
+		CA:         staticCA(ca.pem),
+		ClientAuth: tls.RequireAndVerifyClientCert,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientTLS, err := identitygrpc.NewTLSProvider(context.Background(), identitygrpc.TLSOptions{
+		KeyPair: staticKeyPair(clientCert, clientKey),
+		CA:      staticCA(ca.pem),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := identitygrpc.NewServer(nil, serverTLS.ServerCredentials())
+	go server.Serve(listener)
+	defer server.Stop()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	conn, err := gogrpc.NewClient("localhost:"+port, gogrpc.WithTransportCredentials(clientTLS.ClientCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// el protocolo de salud no requiere access token
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("expected health check over mTLS to succeed: %v", err)
+	}
+}
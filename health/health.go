@@ -0,0 +1,258 @@
+// Package health expone el estado de disponibilidad del servicio (/readyz y el
+// protocolo de salud de gRPC) y coordina el apagado ordenado: primero deja de
+// estar listo, luego drena las peticiones en curso y al final detiene los
+// trabajos en segundo plano.
+package health
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// Check devuelve error si la dependencia no está disponible.
+type Check func(ctx context.Context) error
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// CheckTimeout límite de cada check al calcular la disponibilidad.
+const CheckTimeout = 3 * time.Second
+
+type CheckResult struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks,omitempty"`
+}
+
+func (r Report) OK() bool { return r.Status == StatusOK }
+
+// Phase ordena los pasos del apagado; las fases menores se detienen primero.
+type Phase int
+
+const (
+	// PhaseServers deja de aceptar conexiones y espera las peticiones en curso.
+	PhaseServers Phase = iota
+	// PhaseWorkers detiene ejecutores y tareas en segundo plano.
+	PhaseWorkers
+	// PhaseCleanup detiene limpiadores y libera recursos.
+	PhaseCleanup
+)
+
+type shutdownHook struct {
+	phase Phase
+	name  string
+	fn    func(ctx context.Context) error
+
+	once    sync.Once
+	started atomic.Bool
+	err     error
+}
+
+func (h *shutdownHook) run(ctx context.Context) error {
+	h.once.Do(func() {
+		h.started.Store(true)
+		slog.Info("Stopping component", "component", h.name)
+		h.err = h.fn(ctx)
+	})
+	return h.err
+}
+
+type Health struct {
+	mu         sync.RWMutex
+	ready      bool
+	drainDelay time.Duration
+	checks     map[string]Check
+	listeners  []func(ready bool)
+	hooks      []*shutdownHook
+}
+
+func New() *Health {
+	return &Health{checks: map[string]Check{}}
+}
+
+// Default es el estado que exponen /readyz y el servicio de salud de gRPC.
+var Default = New()
+
+// Register agrega o reemplaza un check de disponibilidad.
+func (h *Health) Register(name string, check Check) {
+	if check == nil {
+		slog.Warn("Health check is nil, operation skipped", "name", name)
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// SetReady marca el servicio como listo o no y avisa a los observadores.
+func (h *Health) SetReady(ready bool) {
+	h.mu.Lock()
+	changed := h.ready != ready
+	h.ready = ready
+	listeners := append([]func(bool){}, h.listeners...)
+	h.mu.Unlock()
+	if !changed {
+		return
+	}
+	for _, fn := range listeners {
+		fn(ready)
+	}
+}
+
+func (h *Health) IsReady() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.ready
+}
+
+// OnReadyChange llama fn con el estado actual y en cada cambio posterior.
+func (h *Health) OnReadyChange(fn func(ready bool)) {
+	h.mu.Lock()
+	h.listeners = append(h.listeners, fn)
+	ready := h.ready
+	h.mu.Unlock()
+	fn(ready)
+}
+
+// Readiness ejecuta los checks en paralelo; mientras el servicio no esté listo
+// (arrancando o apagándose) responde no disponible sin ejecutarlos.
+func (h *Health) Readiness(ctx context.Context) Report {
+	h.mu.RLock()
+	ready := h.ready
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	checks := make([]Check, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		checks[i] = h.checks[name]
+	}
+	h.mu.RUnlock()
+
+	if !ready {
+		return Report{Status: StatusUnavailable}
+	}
+	report := Report{Status: StatusOK, Checks: make([]CheckResult, len(names))}
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			report.Checks[i] = runCheck(ctx, names[i], checks[i])
+		}(i)
+	}
+	wg.Wait()
+	for _, result := range report.Checks {
+		if result.Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+	return report
+}
+
+func runCheck(ctx context.Context, name string, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, CheckTimeout)
+	defer cancel()
+	started := time.Now()
+	err := check(ctx)
+	result := CheckResult{Name: name, Status: StatusOK, Duration: time.Since(started).String()}
+	if err != nil {
+		result.Status = StatusUnavailable
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// SetDrainDelay tiempo que se espera tras dejar de estar listo antes de
+// detener los servidores, para que el balanceador deje de enviar tráfico.
+func (h *Health) SetDrainDelay(delay time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drainDelay = delay
+}
+
+// OnShutdown registra fn para la fase indicada y devuelve una función
+// idempotente para usar como OnStop de fx: si Shutdown ya la ejecutó no hace nada.
+func (h *Health) OnShutdown(phase Phase, name string, fn func(ctx context.Context) error) func(context.Context) error {
+	hook := &shutdownHook{phase: phase, name: name, fn: fn}
+	h.mu.Lock()
+	pending := h.hooks[:0]
+	for _, existing := range h.hooks {
+		if !existing.started.Load() {
+			pending = append(pending, existing)
+		}
+	}
+	h.hooks = append(pending, hook)
+	h.mu.Unlock()
+	return hook.run
+}
+
+// Shutdown marca el servicio como no listo, espera el drain delay y detiene
+// los componentes registrados por fase, en el orden en que se registraron.
+func (h *Health) Shutdown(ctx context.Context) error {
+	h.SetReady(false)
+
+	h.mu.Lock()
+	delay := h.drainDelay
+	hooks := h.hooks
+	h.hooks = nil
+	h.mu.Unlock()
+
+	if delay > 0 {
+		slog.Info("Waiting before draining connections", "delay", delay.String())
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
+
+	sort.SliceStable(hooks, func(i, j int) bool { return hooks[i].phase < hooks[j].phase })
+	var errList []error
+	for _, hook := range hooks {
+		if err := hook.run(ctx); err != nil {
+			slog.Error("Error stopping component", "component", hook.name, "error", err)
+			errList = append(errList, err)
+		}
+	}
+	return errors.Join(errList...)
+}
+
+func Register(name string, check Check)    { Default.Register(name, check) }
+func SetReady(ready bool)                  { Default.SetReady(ready) }
+func IsReady() bool                        { return Default.IsReady() }
+func OnReadyChange(fn func(ready bool))    { Default.OnReadyChange(fn) }
+func Readiness(ctx context.Context) Report { return Default.Readiness(ctx) }
+func SetDrainDelay(delay time.Duration)    { Default.SetDrainDelay(delay) }
+func Shutdown(ctx context.Context) error   { return Default.Shutdown(ctx) }
+func OnShutdown(phase Phase, name string, fn func(ctx context.Context) error) func(context.Context) error {
+	return Default.OnShutdown(phase, name, fn)
+}
+
+// Lifecycle marca el servicio como listo cuando terminan los OnStart y ejecuta
+// Shutdown antes que los demás OnStop; debe invocarse después de arrancar los servidores.
+func Lifecycle(lc fx.Lifecycle) {
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			SetReady(true)
+			slog.Info("Service ready")
+			return nil
+		},
+		OnStop: Shutdown,
+	})
+}
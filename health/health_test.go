@@ -0,0 +1,68 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sfperusacdev/identitysdk/health"
+)
+
+func TestReadiness(t *testing.T) {
+	h := health.New()
+	h.Register("database", func(context.Context) error { return nil })
+
+	if report := h.Readiness(context.Background()); report.OK() || len(report.Checks) != 0 {
+		t.Fatalf("expected unavailable without running checks before ready, got %+v", report)
+	}
+
+	h.SetReady(true)
+	if report := h.Readiness(context.Background()); !report.OK() {
+		t.Fatalf("expected ok, got %+v", report)
+	}
+
+	h.Register("identity", func(context.Context) error { return errors.New("connection refused") })
+	report := h.Readiness(context.Background())
+	if report.OK() {
+		t.Fatal("expected unavailable when a check fails")
+	}
+	if len(report.Checks) != 2 || report.Checks[1].Name != "identity" || report.Checks[1].Error != "connection refused" {
+		t.Fatalf("unexpected checks %+v", report.Checks)
+	}
+}
+
+func TestShutdownOrder(t *testing.T) {
+	h := health.New()
+	var steps []string
+	step := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			steps = append(steps, name)
+			return nil
+		}
+	}
+	var readyChanges []bool
+	h.OnReadyChange(func(ready bool) { readyChanges = append(readyChanges, ready) })
+	h.SetReady(true)
+
+	h.OnShutdown(health.PhaseCleanup, "staging", step("staging"))
+	stopExecutor := h.OnShutdown(health.PhaseWorkers, "executor", step("executor"))
+	h.OnShutdown(health.PhaseServers, "http", step("http"))
+	h.OnShutdown(health.PhaseServers, "grpc", step("grpc"))
+
+	if err := h.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := stopExecutor(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"http", "grpc", "executor", "staging"}; !reflect.DeepEqual(steps, expected) {
+		t.Fatalf("expected %v, got %v", expected, steps)
+	}
+	if expected := []bool{false, true, false}; !reflect.DeepEqual(readyChanges, expected) {
+		t.Fatalf("expected ready changes %v, got %v", expected, readyChanges)
+	}
+	if h.IsReady() {
+		t.Fatal("expected not ready after shutdown")
+	}
+}
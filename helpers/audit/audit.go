@@ -0,0 +1,232 @@
+package audit
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+)
+
+// Event es un cambio a registrar; Old y New pueden ser structs, mapas o nil.
+type Event struct {
+	Action    string
+	Entity    string
+	EntityKey string
+	Old       any
+	New       any
+}
+
+// Change es el valor anterior y nuevo de un campo.
+type Change struct {
+	Old any `json:"old,omitempty"`
+	New any `json:"new,omitempty"`
+}
+
+type Entry struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Empresa   string    `json:"empresa"`
+	Sucursal  string    `json:"sucursal"`
+	Username  string    `json:"username"`
+	Origin    string    `json:"origin"`
+	Operation string    `json:"operation"`
+	Action    string    `json:"action"`
+	Entity    string    `json:"entity"`
+	EntityKey string    `json:"entity_key"`
+	Changes   JSON      `gorm:"type:jsonb" json:"changes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (*Entry) TableName() string { return "_audit_log" }
+
+// JSON guarda el diff como jsonb y lo expone sin re-codificar.
+type JSON json.RawMessage
+
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return "{}", nil
+	}
+	return string(j), nil
+}
+
+func (j *JSON) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append((*j)[:0], v...)
+	case string:
+		*j = JSON(v)
+	default:
+		return fmt.Errorf("audit: cannot scan %T into JSON", src)
+	}
+	return nil
+}
+
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("{}"), nil
+	}
+	return j, nil
+}
+
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	*j = append((*j)[:0], data...)
+	return nil
+}
+
+type Filter struct {
+	Empresa   string
+	Username  string
+	Action    string
+	Entity    string
+	EntityKey string
+	From      time.Time
+	To        time.Time
+	Limit     int
+	Offset    int
+}
+
+type Store interface {
+	Write(ctx context.Context, entries []Entry) error
+	Query(ctx context.Context, filter Filter) ([]Entry, error)
+}
+
+var (
+	storeMu sync.RWMutex
+	store   Store
+)
+
+// SetStore define dónde se guardan los registros; Module usa el store de Postgres.
+func SetStore(s Store) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	store = s
+}
+
+func currentStore() Store {
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+	return store
+}
+
+var ErrNoStore = errors.New("audit: store not configured")
+
+// Enabled indica si hay un store configurado; evita leer el estado anterior
+// de una entidad cuando no se va a auditar.
+func Enabled() bool { return currentStore() != nil }
+
+// Record registra los eventos con el usuario, empresa, sucursal, origen y
+// operación del contexto. Si ctx lleva una transacción de StorageManager el
+// registro forma parte de ella.
+func Record(ctx context.Context, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	s := currentStore()
+	if s == nil {
+		return ErrNoStore
+	}
+	entries := make([]Entry, 0, len(events))
+	for _, event := range events {
+		entry, err := newEntry(ctx, event)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+	return s.Write(ctx, entries)
+}
+
+// RecordOrLog es Record para los hooks internos: un fallo de auditoría se
+// registra en el log pero no interrumpe la operación.
+func RecordOrLog(ctx context.Context, events ...Event) {
+	if err := Record(ctx, events...); err != nil && !errors.Is(err, ErrNoStore) {
+		slog.Error("failed to record audit events", "count", len(events), "error", err)
+	}
+}
+
+func newEntry(ctx context.Context, event Event) (Entry, error) {
+	diff, err := Diff(event.Old, event.New)
+	if err != nil {
+		return Entry{}, err
+	}
+	changes, err := json.Marshal(diff)
+	if err != nil {
+		return Entry{}, err
+	}
+	_, sucursal := identitysdk.Empresa_Sucursal(ctx)
+	return Entry{
+		Empresa:   known(identitysdk.Empresa(ctx)),
+		Sucursal:  known(sucursal),
+		Username:  known(identitysdk.Username(ctx)),
+		Origin:    identitysdk.RequestOrigin(ctx),
+		Operation: identitysdk.Operation(ctx),
+		Action:    event.Action,
+		Entity:    event.Entity,
+		EntityKey: event.EntityKey,
+		Changes:   changes,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// known descarta los valores "####...-no-found####" que devuelve identitysdk.
+func known(value string) string {
+	if strings.HasPrefix(value, "####") {
+		return ""
+	}
+	return value
+}
+
+// Diff compara la representación JSON de oldValue y newValue y devuelve los campos que cambiaron.
+func Diff(oldValue, newValue any) (map[string]Change, error) {
+	before, err := toFields(oldValue)
+	if err != nil {
+		return nil, err
+	}
+	after, err := toFields(newValue)
+	if err != nil {
+		return nil, err
+	}
+	diff := map[string]Change{}
+	for key, value := range after {
+		if previous, found := before[key]; !found || !reflect.DeepEqual(previous, value) {
+			diff[key] = Change{Old: before[key], New: value}
+		}
+	}
+	for key, value := range before {
+		if _, found := after[key]; !found {
+			diff[key] = Change{Old: value}
+		}
+	}
+	return diff, nil
+}
+
+func toFields(value any) (map[string]any, error) {
+	if value == nil {
+		return map[string]any{}, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("audit: encoding value: %w", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err == nil {
+		return fields, nil
+	}
+	var scalar any
+	if err := json.Unmarshal(data, &scalar); err != nil {
+		return nil, err
+	}
+	if scalar == nil {
+		return map[string]any{}, nil
+	}
+	return map[string]any{"value": scalar}, nil
+}
@@ -0,0 +1,91 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/helpers/audit"
+)
+
+type memoryStore struct {
+	entries []audit.Entry
+}
+
+func (s *memoryStore) Write(ctx context.Context, entries []audit.Entry) error {
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+func (s *memoryStore) Query(ctx context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	return s.entries, nil
+}
+
+type contrato struct {
+	Codigo string  `json:"codigo"`
+	Sueldo float64 `json:"sueldo"`
+	Cargo  string  `json:"cargo,omitempty"`
+}
+
+func TestDiff(t *testing.T) {
+	diff, err := audit.Diff(
+		contrato{Codigo: "C1", Sueldo: 1000, Cargo: "operario"},
+		contrato{Codigo: "C1", Sueldo: 1200},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff) != 2 || diff["sueldo"].Old != 1000.0 || diff["sueldo"].New != 1200.0 {
+		t.Fatalf("unexpected diff: %+v", diff)
+	}
+	if change := diff["cargo"]; change.Old != "operario" || change.New != nil {
+		t.Fatalf("expected removed field, got %+v", change)
+	}
+
+	diff, _ = audit.Diff("a", "b")
+	if diff["value"].Old != "a" || diff["value"].New != "b" {
+		t.Fatalf("unexpected scalar diff: %+v", diff)
+	}
+}
+
+func TestRecordUsesContext(t *testing.T) {
+	audit.SetStore(nil)
+	if err := audit.Record(context.Background(), audit.Event{Action: "x"}); !errors.Is(err, audit.ErrNoStore) {
+		t.Fatalf("expected ErrNoStore, got %v", err)
+	}
+
+	store := &memoryStore{}
+	audit.SetStore(store)
+	t.Cleanup(func() { audit.SetStore(nil) })
+
+	ctx := identitysdk.CtxWithDomain(context.Background(), "sfperu")
+	ctx = identitysdk.CtxWithUsername(ctx, "auditor")
+	ctx = identitysdk.CtxWithSucursal(ctx, "sfperu.s1")
+	ctx = identitysdk.CtxWithRequestOrigin(ctx, "web")
+	ctx = identitysdk.CtxWithOperation(ctx, "PUT /api/v1/contratos/:codigo")
+
+	err := audit.Record(ctx, audit.Event{
+		Action:    "contrato.update",
+		Entity:    "contratos",
+		EntityKey: "C1",
+		Old:       contrato{Codigo: "C1", Sueldo: 1000},
+		New:       contrato{Codigo: "C1", Sueldo: 1200},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.entries) != 1 {
+		t.Fatalf("expected one entry, got %d", len(store.entries))
+	}
+	entry := store.entries[0]
+	if entry.Empresa != "sfperu" || entry.Username != "auditor" || entry.Sucursal != "s1" ||
+		entry.Origin != "web" || entry.Operation != "PUT /api/v1/contratos/:codigo" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	var changes map[string]audit.Change
+	if err := json.Unmarshal(entry.Changes, &changes); err != nil || len(changes) != 1 {
+		t.Fatalf("unexpected changes %s: %v", entry.Changes, err)
+	}
+}
@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/binds"
+	"github.com/sfperusacdev/identitysdk/httpapi"
+	"github.com/sfperusacdev/identitysdk/permissions"
+	"github.com/user0608/goones/answer"
+	"github.com/user0608/goones/errs"
+)
+
+var PermissionAuditRead = permissions.Describe("auditoria.ver", "Consultar el registro de auditoría")
+
+// QueryHandler lista los registros de auditoría de la empresa de la sesión.
+// Filtros: username, action, entity, entity_key, desde/hasta, limit y offset.
+type QueryHandler struct {
+	httpapi.MethodGet
+	store Store
+}
+
+var _ httpapi.Route = (*QueryHandler)(nil)
+var _ httpapi.PermissionChecker = (*QueryHandler)(nil)
+
+func NewQueryHandler(store Store) *QueryHandler {
+	return &QueryHandler{store: store}
+}
+
+func (h *QueryHandler) GetPath() string {
+	return "/api/v1/_/audit"
+}
+
+func (h *QueryHandler) CheckPermissions() []string {
+	return []string{"g:" + PermissionAuditRead}
+}
+
+func (h *QueryHandler) ResponseSchema() any { return []Entry{} }
+
+func (h *QueryHandler) HandleRequest(c echo.Context) error {
+	ctx := c.Request().Context()
+	filter := Filter{
+		Empresa:   identitysdk.Empresa(ctx),
+		Username:  c.QueryParam("username"),
+		Action:    c.QueryParam("action"),
+		Entity:    c.QueryParam("entity"),
+		EntityKey: c.QueryParam("entity_key"),
+	}
+	if c.QueryParam("desde") != "" || c.QueryParam("hasta") != "" {
+		from, to, err := binds.QueryDateRangeUTC(c)
+		if err != nil {
+			return answer.Err(c, err)
+		}
+		filter.From, filter.To = from, to
+	}
+	for param, target := range map[string]*int{"limit": &filter.Limit, "offset": &filter.Offset} {
+		if raw := c.QueryParam(param); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil {
+				return answer.Err(c, errs.BadRequestf("el parámetro %s debe ser numérico", param))
+			}
+			*target = value
+		}
+	}
+	entries, err := h.store.Query(ctx, filter)
+	if err != nil {
+		return answer.Err(c, err)
+	}
+	return answer.Ok(c, entries)
+}
@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"github.com/sfperusacdev/identitysdk/httpapi"
+	"go.uber.org/fx"
+)
+
+// Module guarda la auditoría en la tabla _audit_log y expone la consulta para administradores.
+var Module = fx.Module(
+	"audit",
+	fx.Provide(
+		fx.Annotate(
+			NewPgStore,
+			fx.As(new(Store)),
+		),
+		httpapi.AsRoute(NewQueryHandler),
+	),
+	fx.Invoke(SetStore),
+)
@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"context"
+
+	connection "github.com/sfperusacdev/identitysdk/pg-connection"
+	"github.com/user0608/goones/errs"
+)
+
+const (
+	defaultQueryLimit = 100
+	maxQueryLimit     = 1000
+
+	writeSavepoint = "_audit_write"
+)
+
+type PgStore struct {
+	manager connection.StorageManager
+	schema  connection.Schema
+}
+
+var _ Store = (*PgStore)(nil)
+
+func NewPgStore(manager connection.StorageManager) *PgStore {
+	return &PgStore{manager: manager}
+}
+
+// auditTable guarda un registro por cambio; los índices cubren el historial
+// de una entidad y las consultas por rango de fechas de cada empresa.
+const auditTable = `
+	CREATE TABLE IF NOT EXISTS _audit_log (
+		id BIGSERIAL PRIMARY KEY,
+		empresa VARCHAR(255) NOT NULL DEFAULT '',
+		sucursal VARCHAR(255) NOT NULL DEFAULT '',
+		username VARCHAR(255) NOT NULL DEFAULT '',
+		origin TEXT NOT NULL DEFAULT '',
+		operation TEXT NOT NULL DEFAULT '',
+		action VARCHAR(255) NOT NULL,
+		entity VARCHAR(255) NOT NULL DEFAULT '',
+		entity_key TEXT NOT NULL DEFAULT '',
+		changes JSONB NOT NULL DEFAULT '{}',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	);
+	CREATE INDEX IF NOT EXISTS _audit_log_entity_idx ON _audit_log (empresa, entity, entity_key);
+	CREATE INDEX IF NOT EXISTS _audit_log_created_idx ON _audit_log (empresa, created_at)`
+
+func (s *PgStore) Write(ctx context.Context, entries []Entry) error {
+	tx := s.manager.Conn(ctx)
+	if tx == nil {
+		return nil // skip
+	}
+	if err := s.schema.Ensure(s.manager, auditTable); err != nil {
+		return err
+	}
+	if !connection.InTx(ctx) {
+		if err := tx.Create(&entries).Error; err != nil {
+			return errs.Pgf(err)
+		}
+		return nil
+	}
+	// en la transacción del llamador un fallo al auditar no debe abortarla
+	if err := tx.SavePoint(writeSavepoint).Error; err != nil {
+		return errs.Pgf(err)
+	}
+	if err := tx.Create(&entries).Error; err != nil {
+		tx.RollbackTo(writeSavepoint)
+		return errs.Pgf(err)
+	}
+	return nil
+}
+
+func (s *PgStore) Query(ctx context.Context, filter Filter) ([]Entry, error) {
+	tx := s.manager.Conn(ctx)
+	if tx == nil {
+		return []Entry{}, nil
+	}
+	if err := s.schema.Ensure(s.manager, auditTable); err != nil {
+		return nil, err
+	}
+	qry := tx.Model(&Entry{}).Where("empresa = ?", filter.Empresa)
+	for column, value := range map[string]string{
+		"username":   filter.Username,
+		"action":     filter.Action,
+		"entity":     filter.Entity,
+		"entity_key": filter.EntityKey,
+	} {
+		if value != "" {
+			qry = qry.Where(column+" = ?", value)
+		}
+	}
+	if !filter.From.IsZero() {
+		qry = qry.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		qry = qry.Where("created_at <= ?", filter.To)
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultQueryLimit
+	}
+	var entries = []Entry{}
+	if err := qry.Order("created_at desc, id desc").
+		Limit(min(limit, maxQueryLimit)).
+		Offset(max(filter.Offset, 0)).
+		Find(&entries).Error; err != nil {
+		return nil, errs.Pgf(err)
+	}
+	return entries, nil
+}
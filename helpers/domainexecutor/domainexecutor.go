@@ -34,6 +34,15 @@
 //   - Cancela tareas en cola.
 //   - Espera a que las tareas en ejecución finalicen.
 //
+// 7. Modo durable (Config.Durable):
+//   - Enqueue guarda la tarea con un tipo registrado y su payload en un TaskStore.
+//   - Resume reanuda al iniciar las tareas pendientes en su orden por dominio.
+//   - Cada réplica retiene sus tareas con un lease; las de una réplica caída se
+//     toman cuando vence.
+//   - Los fallos se reintentan con backoff hasta MaxAttempts y luego pasan a dead_letter.
+//   - Shutdown no pierde tareas: las que no se ejecutaron siguen pendientes en
+//     el store y se liberan para otra réplica.
+//
 // Este patrón permite implementar procesamiento seguro por clave (dominio),
 // evitando condiciones de carrera cuando múltiples operaciones afectan
 // el mismo recurso lógico.
@@ -56,6 +65,8 @@ const (
 	StateFailed    TaskState = "failed"
 	StateTimeout   TaskState = "timeout"
 	StateCancelled TaskState = "cancelled"
+	// StateDeadLetter tarea durable que agotó sus intentos
+	StateDeadLetter TaskState = "dead_letter"
 )
 
 type StateCallback func(state TaskState, err error)
@@ -80,6 +91,15 @@ type Config struct {
 	// QueueCapacity número máximo de tareas que pueden quedar
 	// esperando en la cola por dominio
 	QueueCapacity int
+
+	// Name identifica al executor en las métricas y en el TaskStore; por defecto "default"
+	Name string
+
+	// Durable guarda las tareas encoladas con Enqueue para reanudarlas tras un reinicio
+	Durable DurableConfig
+
+	// Registry donde se publica el estado de las tareas; por defecto DefaultRegistry()
+	Registry *Registry
 }
 
 type DomainExecutor struct {
@@ -92,6 +112,10 @@ type DomainExecutor struct {
 
 	wgTasks   sync.WaitGroup
 	wgDomains sync.WaitGroup
+
+	metrics  executorMetrics
+	durable  *durableState
+	registry *Registry
 }
 
 type domainRunner struct {
@@ -118,11 +142,23 @@ func New(cfg Config) *DomainExecutor {
 	if cfg.QueueCapacity <= 0 {
 		cfg.QueueCapacity = 1
 	}
+	if cfg.Name == "" {
+		cfg.Name = "default"
+	}
+	if cfg.Registry == nil {
+		cfg.Registry = DefaultRegistry()
+	}
+	if cfg.Durable.Store != nil {
+		cfg.Registry.addStore(cfg.Durable.Store)
+	}
 
 	return &DomainExecutor{
-		runners: make(map[string]*domainRunner),
-		cfg:     cfg,
-		stopCh:  make(chan struct{}),
+		runners:  make(map[string]*domainRunner),
+		cfg:      cfg,
+		stopCh:   make(chan struct{}),
+		metrics:  newExecutorMetrics(cfg.Name),
+		durable:  newDurableState(cfg.Durable),
+		registry: cfg.Registry,
 	}
 }
 
@@ -134,11 +170,38 @@ func New(cfg Config) *DomainExecutor {
 // - error retornado por la Task si la ejecución falla
 // - nil si la Task termina correctamente
 func (e *DomainExecutor) Execute(ctx context.Context, domain string, task Task, cb StateCallback) error {
-	runner, err := e.getOrCreate(domain)
-	if err != nil {
+	waitCtx := ctx
+	if e.cfg.MaxWait > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, e.cfg.MaxWait)
+		defer cancel()
+	}
+
+	req := request{
+		ctx:  waitCtx,
+		task: task,
+		done: make(chan error, 1),
+		cb:   cb,
+	}
+	if _, err := e.enqueue(ctx, waitCtx, domain, "", &req); err != nil {
 		return err
 	}
 
+	select {
+	case err := <-req.done:
+		return err
+	case <-waitCtx.Done():
+		if req.cb != nil {
+			req.cb(StateTimeout, waitCtx.Err())
+		}
+		return ErrTimeout
+	}
+}
+
+// Submit encola la tarea y devuelve su ID sin esperar el resultado; el estado y
+// el avance se consultan en el Registry. MaxWait solo limita la espera para
+// encolar y la tarea continúa aunque termine la petición que la originó.
+func (e *DomainExecutor) Submit(ctx context.Context, domain, name string, task Task, cb StateCallback) (string, error) {
 	waitCtx := ctx
 	if e.cfg.MaxWait > 0 {
 		var cancel context.CancelFunc
@@ -147,64 +210,83 @@ func (e *DomainExecutor) Execute(ctx context.Context, domain string, task Task,
 	}
 
 	req := request{
-		ctx:  waitCtx,
+		ctx:  context.WithoutCancel(ctx),
 		task: task,
 		done: make(chan error, 1),
 		cb:   cb,
 	}
+	entry, err := e.enqueue(ctx, waitCtx, domain, name, &req)
+	if err != nil {
+		return "", err
+	}
+	return entry.id, nil
+}
+
+// enqueue pone req en la cola del dominio esperando como máximo hasta que
+// termine waitCtx, y la publica en el Registry si fue aceptada.
+func (e *DomainExecutor) enqueue(ctx, waitCtx context.Context, domain, name string, req *request) (*taskEntry, error) {
+	runner, err := e.getOrCreate(domain)
+	if err != nil {
+		return nil, err
+	}
 
 	select {
 	case <-e.stopCh:
-		return ErrExecutorClosed
+		return nil, ErrExecutorClosed
 	default:
 	}
 
 	select {
 	case <-runner.stop:
-		return ErrDomainClosed
+		return nil, ErrDomainClosed
 	default:
 	}
 
+	cb := req.cb
+	entry := e.registry.newEntry(ctx, TaskInfo{
+		Executor: e.cfg.Name,
+		Domain:   domain,
+		Name:     name,
+		State:    StatePending,
+	})
+	req.cb = entry.callback(cb)
+	req.task = entry.wrap(req.task)
+
+	// se publica antes de encolar para que el runner no actualice un registro ausente
+	e.registry.add(entry)
 	e.wgTasks.Add(1)
 
 	select {
-	case runner.queue <- req:
-
-		if req.cb != nil {
-			req.cb(StatePending, nil)
+	case runner.queue <- *req:
+		e.metrics.queued.Inc()
+		if cb != nil {
+			cb(StatePending, nil)
 		}
-
+		return entry, nil
 	case <-waitCtx.Done():
 		e.wgTasks.Done()
-		if req.cb != nil {
-			req.cb(StateTimeout, waitCtx.Err())
+		e.registry.remove(entry)
+		e.metrics.outcome(StateTimeout)
+		if cb != nil {
+			cb(StateTimeout, waitCtx.Err())
 		}
-		return ErrTimeout
-
+		return nil, ErrTimeout
 	case <-e.stopCh:
 		e.wgTasks.Done()
-		if req.cb != nil {
-			req.cb(StateCancelled, ErrExecutorClosed)
+		e.registry.remove(entry)
+		e.metrics.outcome(StateCancelled)
+		if cb != nil {
+			cb(StateCancelled, ErrExecutorClosed)
 		}
-		return ErrExecutorClosed
-
+		return nil, ErrExecutorClosed
 	case <-runner.stop:
 		e.wgTasks.Done()
-		if req.cb != nil {
-			req.cb(StateCancelled, ErrDomainClosed)
+		e.registry.remove(entry)
+		e.metrics.outcome(StateCancelled)
+		if cb != nil {
+			cb(StateCancelled, ErrDomainClosed)
 		}
-		return ErrDomainClosed
-	}
-
-	select {
-	case err := <-req.done:
-		return err
-
-	case <-waitCtx.Done():
-		if req.cb != nil {
-			req.cb(StateTimeout, waitCtx.Err())
-		}
-		return ErrTimeout
+		return nil, ErrDomainClosed
 	}
 }
 
@@ -237,6 +319,7 @@ func (e *DomainExecutor) Shutdown(ctx context.Context) error {
 
 	select {
 	case <-done:
+		e.releaseLeases(ctx)
 		return nil
 	case <-ctx.Done():
 		return ctx.Err()
@@ -306,12 +389,16 @@ func (e *DomainExecutor) runRunner(domain string, r *domainRunner) {
 
 		case req := <-r.queue:
 			resetTimer()
+			e.metrics.queued.Dec()
+			e.metrics.running.Inc()
 
 			if req.cb != nil && req.ctx.Err() == nil {
 				req.cb(StateRunning, nil)
 			}
 
 			err := req.task(req.ctx)
+			e.metrics.running.Dec()
+			e.metrics.finished(req.ctx, err)
 
 			if req.ctx.Err() == nil {
 				if req.cb != nil {
@@ -337,6 +424,8 @@ func (e *DomainExecutor) cancelQueued(r *domainRunner) {
 	for {
 		select {
 		case req := <-r.queue:
+			e.metrics.queued.Dec()
+			e.metrics.outcome(StateCancelled)
 			if req.cb != nil {
 				req.cb(StateCancelled, ErrDomainClosed)
 			}
@@ -0,0 +1,475 @@
+package domainexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sfperusacdev/identitysdk"
+)
+
+// TaskHandler ejecuta una tarea durable a partir de su payload JSON. El
+// contexto lleva la empresa que encoló la tarea (o el dominio si se encoló sin
+// identidad), TaskID y TaskDomain.
+type TaskHandler func(ctx context.Context, payload json.RawMessage) error
+
+// DurableConfig activa el modo durable cuando Store no es nil.
+type DurableConfig struct {
+	Store TaskStore
+	// MaxAttempts intentos antes de pasar la tarea a dead_letter; por defecto 5
+	MaxAttempts int
+	// InitialBackoff espera tras el primer fallo, se duplica en cada intento; por defecto 1s
+	InitialBackoff time.Duration
+	// MaxBackoff espera máxima entre intentos; por defecto 5m
+	MaxBackoff time.Duration
+	// AttemptTimeout límite de cada intento; 0 sin límite
+	AttemptTimeout time.Duration
+	// Lease tiempo que una réplica retiene sus tareas sin renovarlas; al vencer
+	// otra réplica las toma. Se renueva cada Lease/3; por defecto 1m
+	Lease time.Duration
+}
+
+var (
+	ErrNotDurable      = errors.New("domain executor is not durable")
+	ErrUnknownTaskType = errors.New("task type not registered")
+)
+
+type durableState struct {
+	cfg     DurableConfig
+	resumed atomic.Bool
+	// owner identifica a esta instancia en los leases del store
+	owner     string
+	leaseOnce sync.Once
+
+	mu       sync.Mutex
+	handlers map[string]TaskHandler
+	queues   map[string]*durableQueue
+	// active tareas ya encoladas en este proceso
+	active map[int64]bool
+}
+
+type durableQueue struct {
+	tasks []*durableTask
+}
+
+type durableTask struct {
+	record TaskRecord
+	cb     StateCallback
+	entry  *taskEntry
+	// lost indica que otra réplica tomó la tarea; este proceso ya no la reporta
+	lost bool
+}
+
+func newDurableState(cfg DurableConfig) *durableState {
+	if cfg.Store == nil {
+		return nil
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Minute
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = time.Minute
+	}
+	return &durableState{
+		cfg:      cfg,
+		owner:    uuid.NewString(),
+		handlers: map[string]TaskHandler{},
+		queues:   map[string]*durableQueue{},
+		active:   map[int64]bool{},
+	}
+}
+
+func (d *durableState) handler(taskType string) TaskHandler {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.handlers[taskType]
+}
+
+func (d *durableState) backoff(attempts int) time.Duration {
+	wait := d.cfg.InitialBackoff
+	for i := 1; i < attempts && wait < d.cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.cfg.MaxBackoff)
+}
+
+// Register asocia un tipo de tarea con su handler; debe llamarse antes de Resume.
+func (e *DomainExecutor) Register(taskType string, handler TaskHandler) {
+	if e.durable == nil {
+		slog.Warn("domain executor is not durable, operation skipped")
+		return
+	}
+	if handler == nil {
+		slog.Warn("handler is nil, operation skipped")
+		return
+	}
+	e.durable.mu.Lock()
+	defer e.durable.mu.Unlock()
+	e.durable.handlers[taskType] = handler
+}
+
+// RegisterTask registra un handler tipado; el payload se decodifica desde JSON.
+func RegisterTask[T any](e *DomainExecutor, taskType string, handler func(ctx context.Context, payload T) error) {
+	if handler == nil {
+		slog.Warn("handler is nil, operation skipped")
+		return
+	}
+	e.Register(taskType, func(ctx context.Context, raw json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return err
+		}
+		return handler(ctx, payload)
+	})
+}
+
+// Enqueue guarda la tarea y la encola en su dominio sin esperar el resultado.
+// cb recibe el estado cada vez que se actualiza el registro: pending (también
+// al programar un reintento, con el error del intento), running, completed o
+// dead_letter.
+func (e *DomainExecutor) Enqueue(ctx context.Context, domain, taskType string, payload any, cb StateCallback) (TaskRecord, error) {
+	if e.durable == nil {
+		return TaskRecord{}, ErrNotDurable
+	}
+	if e.durable.handler(taskType) == nil {
+		return TaskRecord{}, fmt.Errorf("%w: %s", ErrUnknownTaskType, taskType)
+	}
+	select {
+	case <-e.stopCh:
+		return TaskRecord{}, ErrExecutorClosed
+	default:
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return TaskRecord{}, err
+	}
+	record := TaskRecord{
+		Executor: e.cfg.Name,
+		Domain:   domain,
+		Empresa:  empresa(ctx),
+		Type:     taskType,
+		Payload:  raw,
+		State:    StatePending,
+		RunAt:    time.Now(),
+		Owner:    e.durable.owner,
+	}
+	record.LockedUntil = record.RunAt.Add(e.durable.cfg.Lease)
+	if err := e.durable.cfg.Store.Insert(ctx, &record); err != nil {
+		return TaskRecord{}, err
+	}
+	e.startLeases()
+	task := e.track(record, cb)
+	task.report()
+	e.schedule(task)
+	return record, nil
+}
+
+// Resume toma del store las tareas pendientes cuyo lease venció y las ejecuta
+// en el orden en que fueron encoladas. Las que quedaron en running por una
+// caída se reintentan y el intento interrumpido cuenta en Attempts. Se llama
+// una vez al iniciar, antes de encolar tareas nuevas; después las tareas que
+// abandone otra réplica se toman al vencer su lease.
+func (e *DomainExecutor) Resume(ctx context.Context) error {
+	if e.durable == nil {
+		return ErrNotDurable
+	}
+	if !e.durable.resumed.CompareAndSwap(false, true) {
+		return nil
+	}
+	records, err := e.durable.cfg.Store.Claim(ctx, e.cfg.Name, e.durable.owner, e.durable.cfg.Lease)
+	if err != nil {
+		e.durable.resumed.Store(false)
+		return err
+	}
+	e.adopt(records)
+	e.startLeases()
+	return nil
+}
+
+// adopt encola las tareas tomadas del store que aún no corren en este proceso.
+func (e *DomainExecutor) adopt(records []TaskRecord) {
+	for _, record := range records {
+		e.durable.mu.Lock()
+		active := e.durable.active[record.ID]
+		e.durable.mu.Unlock()
+		if active {
+			continue
+		}
+		record.State = StatePending
+		task := e.track(record, nil)
+		task.report()
+		e.schedule(task)
+	}
+}
+
+// startLeases renueva los leases de este proceso y toma las tareas abandonadas
+// por otras réplicas hasta Shutdown.
+func (e *DomainExecutor) startLeases() {
+	e.durable.leaseOnce.Do(func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if e.stopped {
+			return
+		}
+		e.wgDomains.Add(1)
+		go e.renewLeases()
+	})
+}
+
+func (e *DomainExecutor) renewLeases() {
+	defer e.wgDomains.Done()
+	d := e.durable
+	ticker := time.NewTicker(d.cfg.Lease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.stopCh:
+			return
+		case <-ticker.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), d.cfg.Lease/3)
+		if err := d.cfg.Store.Renew(ctx, e.cfg.Name, d.owner, d.cfg.Lease); err != nil {
+			slog.Error("error renewing domain task leases", "error", err, "executor", e.cfg.Name)
+		}
+		records, err := d.cfg.Store.Claim(ctx, e.cfg.Name, d.owner, d.cfg.Lease)
+		cancel()
+		if err != nil {
+			slog.Error("error claiming domain tasks", "error", err, "executor", e.cfg.Name)
+			continue
+		}
+		e.adopt(records)
+	}
+}
+
+// releaseLeases suelta las tareas que no llegaron a ejecutarse para que otra
+// réplica no espere a que venza el lease.
+func (e *DomainExecutor) releaseLeases(ctx context.Context) {
+	if e.durable == nil {
+		return
+	}
+	if err := e.durable.cfg.Store.Release(ctx, e.cfg.Name, e.durable.owner); err != nil {
+		slog.Error("error releasing domain task leases", "error", err, "executor", e.cfg.Name)
+	}
+}
+
+// track publica la tarea en el Registry con el ID del store.
+func (e *DomainExecutor) track(record TaskRecord, cb StateCallback) *durableTask {
+	entry := e.registry.newEntry(context.Background(), TaskInfo{
+		ID:        strconv.FormatInt(record.ID, 10),
+		Executor:  record.Executor,
+		Domain:    record.Domain,
+		Empresa:   record.Empresa,
+		Name:      record.Type,
+		State:     record.State,
+		CreatedAt: record.CreatedAt,
+	})
+	e.registry.add(entry)
+	return &durableTask{record: record, cb: cb, entry: entry}
+}
+
+// report notifica el estado guardado al Registry y al callback.
+func (t *durableTask) report() {
+	t.entry.setRecord(t.record)
+	if t.cb != nil {
+		t.cb(t.record.State, t.record.Err())
+	}
+}
+
+func (e *DomainExecutor) schedule(task *durableTask) {
+	d := e.durable
+	domain := task.record.Domain
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.active[task.record.ID] = true
+	if q, ok := d.queues[domain]; ok {
+		q.tasks = append(q.tasks, task)
+		return
+	}
+
+	e.mu.Lock()
+	if e.stopped {
+		e.mu.Unlock()
+		return
+	}
+	e.wgDomains.Add(1)
+	e.mu.Unlock()
+
+	q := &durableQueue{tasks: []*durableTask{task}}
+	d.queues[domain] = q
+	go e.runDurable(domain, q)
+}
+
+// runDurable ejecuta las tareas durables de un dominio una a una; un reintento
+// pendiente bloquea a las siguientes para conservar el orden.
+func (e *DomainExecutor) runDurable(domain string, q *durableQueue) {
+	defer e.wgDomains.Done()
+	d := e.durable
+	for {
+		d.mu.Lock()
+		if len(q.tasks) == 0 {
+			delete(d.queues, domain)
+			d.mu.Unlock()
+			return
+		}
+		task := q.tasks[0]
+		d.mu.Unlock()
+
+		if wait := time.Until(task.record.RunAt); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-e.stopCh:
+				timer.Stop()
+				return
+			}
+		}
+
+		if !e.attempt(task) {
+			return
+		}
+		if task.lost || task.record.State != StatePending {
+			d.mu.Lock()
+			q.tasks = q.tasks[1:]
+			delete(d.active, task.record.ID)
+			d.mu.Unlock()
+		}
+	}
+}
+
+// attempt ejecuta un intento en el runner del dominio y guarda el resultado.
+// Devuelve false si el executor se cerró antes de ejecutarlo; la tarea queda
+// pendiente en el store para el siguiente Resume. Si el lease se perdió la
+// tarea se abandona sin reportar: la réplica que la tomó es la dueña.
+func (e *DomainExecutor) attempt(task *durableTask) bool {
+	d := e.durable
+	record := &task.record
+	handler := d.handler(record.Type)
+
+	ran, err := e.run(record.Domain, func(ctx context.Context) error {
+		record.State = StateRunning
+		record.Attempts++
+		if err := d.cfg.Store.Update(ctx, *record); err != nil {
+			if errors.Is(err, ErrLeaseLost) {
+				return err
+			}
+			slog.Error("error saving domain task state", "error", err, "task_id", record.ID)
+		}
+		task.report()
+		if handler == nil {
+			return fmt.Errorf("%w: %s", ErrUnknownTaskType, record.Type)
+		}
+		if d.cfg.AttemptTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, d.cfg.AttemptTimeout)
+			defer cancel()
+		}
+		return handler(task.context(ctx), record.Payload)
+	})
+	if !ran {
+		return false
+	}
+	if errors.Is(err, ErrLeaseLost) {
+		e.abandon(task)
+		return true
+	}
+
+	switch {
+	case err == nil:
+		record.State = StateCompleted
+		record.LastError = ""
+	case handler == nil || record.Attempts >= d.cfg.MaxAttempts:
+		record.State = StateDeadLetter
+		record.LastError = err.Error()
+	default:
+		record.State = StatePending
+		record.LastError = err.Error()
+		record.RunAt = time.Now().Add(d.backoff(record.Attempts))
+	}
+	if err := d.cfg.Store.Update(context.Background(), *record); err != nil {
+		if errors.Is(err, ErrLeaseLost) {
+			e.abandon(task)
+			return true
+		}
+		slog.Error("error saving domain task state", "error", err, "task_id", record.ID)
+	}
+	if record.State == StateDeadLetter {
+		e.metrics.outcome(StateDeadLetter)
+	}
+	task.report()
+	return true
+}
+
+// context arma el contexto del handler con la identidad de la tarea.
+func (t *durableTask) context(ctx context.Context) context.Context {
+	empresa := t.record.Empresa
+	if empresa == "" {
+		empresa = t.record.Domain
+	}
+	ctx = identitysdk.CtxWithDomain(ctx, empresa)
+	return context.WithValue(ctx, taskEntryKey{}, t.entry)
+}
+
+// abandon deja de seguir una tarea cuyo lease tomó otra réplica; el Registry
+// la consulta desde entonces en el store.
+func (e *DomainExecutor) abandon(task *durableTask) {
+	slog.Warn("domain task lease lost, task left to its new owner", "task_id", task.record.ID, "executor", e.cfg.Name)
+	task.lost = true
+	e.registry.remove(task.entry)
+}
+
+// run ejecuta task en el runner del dominio sin límite de espera: las tareas
+// durables no tienen un llamador esperando. Si el runner fue desalojado antes
+// de tomar la tarea se vuelve a encolar en uno nuevo.
+func (e *DomainExecutor) run(domain string, task Task) (bool, error) {
+	for {
+		runner, err := e.getOrCreate(domain)
+		if err != nil {
+			return false, err
+		}
+		ran := false
+		req := request{
+			ctx: context.Background(),
+			task: func(ctx context.Context) error {
+				ran = true
+				return task(ctx)
+			},
+			done: make(chan error, 1),
+		}
+
+		e.wgTasks.Add(1)
+		select {
+		case runner.queue <- req:
+			e.metrics.queued.Inc()
+		case <-e.stopCh:
+			e.wgTasks.Done()
+			return false, ErrExecutorClosed
+		case <-runner.stop:
+			e.wgTasks.Done()
+			continue
+		}
+
+		err = <-req.done
+		if ran {
+			return true, err
+		}
+		select {
+		case <-e.stopCh:
+			return false, ErrExecutorClosed
+		default:
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package domainexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+)
+
+type durablePayload struct {
+	Value string `json:"value"`
+}
+
+func newDurable(store TaskStore) *DomainExecutor {
+	return New(Config{
+		MaxWait:       time.Second,
+		QueueCapacity: 1,
+		Name:          "test",
+		Durable: DurableConfig{
+			Store:          store,
+			MaxAttempts:    3,
+			InitialBackoff: 10 * time.Millisecond,
+		},
+	})
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not reached")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDurableResumeKeepsDomainOrder(t *testing.T) {
+	store := NewMemoryTaskStore()
+	seed := []struct {
+		Domain, Value string
+		State         TaskState
+		Attempts      int
+	}{
+		{Domain: "a", Value: "a1", State: StateRunning, Attempts: 1},
+		{Domain: "b", Value: "b1", State: StatePending},
+		{Domain: "a", Value: "a2", State: StatePending},
+		{Domain: "a", Value: "done", State: StateCompleted},
+		{Domain: "a", Value: "a3", State: StatePending},
+	}
+	for _, s := range seed {
+		payload, _ := json.Marshal(durablePayload{Value: s.Value})
+		record := TaskRecord{Executor: "test", Domain: s.Domain, Type: "recalcular", Payload: payload, State: s.State, Attempts: s.Attempts}
+		if err := store.Insert(context.Background(), &record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exec := newDurable(store)
+	var mu sync.Mutex
+	got := map[string][]string{}
+	RegisterTask(exec, "recalcular", func(ctx context.Context, payload durablePayload) error {
+		mu.Lock()
+		defer mu.Unlock()
+		domain := TaskDomain(ctx)
+		if empresa := identitysdk.Empresa(ctx); empresa != domain {
+			t.Errorf("expected handler empresa %q, got %q", domain, empresa)
+		}
+		got[domain] = append(got[domain], payload.Value)
+		return nil
+	})
+	if err := exec.Resume(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, func() bool {
+		pending, _ := store.Pending(context.Background(), "test")
+		return len(pending) == 0
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got["a"]) != 3 || got["a"][0] != "a1" || got["a"][1] != "a2" || got["a"][2] != "a3" || len(got["b"]) != 1 {
+		t.Fatalf("unexpected execution order %v", got)
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if record := store.records[1]; record.State != StateCompleted || record.Attempts != 2 {
+		t.Fatalf("expected interrupted task to count its attempt, got %+v", record)
+	}
+}
+
+func TestDurableRetriesThenDeadLetter(t *testing.T) {
+	store := NewMemoryTaskStore()
+	exec := newDurable(store)
+	defer exec.Shutdown(context.Background())
+
+	var mu sync.Mutex
+	var ran []string
+	exec.Register("falla", func(ctx context.Context, payload json.RawMessage) error {
+		mu.Lock()
+		ran = append(ran, "falla")
+		mu.Unlock()
+		return errors.New("sin conexión")
+	})
+	RegisterTask(exec, "ok", func(ctx context.Context, payload durablePayload) error {
+		mu.Lock()
+		ran = append(ran, payload.Value)
+		mu.Unlock()
+		return nil
+	})
+
+	var states []TaskState
+	var lastErr error
+	first, err := exec.Enqueue(context.Background(), "empresa", "falla", nil, func(state TaskState, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		states = append(states, state)
+		lastErr = err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := exec.Enqueue(context.Background(), "empresa", "ok", durablePayload{Value: "siguiente"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return store.records[second.ID].State == StateCompleted
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []TaskState{StatePending, StateRunning, StatePending, StateRunning, StatePending, StateRunning, StateDeadLetter}
+	if len(states) != len(want) {
+		t.Fatalf("expected states %v, got %v", want, states)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Fatalf("expected states %v, got %v", want, states)
+		}
+	}
+	if lastErr == nil || lastErr.Error() != "sin conexión" {
+		t.Fatalf("expected last error to be reported, got %v", lastErr)
+	}
+	if len(ran) != 4 || ran[3] != "siguiente" {
+		t.Fatalf("expected the next task to wait for retries, got %v", ran)
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if record := store.records[first.ID]; record.State != StateDeadLetter || record.Attempts != 3 {
+		t.Fatalf("unexpected dead letter record %+v", record)
+	}
+}
+
+func TestDurableShutdownKeepsPendingTasks(t *testing.T) {
+	store := NewMemoryTaskStore()
+	exec := newDurable(store)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	exec.Register("lenta", func(ctx context.Context, payload json.RawMessage) error {
+		close(started)
+		<-release
+		return nil
+	})
+	exec.Register("pendiente", func(ctx context.Context, payload json.RawMessage) error { return nil })
+
+	if _, err := exec.Enqueue(context.Background(), "a", "lenta", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	pending, err := exec.Enqueue(context.Background(), "a", "pendiente", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- exec.Shutdown(context.Background()) }()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	records, _ := store.Pending(context.Background(), "test")
+	if len(records) != 1 || records[0].ID != pending.ID {
+		t.Fatalf("expected only the unexecuted task to remain pending, got %+v", records)
+	}
+
+	restarted := newDurable(store)
+	defer restarted.Shutdown(context.Background())
+	states := make(chan TaskState, 4)
+	restarted.Register("pendiente", func(ctx context.Context, payload json.RawMessage) error {
+		states <- StateCompleted
+		return nil
+	})
+	if err := restarted.Resume(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-states:
+	case <-time.After(time.Second):
+		t.Fatal("expected pending task to be resumed")
+	}
+}
+
+func TestDurableTakesOverExpiredLeases(t *testing.T) {
+	store := NewMemoryTaskStore()
+	// tarea de una réplica caída y la siguiente de su dominio
+	for _, value := range []string{"a1", "a2"} {
+		payload, _ := json.Marshal(durablePayload{Value: value})
+		record := TaskRecord{Executor: "test", Domain: "a", Type: "recalcular", Payload: payload, State: StateRunning}
+		if err := store.Insert(context.Background(), &record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.mu.Lock()
+	held := store.records[1]
+	held.Owner, held.LockedUntil = "caida", time.Now().Add(100*time.Millisecond)
+	store.records[1] = held
+	store.mu.Unlock()
+
+	exec := New(Config{
+		MaxWait:       time.Second,
+		QueueCapacity: 1,
+		Name:          "test",
+		Durable:       DurableConfig{Store: store, Lease: 30 * time.Millisecond},
+	})
+	var mu sync.Mutex
+	var got []string
+	RegisterTask(exec, "recalcular", func(ctx context.Context, payload durablePayload) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, payload.Value)
+		return nil
+	})
+	if err := exec.Resume(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	if len(got) != 0 {
+		mu.Unlock()
+		t.Fatalf("tasks held by another replica must wait for its lease, ran %v", got)
+	}
+	mu.Unlock()
+
+	waitFor(t, func() bool {
+		pending, _ := store.Pending(context.Background(), "test")
+		return len(pending) == 0
+	})
+	if err := exec.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 2 || got[0] != "a1" || got[1] != "a2" {
+		t.Fatalf("unexpected execution order %v", got)
+	}
+}
+
+func TestDurableStopsReportingWhenLeaseIsLost(t *testing.T) {
+	store := NewMemoryTaskStore()
+	exec := newDurable(store)
+	defer exec.Shutdown(context.Background())
+
+	started := make(chan string, 1)
+	release := make(chan struct{})
+	exec.Register("recalcular", func(ctx context.Context, payload json.RawMessage) error {
+		started <- identitysdk.Empresa(ctx)
+		<-release
+		return nil
+	})
+	var mu sync.Mutex
+	var states []TaskState
+	ctx := identitysdk.CtxWithDomain(context.Background(), "sfperu")
+	record, err := exec.Enqueue(ctx, "planilla", "recalcular", nil, func(state TaskState, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		states = append(states, state)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empresa := <-started; empresa != "sfperu" {
+		t.Fatalf("expected the enqueuing empresa in the handler, got %q", empresa)
+	}
+
+	// otra réplica toma la tarea mientras el intento sigue en curso
+	if err := store.Release(context.Background(), "test", exec.durable.owner); err != nil {
+		t.Fatal(err)
+	}
+	if claimed, err := store.Claim(context.Background(), "test", "otra", time.Minute); err != nil || len(claimed) != 1 {
+		t.Fatalf("expected the other replica to claim the task, got %v %v", claimed, err)
+	}
+	close(release)
+
+	waitFor(t, func() bool {
+		_, found := exec.registry.Get(strconv.FormatInt(record.ID, 10))
+		return !found
+	})
+	stored, err := store.Get(context.Background(), record.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.State != StateRunning || stored.Owner != "otra" {
+		t.Fatalf("the previous owner must not overwrite the task, got %+v", stored)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, state := range states {
+		if state == StateCompleted {
+			t.Fatalf("lost task must not be reported completed, got %v", states)
+		}
+	}
+}
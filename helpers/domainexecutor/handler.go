@@ -0,0 +1,67 @@
+package domainexecutor
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/httpapi"
+	"github.com/user0608/goones/answer"
+	"github.com/user0608/goones/errs"
+)
+
+// ListTasksHandler lista las tareas recientes de la empresa de la sesión que
+// conoce esta réplica. Filtros: domain y state.
+type ListTasksHandler struct {
+	httpapi.MethodGet
+	registry *Registry
+}
+
+var _ httpapi.Route = (*ListTasksHandler)(nil)
+
+func NewListTasksHandler(registry *Registry) *ListTasksHandler {
+	return &ListTasksHandler{registry: registry}
+}
+
+func (h *ListTasksHandler) GetPath() string {
+	return "/api/v1/_/tasks"
+}
+
+func (h *ListTasksHandler) ResponseSchema() any { return []TaskInfo{} }
+
+func (h *ListTasksHandler) HandleRequest(c echo.Context) error {
+	return answer.Ok(c, h.registry.List(TaskFilter{
+		Empresa: identitysdk.Empresa(c.Request().Context()),
+		Domain:  c.QueryParam("domain"),
+		State:   TaskState(c.QueryParam("state")),
+	}))
+}
+
+// GetTaskHandler devuelve el estado y el avance de una tarea de la empresa de
+// la sesión. Una tarea durable de otra réplica se lee del TaskStore, sin avance.
+type GetTaskHandler struct {
+	httpapi.MethodGet
+	registry *Registry
+}
+
+var _ httpapi.Route = (*GetTaskHandler)(nil)
+
+func NewGetTaskHandler(registry *Registry) *GetTaskHandler {
+	return &GetTaskHandler{registry: registry}
+}
+
+func (h *GetTaskHandler) GetPath() string {
+	return "/api/v1/_/tasks/:id"
+}
+
+func (h *GetTaskHandler) ResponseSchema() any { return TaskInfo{} }
+
+func (h *GetTaskHandler) HandleRequest(c echo.Context) error {
+	id := c.Param("id")
+	task, ok, err := h.registry.Lookup(c.Request().Context(), id)
+	if err != nil {
+		return answer.Err(c, err)
+	}
+	if !ok || task.Empresa != identitysdk.Empresa(c.Request().Context()) {
+		return answer.Err(c, errs.NotFoundf("tarea %s no encontrada", id))
+	}
+	return answer.Ok(c, task)
+}
@@ -0,0 +1,56 @@
+package domainexecutor
+
+import (
+	"context"
+
+	"github.com/sfperusacdev/identitysdk/metrics"
+)
+
+var (
+	queuedTasks = metrics.NewGauge(
+		"domainexecutor_queued_tasks",
+		"Tareas en cola esperando a su runner de dominio.",
+		"executor",
+	)
+	runningTasks = metrics.NewGauge(
+		"domainexecutor_running_tasks",
+		"Tareas en ejecución.",
+		"executor",
+	)
+	tasksTotal = metrics.NewCounter(
+		"domainexecutor_tasks_total",
+		"Tareas terminadas por resultado (completed, failed, timeout, cancelled, dead_letter).",
+		"executor", "outcome",
+	)
+)
+
+type executorMetrics struct {
+	name    string
+	queued  metrics.Gauge
+	running metrics.Gauge
+}
+
+func newExecutorMetrics(name string) executorMetrics {
+	return executorMetrics{
+		name:    name,
+		queued:  queuedTasks.With(name),
+		running: runningTasks.With(name),
+	}
+}
+
+func (m executorMetrics) outcome(state TaskState) {
+	tasksTotal.With(m.name, string(state)).Inc()
+}
+
+// finished registra el resultado de una tarea ejecutada; si el llamador ya
+// dejó de esperar se cuenta como timeout.
+func (m executorMetrics) finished(ctx context.Context, err error) {
+	switch {
+	case ctx.Err() != nil:
+		m.outcome(StateTimeout)
+	case err != nil:
+		m.outcome(StateFailed)
+	default:
+		m.outcome(StateCompleted)
+	}
+}
@@ -0,0 +1,16 @@
+package domainexecutor
+
+import (
+	"github.com/sfperusacdev/identitysdk/httpapi"
+	"go.uber.org/fx"
+)
+
+// Module expone el estado de las tareas del Registry por defecto para la empresa de la sesión.
+var Module = fx.Module(
+	"domainexecutor",
+	fx.Provide(
+		DefaultRegistry,
+		httpapi.AsRoute(NewListTasksHandler),
+		httpapi.AsRoute(NewGetTaskHandler),
+	),
+)
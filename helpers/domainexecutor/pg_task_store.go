@@ -0,0 +1,244 @@
+package domainexecutor
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	connection "github.com/sfperusacdev/identitysdk/pg-connection"
+	"github.com/user0608/goones/errs"
+	"gorm.io/gorm"
+)
+
+type pgTask struct {
+	ID          int64 `gorm:"primaryKey"`
+	Executor    string
+	Domain      string
+	Empresa     string
+	TaskType    string
+	Payload     []byte
+	State       string
+	Attempts    int
+	LastError   string
+	RunAt       time.Time
+	Owner       string
+	LockedUntil *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (pgTask) TableName() string { return "_domain_tasks" }
+
+func (t pgTask) record() TaskRecord {
+	var lockedUntil time.Time
+	if t.LockedUntil != nil {
+		lockedUntil = *t.LockedUntil
+	}
+	return TaskRecord{
+		ID:          t.ID,
+		Executor:    t.Executor,
+		Domain:      t.Domain,
+		Empresa:     t.Empresa,
+		Type:        t.TaskType,
+		Payload:     t.Payload,
+		State:       TaskState(t.State),
+		Attempts:    t.Attempts,
+		LastError:   t.LastError,
+		RunAt:       t.RunAt,
+		Owner:       t.Owner,
+		LockedUntil: lockedUntil,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+// PgTaskStore guarda las tareas en la tabla _domain_tasks.
+type PgTaskStore struct {
+	manager connection.StorageManager
+	schema  connection.Schema
+}
+
+var _ TaskStore = (*PgTaskStore)(nil)
+
+func NewPgTaskStore(manager connection.StorageManager) *PgTaskStore {
+	return &PgTaskStore{manager: manager}
+}
+
+// taskTable guarda las tareas durables; el índice parcial cubre solo las
+// pendientes, que son las que Claim recorre en orden por dominio.
+const taskTable = `
+	CREATE TABLE IF NOT EXISTS _domain_tasks (
+		id BIGSERIAL PRIMARY KEY,
+		executor VARCHAR(255) NOT NULL,
+		domain VARCHAR(255) NOT NULL,
+		empresa VARCHAR(255) NOT NULL DEFAULT '',
+		task_type VARCHAR(255) NOT NULL,
+		payload JSONB NOT NULL DEFAULT 'null',
+		state VARCHAR(32) NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		run_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		owner VARCHAR(255) NOT NULL DEFAULT '',
+		locked_until TIMESTAMPTZ,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	);
+	CREATE INDEX IF NOT EXISTS _domain_tasks_pending_idx ON _domain_tasks (executor, domain, id)
+		WHERE state IN ('pending', 'running')`
+
+// conn no usa la transacción del llamador: la tarea debe quedar guardada
+// aunque esa transacción se revierta después.
+func (s *PgTaskStore) conn() (*gorm.DB, error) {
+	tx := s.manager.Conn(context.Background())
+	if tx == nil {
+		return nil, nil
+	}
+	if err := s.schema.Ensure(s.manager, taskTable); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (s *PgTaskStore) Insert(ctx context.Context, record *TaskRecord) error {
+	tx, err := s.conn()
+	if err != nil || tx == nil {
+		return err
+	}
+	const insert = `
+	INSERT INTO _domain_tasks (executor, domain, empresa, task_type, payload, state, attempts, run_at, owner, locked_until)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	RETURNING id, created_at`
+	row := tx.WithContext(ctx).Raw(insert,
+		record.Executor,
+		record.Domain,
+		record.Empresa,
+		record.Type,
+		string(record.Payload),
+		string(record.State),
+		record.Attempts,
+		record.RunAt,
+		record.Owner,
+		nullTime(record.LockedUntil),
+	).Row()
+	if err := row.Scan(&record.ID, &record.CreatedAt); err != nil {
+		return errs.Pgf(err)
+	}
+	record.UpdatedAt = record.CreatedAt
+	return nil
+}
+
+func (s *PgTaskStore) Update(ctx context.Context, record TaskRecord) error {
+	tx, err := s.conn()
+	if err != nil || tx == nil {
+		return err
+	}
+	const update = `
+	UPDATE _domain_tasks
+	SET state = ?, attempts = ?, last_error = ?, run_at = ?, updated_at = now()
+	WHERE id = ? AND owner = ?`
+	result := tx.WithContext(ctx).Exec(update,
+		string(record.State),
+		record.Attempts,
+		record.LastError,
+		record.RunAt,
+		record.ID,
+		record.Owner,
+	)
+	if result.Error != nil {
+		return errs.Pgf(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (s *PgTaskStore) Get(ctx context.Context, id int64) (TaskRecord, error) {
+	tx, err := s.conn()
+	if err != nil {
+		return TaskRecord{}, err
+	}
+	if tx == nil {
+		return TaskRecord{}, ErrTaskNotFound
+	}
+	var row pgTask
+	if err := tx.WithContext(ctx).Where("id = ?", id).Take(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return TaskRecord{}, ErrTaskNotFound
+		}
+		return TaskRecord{}, errs.Pgf(err)
+	}
+	return row.record(), nil
+}
+
+func (s *PgTaskStore) Claim(ctx context.Context, executor, owner string, lease time.Duration) ([]TaskRecord, error) {
+	tx, err := s.conn()
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	// una tarea tomada por otra réplica retiene a las siguientes de su dominio
+	const claim = `
+	UPDATE _domain_tasks SET owner = ?, locked_until = now() + make_interval(secs => ?)
+	WHERE id IN (
+		SELECT t.id FROM _domain_tasks t
+		WHERE t.executor = ?
+			AND t.state IN ('pending', 'running')
+			AND (t.locked_until IS NULL OR t.locked_until < now())
+			AND NOT EXISTS (
+				SELECT 1 FROM _domain_tasks p
+				WHERE p.executor = t.executor AND p.domain = t.domain
+					AND p.state IN ('pending', 'running') AND p.id < t.id
+					AND p.owner <> ? AND p.locked_until >= now()
+			)
+		ORDER BY t.id
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *`
+	var rows []pgTask
+	if err := tx.WithContext(ctx).Raw(claim, owner, lease.Seconds(), executor, owner).Scan(&rows).Error; err != nil {
+		return nil, errs.Pgf(err)
+	}
+	records := make([]TaskRecord, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, row.record())
+	}
+	slices.SortFunc(records, func(a, b TaskRecord) int { return cmp.Compare(a.ID, b.ID) })
+	return records, nil
+}
+
+func (s *PgTaskStore) Renew(ctx context.Context, executor, owner string, lease time.Duration) error {
+	tx, err := s.conn()
+	if err != nil || tx == nil {
+		return err
+	}
+	const renew = `
+	UPDATE _domain_tasks SET locked_until = now() + make_interval(secs => ?)
+	WHERE executor = ? AND owner = ? AND state IN ('pending', 'running')`
+	if err := tx.WithContext(ctx).Exec(renew, lease.Seconds(), executor, owner).Error; err != nil {
+		return errs.Pgf(err)
+	}
+	return nil
+}
+
+func (s *PgTaskStore) Release(ctx context.Context, executor, owner string) error {
+	tx, err := s.conn()
+	if err != nil || tx == nil {
+		return err
+	}
+	const release = `
+	UPDATE _domain_tasks SET owner = '', locked_until = NULL
+	WHERE executor = ? AND owner = ? AND state IN ('pending', 'running')`
+	if err := tx.WithContext(ctx).Exec(release, executor, owner).Error; err != nil {
+		return errs.Pgf(err)
+	}
+	return nil
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
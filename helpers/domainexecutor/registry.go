@@ -0,0 +1,343 @@
+package domainexecutor
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sfperusacdev/identitysdk"
+)
+
+const registrySweepInterval = time.Minute
+
+// Progress avance reportado por una tarea en ejecución.
+type Progress struct {
+	Current int64  `json:"current"`
+	Total   int64  `json:"total"`
+	Message string `json:"message,omitempty"`
+}
+
+// TaskInfo estado consultable de una tarea.
+type TaskInfo struct {
+	ID       string    `json:"id"`
+	Executor string    `json:"executor"`
+	Domain   string    `json:"domain"`
+	Empresa  string    `json:"empresa"`
+	Name     string    `json:"name"`
+	State    TaskState `json:"state"`
+	Error    string    `json:"error,omitempty"`
+	// Attempts solo para tareas durables
+	Attempts   int       `json:"attempts,omitempty"`
+	Progress   Progress  `json:"progress"`
+	CreatedAt  time.Time `json:"created_at"`
+	StartedAt  time.Time `json:"started_at,omitzero"`
+	FinishedAt time.Time `json:"finished_at,omitzero"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (t TaskInfo) Finished() bool {
+	switch t.State {
+	case StateCompleted, StateFailed, StateTimeout, StateCancelled, StateDeadLetter:
+		return true
+	}
+	return false
+}
+
+type TaskFilter struct {
+	Empresa string
+	Domain  string
+	State   TaskState
+}
+
+type RegistryConfig struct {
+	// MaxPerDomain tareas que se conservan por dominio; las terminadas más
+	// antiguas se descartan primero. Por defecto 50
+	MaxPerDomain int
+	// Retention tiempo que se conserva una tarea terminada; por defecto 24h
+	Retention time.Duration
+}
+
+// Registry guarda en memoria el estado de las tareas recientes de los executors.
+// Las tareas durables que no están en memoria (por ejemplo, encoladas en otra
+// réplica) se consultan en el TaskStore de los executors durables.
+type Registry struct {
+	cfg RegistryConfig
+	now func() time.Time
+
+	mu        sync.Mutex
+	tasks     map[string]*taskEntry
+	domains   map[string][]*taskEntry
+	stores    []TaskStore
+	lastSweep time.Time
+}
+
+func NewRegistry(cfg RegistryConfig) *Registry {
+	if cfg.MaxPerDomain <= 0 {
+		cfg.MaxPerDomain = 50
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 24 * time.Hour
+	}
+	return &Registry{
+		cfg:     cfg,
+		now:     time.Now,
+		tasks:   map[string]*taskEntry{},
+		domains: map[string][]*taskEntry{},
+	}
+}
+
+var (
+	defaultRegistryMu sync.RWMutex
+	defaultRegistry   = NewRegistry(RegistryConfig{})
+)
+
+// SetDefaultRegistry cambia el Registry de los executors creados sin uno propio.
+func SetDefaultRegistry(registry *Registry) {
+	if registry == nil {
+		return
+	}
+	defaultRegistryMu.Lock()
+	defer defaultRegistryMu.Unlock()
+	defaultRegistry = registry
+}
+
+func DefaultRegistry() *Registry {
+	defaultRegistryMu.RLock()
+	defer defaultRegistryMu.RUnlock()
+	return defaultRegistry
+}
+
+// Get devuelve el estado de una tarea por su ID.
+func (r *Registry) Get(id string) (TaskInfo, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.tasks[id]
+	if !ok {
+		return TaskInfo{}, false
+	}
+	return entry.info, true
+}
+
+// Lookup busca la tarea en memoria y, si no está, en los TaskStore de los
+// executors durables.
+func (r *Registry) Lookup(ctx context.Context, id string) (TaskInfo, bool, error) {
+	if info, ok := r.Get(id); ok {
+		return info, true, nil
+	}
+	storeID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return TaskInfo{}, false, nil
+	}
+	r.mu.Lock()
+	stores := slices.Clone(r.stores)
+	r.mu.Unlock()
+	for _, store := range stores {
+		record, err := store.Get(ctx, storeID)
+		if errors.Is(err, ErrTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return TaskInfo{}, false, err
+		}
+		return record.info(), true, nil
+	}
+	return TaskInfo{}, false, nil
+}
+
+// addStore registra el TaskStore de un executor durable para Lookup.
+func (r *Registry) addStore(store TaskStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !slices.Contains(r.stores, store) {
+		r.stores = append(r.stores, store)
+	}
+}
+
+// List devuelve las tareas de la empresa, las más recientes primero; Domain y
+// State filtran si no están vacíos.
+func (r *Registry) List(filter TaskFilter) []TaskInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tasks := []TaskInfo{}
+	for _, entry := range r.tasks {
+		info := entry.info
+		if info.Empresa != filter.Empresa {
+			continue
+		}
+		if filter.Domain != "" && info.Domain != filter.Domain {
+			continue
+		}
+		if filter.State != "" && info.State != filter.State {
+			continue
+		}
+		tasks = append(tasks, info)
+	}
+	slices.SortFunc(tasks, func(a, b TaskInfo) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return tasks
+}
+
+// empresa devuelve la empresa de la sesión o "" si el contexto no tiene identidad.
+func empresa(ctx context.Context) string {
+	value := identitysdk.Empresa(ctx)
+	if strings.HasPrefix(value, "####") {
+		return ""
+	}
+	return value
+}
+
+type taskEntry struct {
+	registry *Registry
+	id       string
+	domain   string
+	info     TaskInfo
+}
+
+type taskEntryKey struct{}
+
+// newEntry prepara el registro de una tarea; se publica con add.
+func (r *Registry) newEntry(ctx context.Context, info TaskInfo) *taskEntry {
+	if info.ID == "" {
+		info.ID = uuid.NewString()
+	}
+	if info.Empresa == "" {
+		info.Empresa = empresa(ctx)
+	}
+	now := r.now()
+	if info.CreatedAt.IsZero() {
+		info.CreatedAt = now
+	}
+	info.UpdatedAt = now
+	return &taskEntry{registry: r, id: info.ID, domain: info.Domain, info: info}
+}
+
+func (r *Registry) add(entry *taskEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	if now.Sub(r.lastSweep) >= registrySweepInterval {
+		r.lastSweep = now
+		r.sweep(now)
+	}
+	r.tasks[entry.id] = entry
+	key := entry.info.Executor + "/" + entry.info.Domain
+	list := append(r.domains[key], entry)
+	for len(list) > r.cfg.MaxPerDomain {
+		i := slices.IndexFunc(list, func(e *taskEntry) bool { return e.info.Finished() })
+		if i < 0 {
+			break
+		}
+		delete(r.tasks, list[i].id)
+		list = slices.Delete(list, i, i+1)
+	}
+	r.domains[key] = list
+}
+
+// remove descarta una tarea que el executor no llegó a aceptar.
+func (r *Registry) remove(entry *taskEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tasks, entry.id)
+	key := entry.info.Executor + "/" + entry.info.Domain
+	list := slices.DeleteFunc(r.domains[key], func(e *taskEntry) bool { return e == entry })
+	if len(list) == 0 {
+		delete(r.domains, key)
+		return
+	}
+	r.domains[key] = list
+}
+
+func (r *Registry) sweep(now time.Time) {
+	for key, list := range r.domains {
+		list = slices.DeleteFunc(list, func(e *taskEntry) bool {
+			expired := e.info.Finished() && now.Sub(e.info.FinishedAt) > r.cfg.Retention
+			if expired {
+				delete(r.tasks, e.id)
+			}
+			return expired
+		})
+		if len(list) == 0 {
+			delete(r.domains, key)
+			continue
+		}
+		r.domains[key] = list
+	}
+}
+
+func (t *taskEntry) set(state TaskState, err error) {
+	t.registry.mu.Lock()
+	defer t.registry.mu.Unlock()
+	now := t.registry.now()
+	t.info.State = state
+	t.info.Error = ""
+	if err != nil {
+		t.info.Error = err.Error()
+	}
+	if state == StateRunning {
+		t.info.StartedAt = now
+	}
+	if t.info.Finished() {
+		t.info.FinishedAt = now
+	}
+	t.info.UpdatedAt = now
+}
+
+// setRecord copia el estado guardado de una tarea durable.
+func (t *taskEntry) setRecord(record TaskRecord) {
+	t.set(record.State, record.Err())
+	t.registry.mu.Lock()
+	defer t.registry.mu.Unlock()
+	t.info.Attempts = record.Attempts
+}
+
+func (t *taskEntry) setProgress(progress Progress) {
+	t.registry.mu.Lock()
+	defer t.registry.mu.Unlock()
+	t.info.Progress = progress
+	t.info.UpdatedAt = t.registry.now()
+}
+
+// callback actualiza el registro antes de notificar al callback del llamador.
+func (t *taskEntry) callback(cb StateCallback) StateCallback {
+	return func(state TaskState, err error) {
+		t.set(state, err)
+		if cb != nil {
+			cb(state, err)
+		}
+	}
+}
+
+// wrap deja el registro en el contexto de la tarea para TaskID y ReportProgress.
+func (t *taskEntry) wrap(task Task) Task {
+	return func(ctx context.Context) error {
+		return task(context.WithValue(ctx, taskEntryKey{}, t))
+	}
+}
+
+// TaskID devuelve el ID de la tarea en ejecución o "" fuera de una tarea.
+func TaskID(ctx context.Context) string {
+	if entry, ok := ctx.Value(taskEntryKey{}).(*taskEntry); ok {
+		return entry.id
+	}
+	return ""
+}
+
+// TaskDomain devuelve el dominio de la tarea en ejecución o "" fuera de una tarea.
+func TaskDomain(ctx context.Context) string {
+	if entry, ok := ctx.Value(taskEntryKey{}).(*taskEntry); ok {
+		return entry.domain
+	}
+	return ""
+}
+
+// ReportProgress publica el avance de la tarea en ejecución; fuera de una
+// tarea no hace nada.
+func ReportProgress(ctx context.Context, current, total int64, message string) {
+	if entry, ok := ctx.Value(taskEntryKey{}).(*taskEntry); ok {
+		entry.setProgress(Progress{Current: current, Total: total, Message: message})
+	}
+}
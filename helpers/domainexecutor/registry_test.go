@@ -0,0 +1,184 @@
+package domainexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/httpapi"
+)
+
+func TestSubmitReportsProgressInRegistry(t *testing.T) {
+	registry := NewRegistry(RegistryConfig{})
+	exec := New(Config{MaxWait: time.Second, QueueCapacity: 1, Name: "planillas", Registry: registry})
+	defer exec.Shutdown(context.Background())
+
+	ctx, cancel := context.WithCancel(identitysdk.CtxWithDomain(context.Background(), "sfperu"))
+	reported := make(chan struct{})
+	finish := make(chan struct{})
+	var taskID string
+	id, err := exec.Submit(ctx, "sfperu", "recalculo planilla", func(ctx context.Context) error {
+		taskID = TaskID(ctx)
+		ReportProgress(ctx, 40, 100, "calculando")
+		close(reported)
+		<-finish
+		return ctx.Err()
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// la tarea no depende de la petición que la originó
+	cancel()
+
+	<-reported
+	info, ok := registry.Get(id)
+	if !ok || info.State != StateRunning || info.Progress.Current != 40 || info.Progress.Message != "calculando" ||
+		info.Empresa != "sfperu" || info.Name != "recalculo planilla" || info.Executor != "planillas" {
+		t.Fatalf("unexpected running task %+v", info)
+	}
+	close(finish)
+
+	waitFor(t, func() bool {
+		info, _ := registry.Get(id)
+		return info.State == StateCompleted
+	})
+	if taskID != id {
+		t.Fatalf("expected TaskID %q inside the task, got %q", id, taskID)
+	}
+	if tasks := registry.List(TaskFilter{Empresa: "sfperu", State: StateCompleted}); len(tasks) != 1 || tasks[0].ID != id {
+		t.Fatalf("unexpected list %+v", tasks)
+	}
+	if tasks := registry.List(TaskFilter{Empresa: "otra"}); len(tasks) != 0 {
+		t.Fatalf("expected tasks to be scoped by empresa, got %+v", tasks)
+	}
+}
+
+func TestTrySubmitAndDurableTasksInRegistry(t *testing.T) {
+	registry := NewRegistry(RegistryConfig{MaxPerDomain: 2})
+	try := NewTry(TryConfig{QueueCapacity: 1, Registry: registry})
+	defer try.Shutdown(context.Background())
+
+	ctx := identitysdk.CtxWithDomain(context.Background(), "sfperu")
+	var ids []string
+	for range 3 {
+		done := make(chan struct{})
+		id, ok, err := try.TrySubmit(ctx, "a", "sync", func(ctx context.Context) error {
+			close(done)
+			return nil
+		}, nil)
+		if err != nil || !ok {
+			t.Fatalf("expected task to be accepted, got %v %v", ok, err)
+		}
+		<-done
+		waitFor(t, func() bool {
+			info, _ := registry.Get(id)
+			return info.State == StateCompleted
+		})
+		ids = append(ids, id)
+	}
+	if _, ok := registry.Get(ids[0]); ok {
+		t.Fatal("expected oldest finished task to be discarded")
+	}
+	if tasks := registry.List(TaskFilter{Empresa: "sfperu", Domain: "a"}); len(tasks) != 2 || tasks[0].ID != ids[2] {
+		t.Fatalf("expected the two most recent tasks, got %+v", tasks)
+	}
+
+	exec := newDurable(NewMemoryTaskStore())
+	exec.registry = registry
+	defer exec.Shutdown(context.Background())
+	exec.Register("recalcular", func(ctx context.Context, payload json.RawMessage) error {
+		ReportProgress(ctx, 1, 1, "listo")
+		return nil
+	})
+	record, err := exec.Enqueue(ctx, "sfperu", "recalcular", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		info, _ := registry.Get("1")
+		return info.State == StateCompleted
+	})
+	info, _ := registry.Get("1")
+	if record.ID != 1 || info.Empresa != "sfperu" || info.Attempts != 1 || info.Progress.Message != "listo" {
+		t.Fatalf("unexpected durable task %+v", info)
+	}
+}
+
+func TestTaskRoutesFilterByEmpresa(t *testing.T) {
+	registry := NewRegistry(RegistryConfig{})
+	own := registry.newEntry(identitysdk.CtxWithDomain(context.Background(), "empresa"), TaskInfo{Domain: "empresa", State: StateRunning})
+	other := registry.newEntry(identitysdk.CtxWithDomain(context.Background(), "otra"), TaskInfo{Domain: "otra", State: StateRunning})
+	registry.add(own)
+	registry.add(other)
+
+	server := httpapi.NewTestServer(t, NewListTasksHandler(registry), NewGetTaskHandler(registry))
+
+	res, err := server.Client().Get(server.URL + "/api/v1/_/tasks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	var body struct {
+		Data []TaskInfo `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Data) != 1 || body.Data[0].ID != own.id {
+		t.Fatalf("expected only the session's tasks, got %+v", body.Data)
+	}
+
+	for id, want := range map[string]int{own.id: http.StatusOK, other.id: http.StatusNotFound} {
+		res, err := server.Client().Get(server.URL + "/api/v1/_/tasks/" + id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != want {
+			t.Fatalf("task %s: expected status %d, got %d", id, want, res.StatusCode)
+		}
+	}
+}
+
+func TestGetTaskReadsDurableTasksFromStore(t *testing.T) {
+	store := NewMemoryTaskStore()
+	// tareas encoladas por otra réplica
+	own := TaskRecord{Executor: "test", Domain: "a", Empresa: "empresa", Type: "recalcular", State: StateDeadLetter, Attempts: 3, LastError: "sin conexión"}
+	other := TaskRecord{Executor: "test", Domain: "a", Empresa: "otra", Type: "recalcular", State: StatePending}
+	for _, record := range []*TaskRecord{&own, &other} {
+		if err := store.Insert(context.Background(), record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	registry := NewRegistry(RegistryConfig{})
+	exec := New(Config{Name: "test", Registry: registry, Durable: DurableConfig{Store: store}})
+	defer exec.Shutdown(context.Background())
+
+	server := httpapi.NewTestServer(t, NewGetTaskHandler(registry))
+	for id, want := range map[string]int{"1": http.StatusOK, "2": http.StatusNotFound, "3": http.StatusNotFound} {
+		res, err := server.Client().Get(server.URL + "/api/v1/_/tasks/" + id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body struct {
+			Data TaskInfo `json:"data"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		res.Body.Close()
+		if res.StatusCode != want {
+			t.Fatalf("task %s: expected status %d, got %d", id, want, res.StatusCode)
+		}
+		if want != http.StatusOK {
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info := body.Data; info.State != StateDeadLetter || info.Attempts != 3 || info.Error != "sin conexión" || info.FinishedAt.IsZero() {
+			t.Fatalf("unexpected task %+v", info)
+		}
+	}
+}
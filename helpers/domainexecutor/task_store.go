@@ -0,0 +1,208 @@
+package domainexecutor
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TaskRecord es una tarea durable tal como queda guardada en el TaskStore.
+type TaskRecord struct {
+	ID       int64
+	Executor string
+	Domain   string
+	// Empresa de la sesión que encoló la tarea
+	Empresa  string
+	Type     string
+	Payload  json.RawMessage
+	State    TaskState
+	Attempts int
+	// LastError mensaje del último intento fallido
+	LastError string
+	// RunAt momento a partir del cual puede ejecutarse el siguiente intento
+	RunAt time.Time
+	// Owner executor que tiene la tarea tomada hasta LockedUntil
+	Owner       string
+	LockedUntil time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+var (
+	ErrTaskNotFound = errors.New("domain task not found")
+	// ErrLeaseLost indica que otra réplica tomó la tarea al vencer el lease
+	ErrLeaseLost = errors.New("domain task lease lost")
+)
+
+// info arma el estado consultable de la tarea tal como quedó en el store.
+func (r TaskRecord) info() TaskInfo {
+	info := TaskInfo{
+		ID:        strconv.FormatInt(r.ID, 10),
+		Executor:  r.Executor,
+		Domain:    r.Domain,
+		Empresa:   r.Empresa,
+		Name:      r.Type,
+		State:     r.State,
+		Error:     r.LastError,
+		Attempts:  r.Attempts,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+	if info.Finished() {
+		info.FinishedAt = r.UpdatedAt
+	}
+	return info
+}
+
+// Err devuelve el último error del registro o nil.
+func (r TaskRecord) Err() error {
+	if r.LastError == "" {
+		return nil
+	}
+	return errors.New(r.LastError)
+}
+
+// TaskStore guarda las tareas durables del DomainExecutor.
+type TaskStore interface {
+	// Insert guarda una tarea nueva y completa su ID y CreatedAt.
+	Insert(ctx context.Context, record *TaskRecord) error
+	// Update guarda estado, intentos, último error y próxima ejecución solo si
+	// la tarea sigue tomada por record.Owner; si no, devuelve ErrLeaseLost.
+	Update(ctx context.Context, record TaskRecord) error
+	// Get devuelve ErrTaskNotFound si la tarea no existe.
+	Get(ctx context.Context, id int64) (TaskRecord, error)
+	// Claim toma para owner hasta now+lease las tareas pending o running del
+	// executor cuyo lease venció y las devuelve ordenadas por ID. No toma una
+	// tarea mientras otra réplica tenga tomada una anterior del mismo dominio.
+	Claim(ctx context.Context, executor, owner string, lease time.Duration) ([]TaskRecord, error)
+	// Renew extiende hasta now+lease las tareas sin terminar de owner.
+	Renew(ctx context.Context, executor, owner string, lease time.Duration) error
+	// Release suelta las tareas sin terminar de owner para que otra réplica las tome.
+	Release(ctx context.Context, executor, owner string) error
+}
+
+// MemoryTaskStore no sobrevive a reinicios; sirve para pruebas.
+type MemoryTaskStore struct {
+	mu      sync.Mutex
+	nextID  int64
+	records map[int64]TaskRecord
+}
+
+var _ TaskStore = (*MemoryTaskStore)(nil)
+
+func NewMemoryTaskStore() *MemoryTaskStore {
+	return &MemoryTaskStore{records: map[int64]TaskRecord{}}
+}
+
+func (s *MemoryTaskStore) Insert(ctx context.Context, record *TaskRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	now := time.Now()
+	record.ID = s.nextID
+	record.CreatedAt = now
+	record.UpdatedAt = now
+	s.records[record.ID] = *record
+	return nil
+}
+
+func (s *MemoryTaskStore) Update(ctx context.Context, record TaskRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, found := s.records[record.ID]
+	if !found || current.Owner != record.Owner {
+		return ErrLeaseLost
+	}
+	// el lease solo lo cambian Claim, Renew y Release
+	record.LockedUntil = current.LockedUntil
+	record.UpdatedAt = time.Now()
+	s.records[record.ID] = record
+	return nil
+}
+
+func (s *MemoryTaskStore) Get(ctx context.Context, id int64) (TaskRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, found := s.records[id]
+	if !found {
+		return TaskRecord{}, ErrTaskNotFound
+	}
+	return record, nil
+}
+
+// Pending devuelve las tareas pending o running del executor ordenadas por ID.
+func (s *MemoryTaskStore) Pending(ctx context.Context, executor string) ([]TaskRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.unfinished(executor), nil
+}
+
+func (s *MemoryTaskStore) Claim(ctx context.Context, executor, owner string, lease time.Duration) ([]TaskRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	held := map[string]bool{}
+	var claimed []TaskRecord
+	for _, record := range s.unfinished(executor) {
+		if held[record.Domain] {
+			continue
+		}
+		if record.Owner != owner && now.Before(record.LockedUntil) {
+			held[record.Domain] = true
+			continue
+		}
+		if now.Before(record.LockedUntil) {
+			continue
+		}
+		record.Owner = owner
+		record.LockedUntil = now.Add(lease)
+		s.records[record.ID] = record
+		claimed = append(claimed, record)
+	}
+	return claimed, nil
+}
+
+func (s *MemoryTaskStore) Renew(ctx context.Context, executor, owner string, lease time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	until := time.Now().Add(lease)
+	for _, record := range s.unfinished(executor) {
+		if record.Owner == owner {
+			record.LockedUntil = until
+			s.records[record.ID] = record
+		}
+	}
+	return nil
+}
+
+func (s *MemoryTaskStore) Release(ctx context.Context, executor, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, record := range s.unfinished(executor) {
+		if record.Owner == owner {
+			record.Owner = ""
+			record.LockedUntil = time.Time{}
+			s.records[record.ID] = record
+		}
+	}
+	return nil
+}
+
+func (s *MemoryTaskStore) unfinished(executor string) []TaskRecord {
+	var records []TaskRecord
+	for _, record := range s.records {
+		if record.Executor != executor {
+			continue
+		}
+		if record.State == StatePending || record.State == StateRunning {
+			records = append(records, record)
+		}
+	}
+	slices.SortFunc(records, func(a, b TaskRecord) int { return cmp.Compare(a.ID, b.ID) })
+	return records
+}
@@ -21,6 +21,11 @@ type TryExecutor struct {
 type TryConfig struct {
 	IdleEvictAfter time.Duration
 	QueueCapacity  int
+
+	// Name identifica al executor en el Registry; por defecto "default"
+	Name string
+	// Registry donde se publica el estado de las tareas; por defecto DefaultRegistry()
+	Registry *Registry
 }
 
 type tryRunner struct {
@@ -39,6 +44,12 @@ func NewTry(cfg TryConfig) *TryExecutor {
 	if cfg.QueueCapacity <= 0 {
 		cfg.QueueCapacity = 1
 	}
+	if cfg.Name == "" {
+		cfg.Name = "default"
+	}
+	if cfg.Registry == nil {
+		cfg.Registry = DefaultRegistry()
+	}
 
 	return &TryExecutor{
 		runners: make(map[string]*tryRunner),
@@ -55,43 +66,61 @@ func NewTryDefault() *TryExecutor {
 }
 
 func (e *TryExecutor) TryExecute(ctx context.Context, domain string, task Task, cb StateCallback) (bool, error) {
+	_, ok, err := e.TrySubmit(ctx, domain, "", task, cb)
+	return ok, err
+}
+
+// TrySubmit es TryExecute con nombre; devuelve el ID con el que la tarea
+// aceptada se consulta en el Registry.
+func (e *TryExecutor) TrySubmit(ctx context.Context, domain, name string, task Task, cb StateCallback) (string, bool, error) {
 	if task == nil {
-		return false, nil
+		return "", false, nil
 	}
 
 	runner, err := e.getOrCreate(domain)
 	if err != nil {
-		return false, err
+		return "", false, err
 	}
 
+	entry := e.cfg.Registry.newEntry(ctx, TaskInfo{
+		Executor: e.cfg.Name,
+		Domain:   domain,
+		Name:     name,
+		State:    StatePending,
+	})
+
 	reqCtx, cancel := context.WithCancel(ctx)
 	req := tryRequest{
 		ctx:    reqCtx,
 		cancel: cancel,
-		task:   task,
-		cb:     cb,
+		task:   entry.wrap(task),
+		cb:     entry.callback(cb),
 	}
 
+	e.cfg.Registry.add(entry)
 	e.wgTasks.Add(1)
 
 	select {
 	case runner.queue <- req:
-		if req.cb != nil {
-			req.cb(StatePending, nil)
+		if cb != nil {
+			cb(StatePending, nil)
 		}
-		return true, nil
+		return entry.id, true, nil
 	case <-e.stopCh:
 		cancel()
 		e.wgTasks.Done()
-		return false, ErrExecutorClosed
+		e.cfg.Registry.remove(entry)
+		return "", false, ErrExecutorClosed
 	case <-runner.stop:
 		cancel()
 		e.wgTasks.Done()
-		return false, ErrDomainClosed
+		e.cfg.Registry.remove(entry)
+		return "", false, ErrDomainClosed
 	default:
 		cancel()
 		e.wgTasks.Done()
-		return false, nil
+		e.cfg.Registry.remove(entry)
+		return "", false, nil
 	}
 }
 
@@ -0,0 +1,53 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+)
+
+// Broker transporta los eventos entre procesos. Para usar un broker externo
+// (NATS, Kafka, etc.) basta con implementarlo y reemplazar el de Module con
+// fx.Decorate.
+type Broker interface {
+	// Publish envía el evento a todos los procesos que escuchan, incluido este.
+	Publish(ctx context.Context, event Event) error
+	// Listen entrega a deliver los eventos recibidos hasta Close; no bloquea.
+	Listen(deliver func(Event)) error
+	// Close deja de entregar eventos.
+	Close(ctx context.Context) error
+}
+
+// LocalBroker entrega los eventos solo dentro del proceso.
+type LocalBroker struct {
+	mu        sync.RWMutex
+	listeners []func(Event)
+}
+
+var _ Broker = (*LocalBroker)(nil)
+
+func NewLocalBroker() *LocalBroker {
+	return &LocalBroker{}
+}
+
+func (b *LocalBroker) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, deliver := range b.listeners {
+		deliver(event)
+	}
+	return nil
+}
+
+func (b *LocalBroker) Listen(deliver func(Event)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listeners = append(b.listeners, deliver)
+	return nil
+}
+
+func (b *LocalBroker) Close(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listeners = nil
+	return nil
+}
@@ -0,0 +1,298 @@
+// Package eventbus publica eventos de dominio para otros módulos del servicio
+// y, con un Broker compartido, para otros servicios.
+//
+// Publish toma la identidad del contexto (empresa, sucursal, usuario y
+// request id) y la envía con el evento. Cada handler corre en su propia
+// goroutine con un contexto clonado como el de identitysdk.FireAndForget: con
+// LocalBroker es el clon del contexto del publicador; si el evento pasó por un
+// broker (PgBroker o uno externo) se arma con la identidad del evento. La
+// entrega es a lo sumo una vez y sin orden garantizado; los efectos que no
+// deben perderse van por el outbox.
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sfperusacdev/identitysdk"
+)
+
+var ErrNotConfigured = errors.New("event bus not configured")
+
+// Event viaja como JSON por los brokers externos.
+type Event struct {
+	ID    string `json:"id"`
+	Topic string `json:"topic"`
+	// Empresa empresa del publicador; vacía si el contexto no tenía identidad
+	Empresa    string          `json:"empresa,omitempty"`
+	Sucursal   string          `json:"sucursal,omitempty"`
+	Username   string          `json:"username,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+	OccurredAt time.Time       `json:"occurred_at"`
+
+	// ctx contexto del publicador, solo dentro del proceso
+	ctx context.Context
+}
+
+// Decode decodifica el payload en v.
+func (e Event) Decode(v any) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// context devuelve el contexto del publicador o, si el evento pasó por un
+// broker, uno con la identidad del evento.
+func (e Event) context() context.Context {
+	if e.ctx != nil {
+		return e.ctx
+	}
+	ctx := context.Background()
+	if e.Empresa != "" {
+		ctx = identitysdk.CtxWithDomain(ctx, e.Empresa)
+	}
+	if e.Sucursal != "" {
+		ctx = identitysdk.CtxWithSucursal(ctx, e.Sucursal)
+	}
+	if e.Username != "" {
+		ctx = identitysdk.CtxWithUsername(ctx, e.Username)
+	}
+	if e.RequestID != "" {
+		ctx = identitysdk.CtxWithRequestID(ctx, e.RequestID)
+	}
+	return ctx
+}
+
+// Handler procesa un evento; un error solo se registra en el log.
+type Handler interface {
+	Handle(ctx context.Context, event Event) error
+}
+
+type HandlerFunc func(ctx context.Context, event Event) error
+
+func (f HandlerFunc) Handle(ctx context.Context, event Event) error { return f(ctx, event) }
+
+// Typed decodifica el payload en T antes de llamar a fn.
+func Typed[T any](fn func(ctx context.Context, payload T) error) Handler {
+	return HandlerFunc(func(ctx context.Context, event Event) error {
+		var payload T
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		return fn(ctx, payload)
+	})
+}
+
+type SubscribeOption func(*subscription)
+
+// ForEmpresa limita la suscripción a los eventos publicados por esas empresas.
+func ForEmpresa(empresas ...string) SubscribeOption {
+	return func(s *subscription) {
+		s.empresas = append(s.empresas, empresas...)
+	}
+}
+
+type subscription struct {
+	id       int64
+	topic    string
+	empresas []string
+	handler  Handler
+}
+
+// matches admite el tema exacto, "*" para todos o un prefijo como "trabajador.*".
+func (s *subscription) matches(event Event) bool {
+	if len(s.empresas) > 0 && !slices.Contains(s.empresas, event.Empresa) {
+		return false
+	}
+	if s.topic == "*" || s.topic == event.Topic {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(s.topic, "*")
+	return ok && strings.HasPrefix(event.Topic, prefix)
+}
+
+type Bus struct {
+	broker Broker
+
+	mu            sync.RWMutex
+	nextID        int64
+	subscriptions []*subscription
+
+	runMu   sync.Mutex
+	started bool
+	wg      sync.WaitGroup
+}
+
+// New crea un Bus sobre broker; nil usa un LocalBroker.
+func New(broker Broker) *Bus {
+	if broker == nil {
+		broker = NewLocalBroker()
+	}
+	return &Bus{broker: broker}
+}
+
+// Subscribe registra handler para topic y devuelve la función que cancela la suscripción.
+func (b *Bus) Subscribe(topic string, handler Handler, opts ...SubscribeOption) (unsubscribe func()) {
+	if handler == nil {
+		slog.Warn("event handler is nil, operation skipped")
+		return func() {}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	s := &subscription{id: b.nextID, topic: topic, handler: handler}
+	for _, apply := range opts {
+		apply(s)
+	}
+	b.subscriptions = append(b.subscriptions, s)
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.subscriptions = slices.DeleteFunc(b.subscriptions, func(other *subscription) bool {
+			return other.id == s.id
+		})
+	}
+}
+
+// SubscribeTyped registra fn para topic decodificando el payload en T.
+func SubscribeTyped[T any](b *Bus, topic string, fn func(ctx context.Context, payload T) error, opts ...SubscribeOption) (unsubscribe func()) {
+	return b.Subscribe(topic, Typed(fn), opts...)
+}
+
+// Publish envía el evento con la identidad de ctx; payload se serializa como JSON.
+func (b *Bus) Publish(ctx context.Context, topic string, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	empresa, sucursal := identitysdk.Empresa_Sucursal(ctx)
+	event := Event{
+		ID:         uuid.NewString(),
+		Topic:      topic,
+		Empresa:    identityValue(empresa),
+		Sucursal:   identityValue(sucursal),
+		Username:   identityValue(identitysdk.Username(ctx)),
+		RequestID:  identitysdk.RequestID(ctx),
+		Payload:    raw,
+		OccurredAt: time.Now(),
+		ctx:        ctx,
+	}
+	return b.broker.Publish(ctx, event)
+}
+
+// Start empieza a recibir los eventos del broker; antes de Start los eventos
+// publicados no se entregan en este proceso.
+func (b *Bus) Start() error {
+	b.runMu.Lock()
+	defer b.runMu.Unlock()
+	if b.started {
+		return nil
+	}
+	if err := b.broker.Listen(b.dispatch); err != nil {
+		return err
+	}
+	b.started = true
+	return nil
+}
+
+// Close deja de recibir eventos y espera a los handlers en curso.
+func (b *Bus) Close(ctx context.Context) error {
+	b.runMu.Lock()
+	started := b.started
+	b.started = false
+	b.runMu.Unlock()
+	if !started {
+		return nil
+	}
+	if err := b.broker.Close(ctx); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Bus) dispatch(event Event) {
+	b.mu.RLock()
+	var handlers []Handler
+	for _, s := range b.subscriptions {
+		if s.matches(event) {
+			handlers = append(handlers, s.handler)
+		}
+	}
+	b.mu.RUnlock()
+	if len(handlers) == 0 {
+		return
+	}
+
+	base := event.context()
+	event.ctx = nil
+	for _, handler := range handlers {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("event handler panicked", "topic", event.Topic, "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+				}
+			}()
+			if err := handler.Handle(identitysdk.CloneContext(base), event); err != nil {
+				slog.Error("event handler failed", "topic", event.Topic, "empresa", event.Empresa, "event", event.ID, "error", err)
+			}
+		}()
+	}
+}
+
+// identityValue descarta los marcadores "####...-no-found####" de identitysdk.
+func identityValue(value string) string {
+	if strings.HasPrefix(value, "####") {
+		return ""
+	}
+	return value
+}
+
+var (
+	defaultBusMu sync.RWMutex
+	defaultBus   *Bus
+)
+
+// SetDefault define el Bus de Publish; lo registra Module.
+func SetDefault(b *Bus) {
+	if b == nil {
+		return
+	}
+	defaultBusMu.Lock()
+	defer defaultBusMu.Unlock()
+	defaultBus = b
+}
+
+func Default() *Bus {
+	defaultBusMu.RLock()
+	defer defaultBusMu.RUnlock()
+	return defaultBus
+}
+
+// Publish publica el evento en el Bus por defecto.
+func Publish(ctx context.Context, topic string, payload any) error {
+	b := Default()
+	if b == nil {
+		return ErrNotConfigured
+	}
+	return b.Publish(ctx, topic, payload)
+}
@@ -0,0 +1,166 @@
+package eventbus_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/helpers/eventbus"
+)
+
+type trabajadorActualizado struct {
+	Codigo string `json:"codigo"`
+}
+
+type received struct {
+	topic, empresa, username, requestID, codigo string
+	ctxErr                                      error
+}
+
+func collect(t *testing.T, events <-chan received, n int) []received {
+	t.Helper()
+	var all []received
+	for range n {
+		select {
+		case event := <-events:
+			all = append(all, event)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected %d events, got %d", n, len(all))
+		}
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected extra event %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+	return all
+}
+
+func publisherCtx() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = identitysdk.CtxWithDomain(ctx, "sfperu")
+	ctx = identitysdk.CtxWithUsername(ctx, "jperez")
+	ctx = identitysdk.CtxWithRequestID(ctx, "req-1")
+	return ctx, cancel
+}
+
+func TestLocalBusDeliversWithClonedIdentity(t *testing.T) {
+	bus := eventbus.New(nil)
+	events := make(chan received, 10)
+	eventbus.SubscribeTyped(bus, "trabajador.*", func(ctx context.Context, payload trabajadorActualizado) error {
+		events <- received{
+			topic:     "trabajador.*",
+			empresa:   identitysdk.Empresa(ctx),
+			username:  identitysdk.Username(ctx),
+			requestID: identitysdk.RequestID(ctx),
+			codigo:    payload.Codigo,
+			ctxErr:    ctx.Err(),
+		}
+		return nil
+	})
+	bus.Subscribe("trabajador.actualizado", eventbus.HandlerFunc(func(ctx context.Context, event eventbus.Event) error {
+		events <- received{topic: event.Topic, empresa: event.Empresa}
+		return nil
+	}), eventbus.ForEmpresa("agro"))
+	unsubscribe := bus.Subscribe("*", eventbus.HandlerFunc(func(ctx context.Context, event eventbus.Event) error {
+		panic("handler roto")
+	}))
+	if err := bus.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := publisherCtx()
+	// el handler no debe depender de la cancelación del contexto del publicador
+	cancel()
+	if err := bus.Publish(ctx, "trabajador.actualizado", trabajadorActualizado{Codigo: "T001"}); err != nil {
+		t.Fatal(err)
+	}
+	got := collect(t, events, 1)[0]
+	if got.empresa != "sfperu" || got.username != "jperez" || got.requestID != "req-1" || got.codigo != "T001" || got.ctxErr != nil {
+		t.Fatalf("unexpected delivery %+v", got)
+	}
+
+	unsubscribe()
+	agro := identitysdk.CtxWithDomain(context.Background(), "agro")
+	if err := bus.Publish(agro, "trabajador.actualizado", trabajadorActualizado{Codigo: "T002"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Publish(agro, "planilla.cerrada", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := collect(t, events, 2); got[0].empresa != "agro" || got[1].empresa != "agro" {
+		t.Fatalf("unexpected deliveries %+v", got)
+	}
+	if err := bus.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// jsonBroker simula un broker externo: los eventos viajan serializados.
+type jsonBroker struct {
+	mu      sync.Mutex
+	deliver func(eventbus.Event)
+}
+
+func (b *jsonBroker) Publish(ctx context.Context, event eventbus.Event) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	var decoded eventbus.Event
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	deliver := b.deliver
+	b.mu.Unlock()
+	if deliver != nil {
+		go deliver(decoded)
+	}
+	return nil
+}
+
+func (b *jsonBroker) Listen(deliver func(eventbus.Event)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.deliver = deliver
+	return nil
+}
+
+func (b *jsonBroker) Close(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.deliver = nil
+	return nil
+}
+
+func TestBusRebuildsIdentityFromBrokerEvents(t *testing.T) {
+	bus := eventbus.New(&jsonBroker{})
+	events := make(chan received, 1)
+	eventbus.SubscribeTyped(bus, "trabajador.actualizado", func(ctx context.Context, payload trabajadorActualizado) error {
+		events <- received{
+			empresa:   identitysdk.Empresa(ctx),
+			username:  identitysdk.Username(ctx),
+			requestID: identitysdk.RequestID(ctx),
+			codigo:    payload.Codigo,
+		}
+		return nil
+	}, eventbus.ForEmpresa("sfperu"))
+	if err := bus.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer bus.Close(context.Background())
+
+	ctx, cancel := publisherCtx()
+	defer cancel()
+	if err := bus.Publish(ctx, "trabajador.actualizado", trabajadorActualizado{Codigo: "T003"}); err != nil {
+		t.Fatal(err)
+	}
+	got := collect(t, events, 1)[0]
+	if got.empresa != "sfperu" || got.username != "jperez" || got.requestID != "req-1" || got.codigo != "T003" {
+		t.Fatalf("unexpected delivery %+v", got)
+	}
+}
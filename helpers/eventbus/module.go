@@ -0,0 +1,63 @@
+package eventbus
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+)
+
+const SubscriptionTag = `group:"eventbus-subscriptions"`
+
+type Subscription struct {
+	// Topic tema exacto, "*" o un prefijo como "trabajador.*"
+	Topic string
+	// Empresas limita la suscripción a esas empresas; vacía recibe todas
+	Empresas []string
+	Handler  Handler
+}
+
+// AsSubscription registra en el Bus la Subscription que devuelve fn.
+func AsSubscription(fn any) any {
+	return fx.Annotate(
+		fn,
+		fx.ResultTags(SubscriptionTag),
+	)
+}
+
+type busParams struct {
+	fx.In
+	Lifecycle     fx.Lifecycle
+	Broker        Broker
+	Subscriptions []Subscription `group:"eventbus-subscriptions"`
+}
+
+func newBus(p busParams) *Bus {
+	b := New(p.Broker)
+	for _, subscription := range p.Subscriptions {
+		var opts []SubscribeOption
+		if len(subscription.Empresas) > 0 {
+			opts = append(opts, ForEmpresa(subscription.Empresas...))
+		}
+		b.Subscribe(subscription.Topic, subscription.Handler, opts...)
+	}
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error { return b.Start() },
+		OnStop:  b.Close,
+	})
+	return b
+}
+
+// Module distribuye los eventos entre los servicios que comparten la base de
+// datos con LISTEN/NOTIFY y los entrega a las suscripciones registradas con
+// AsSubscription. Otro broker se usa con fx.Decorate sobre Broker.
+var Module = fx.Module(
+	"eventbus",
+	fx.Provide(
+		fx.Annotate(
+			NewPgBroker,
+			fx.As(new(Broker)),
+		),
+		newBus,
+	),
+	fx.Invoke(SetDefault),
+)
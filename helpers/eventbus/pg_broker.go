@@ -0,0 +1,173 @@
+package eventbus
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	connection "github.com/sfperusacdev/identitysdk/pg-connection"
+	"github.com/user0608/goones/errs"
+)
+
+// PgChannel canal de LISTEN/NOTIFY compartido por los servicios de la misma base.
+const PgChannel = "_identitysdk_events"
+
+// pgMaxPayload límite de NOTIFY en la configuración por defecto de Postgres.
+const pgMaxPayload = 7999
+
+const (
+	pgMinReconnect = time.Second
+	pgMaxReconnect = 30 * time.Second
+)
+
+// PgBroker distribuye los eventos con LISTEN/NOTIFY. Publicado dentro de
+// StorageManager.WithTx, el evento se entrega solo si la transacción hace
+// commit. El evento serializado no puede superar los 8000 bytes de NOTIFY:
+// los datos grandes se consultan por su ID. Sin base de datos (SkipStorage)
+// los eventos solo se entregan en este proceso.
+type PgBroker struct {
+	manager connection.StorageManager
+
+	mu      sync.Mutex
+	deliver func(Event)
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+var _ Broker = (*PgBroker)(nil)
+
+func NewPgBroker(manager connection.StorageManager) *PgBroker {
+	return &PgBroker{manager: manager}
+}
+
+func (b *PgBroker) Publish(ctx context.Context, event Event) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if len(raw) > pgMaxPayload {
+		return fmt.Errorf("event %s is %d bytes, exceeds the NOTIFY limit of %d bytes", event.Topic, len(raw), pgMaxPayload)
+	}
+	tx := b.manager.Conn(ctx)
+	if tx == nil {
+		b.mu.Lock()
+		deliver := b.deliver
+		b.mu.Unlock()
+		if deliver != nil {
+			deliver(event)
+		}
+		return nil
+	}
+	if err := tx.Exec("SELECT pg_notify(?, ?)", PgChannel, string(raw)).Error; err != nil {
+		return errs.Pgf(err)
+	}
+	return nil
+}
+
+func (b *PgBroker) Listen(deliver func(Event)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancel != nil {
+		return errors.New("pg broker is already listening")
+	}
+	b.deliver = deliver
+	if b.manager.Conn(context.Background()) == nil {
+		return nil // skip
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.done = make(chan struct{})
+	go b.loop(ctx, deliver, b.done)
+	return nil
+}
+
+func (b *PgBroker) Close(ctx context.Context) error {
+	b.mu.Lock()
+	cancel, done := b.cancel, b.done
+	b.deliver, b.cancel, b.done = nil, nil, nil
+	b.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loop reconecta con backoff mientras ctx siga activo; los eventos
+// notificados durante la reconexión se pierden.
+func (b *PgBroker) loop(ctx context.Context, deliver func(Event), done chan struct{}) {
+	defer close(done)
+	wait := pgMinReconnect
+	for {
+		listening, err := b.listen(ctx, deliver)
+		if ctx.Err() != nil {
+			return
+		}
+		if listening {
+			wait = pgMinReconnect
+		}
+		slog.Error("event bus listener disconnected, reconnecting", "channel", PgChannel, "retry_in", wait, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, pgMaxReconnect)
+	}
+}
+
+// listen fija una conexión del pool y espera notificaciones hasta un error o
+// hasta que ctx se cancele; listening indica si llegó a ejecutar LISTEN.
+func (b *PgBroker) listen(ctx context.Context, deliver func(Event)) (listening bool, err error) {
+	tx := b.manager.Conn(context.Background())
+	if tx == nil {
+		return false, errors.New("storage is not available")
+	}
+	db, err := tx.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn any) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unsupported postgres driver %T", driverConn)
+		}
+		pgConn := stdConn.Conn()
+		if _, err := pgConn.Exec(ctx, "LISTEN "+pgx.Identifier{PgChannel}.Sanitize()); err != nil {
+			return err
+		}
+		listening = true
+		for {
+			notification, err := pgConn.WaitForNotification(ctx)
+			if err != nil {
+				// la conexión sigue suscrita al canal: se descarta en lugar de volver al pool
+				return errors.Join(err, driver.ErrBadConn)
+			}
+			var event Event
+			if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+				slog.Warn("invalid event bus notification, skipped", "channel", PgChannel, "error", err)
+				continue
+			}
+			deliver(event)
+		}
+	})
+	return listening, err
+}
@@ -69,6 +69,7 @@ func NewFileCache(baseDir string, maxEntries int, evictInterval time.Duration) (
 		cache.insertFileAccessIfNew(filename)
 		cache.pathsMap[path] = struct{}{}
 	}
+	cacheEntries.With(baseDir).Set(float64(len(cache.pathsMap)))
 	go cache.startEvictionLoop(max(evictInterval, 1*time.Hour))
 	return cache, nil
 }
@@ -162,6 +163,7 @@ func (c *FileCache) addToCache(filename string) {
 	c.m.Lock()
 	defer c.m.Unlock()
 	c.pathsMap[hashed] = struct{}{}
+	cacheEntries.With(c.baseDir).Set(float64(len(c.pathsMap)))
 }
 
 func (c *FileCache) removeFromCache(filename string) {
@@ -169,6 +171,7 @@ func (c *FileCache) removeFromCache(filename string) {
 	c.m.Lock()
 	defer c.m.Unlock()
 	delete(c.pathsMap, hashed)
+	cacheEntries.With(c.baseDir).Set(float64(len(c.pathsMap)))
 }
 
 func (c *FileCache) Read(filename string) ([]byte, error) {
@@ -221,6 +224,7 @@ func (c *FileCache) evict() {
 			continue
 		}
 		c.removeFromCache(filename)
+		cacheEvictions.With(c.baseDir).Inc()
 		if err := c.deleteFileAccessRecord(filename); err != nil {
 			slog.Error("failed to delete file_access record", "filename", filename, "error", err)
 		}
@@ -0,0 +1,16 @@
+package filecache
+
+import "github.com/sfperusacdev/identitysdk/metrics"
+
+var (
+	cacheEntries = metrics.NewGauge(
+		"filecache_entries",
+		"Archivos almacenados en la caché por directorio.",
+		"dir",
+	)
+	cacheEvictions = metrics.NewCounter(
+		"filecache_evictions_total",
+		"Archivos eliminados por la evicción de la caché.",
+		"dir",
+	)
+)
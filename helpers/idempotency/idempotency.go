@@ -0,0 +1,66 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HeaderKey es la cabecera que envía el cliente para identificar un intento.
+const HeaderKey = "Idempotency-Key"
+
+// HeaderReplayed se agrega a las respuestas que provienen de un intento anterior.
+const HeaderReplayed = "Idempotent-Replayed"
+
+// DefaultRetention es el tiempo que se conserva una respuesta si la ruta no indica otro.
+const DefaultRetention = 24 * time.Hour
+
+// LockTimeout es el tiempo que un intento en proceso retiene la llave; si la
+// réplica que lo atendía cae, otro intento la toma al vencer.
+const LockTimeout = 5 * time.Minute
+
+// Record es un intento registrado; Status 0 indica que aún está en proceso.
+type Record struct {
+	Key         string
+	Fingerprint string
+	Status      int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time
+	// LockedUntil vence la reserva de un intento en proceso
+	LockedUntil time.Time
+}
+
+func (r Record) InProgress() bool { return r.Status == 0 }
+
+// Store registra los intentos. Begin reserva la llave y devuelve nil si la
+// reserva fue exitosa, o el registro vigente si la llave ya existía; una
+// reserva en proceso con LockedUntil vencido se reemplaza.
+type Store interface {
+	Begin(ctx context.Context, record Record) (*Record, error)
+	Complete(ctx context.Context, key string, status int, contentType string, body []byte) error
+	// Release libera la llave para que el cliente pueda reintentar.
+	Release(ctx context.Context, key string) error
+	// Purge elimina los registros vencidos.
+	Purge(ctx context.Context) error
+}
+
+var (
+	defaultStoreMu sync.RWMutex
+	defaultStore   Store = NewMemoryStore()
+)
+
+func SetDefaultStore(store Store) {
+	if store == nil {
+		return
+	}
+	defaultStoreMu.Lock()
+	defer defaultStoreMu.Unlock()
+	defaultStore = store
+}
+
+func DefaultStore() Store {
+	defaultStoreMu.RLock()
+	defer defaultStoreMu.RUnlock()
+	return defaultStore
+}
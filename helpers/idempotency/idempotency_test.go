@@ -0,0 +1,116 @@
+package idempotency_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/helpers/idempotency"
+)
+
+func newServer(calls *atomic.Int32, release <-chan struct{}) *echo.Echo {
+	e := echo.New()
+	e.POST("/sync", func(c echo.Context) error {
+		calls.Add(1)
+		if release != nil {
+			<-release
+		}
+		return c.JSON(http.StatusCreated, map[string]int32{"call": calls.Load()})
+	}, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := identitysdk.CtxWithDomain(c.Request().Context(), "sfperu")
+			ctx = identitysdk.CtxWithUsername(ctx, c.Request().Header.Get("X-User"))
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}, idempotency.Middleware(time.Hour))
+	return e
+}
+
+func post(e *echo.Echo, user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/sync", strings.NewReader(body))
+	req.Header.Set("X-User", user)
+	if key != "" {
+		req.Header.Set(idempotency.HeaderKey, key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddlewareReplaysAndRejects(t *testing.T) {
+	idempotency.SetDefaultStore(idempotency.NewMemoryStore())
+	var calls atomic.Int32
+	e := newServer(&calls, nil)
+
+	first := post(e, "ana", "k1", `{"a":1}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("unexpected status %d", first.Code)
+	}
+	retry := post(e, "ana", "k1", `{"a":1}`)
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() ||
+		retry.Header().Get(idempotency.HeaderReplayed) != "true" {
+		t.Fatalf("expected replay, got %d %s", retry.Code, retry.Body.String())
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("handler must run once, ran %d times", calls.Load())
+	}
+
+	if rec := post(e, "ana", "k1", `{"a":2}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected rejection for different body, got %d", rec.Code)
+	}
+	if rec := post(e, "luis", "k1", `{"a":1}`); rec.Code != http.StatusCreated || calls.Load() != 2 {
+		t.Fatalf("keys must be scoped per user, got %d", rec.Code)
+	}
+	if post(e, "ana", "", `{"a":1}`); calls.Load() != 3 {
+		t.Fatal("requests without key must not be deduplicated")
+	}
+}
+
+func TestMiddlewareRejectsConcurrentDuplicate(t *testing.T) {
+	idempotency.SetDefaultStore(idempotency.NewMemoryStore())
+	var calls atomic.Int32
+	release := make(chan struct{})
+	e := newServer(&calls, release)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- post(e, "ana", "k2", `{}`) }()
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	rec := post(e, "ana", "k2", `{}`)
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), `"type":"error"`) {
+		t.Fatalf("expected 409 while first attempt runs, got %d %s", rec.Code, rec.Body.String())
+	}
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Fatalf("unexpected status %d", first.Code)
+	}
+	if rec := post(e, "ana", "k2", `{}`); rec.Code != http.StatusCreated || calls.Load() != 1 {
+		t.Fatalf("expected replay after completion, got %d", rec.Code)
+	}
+}
+
+func TestMemoryStoreTakesOverExpiredLock(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+	stalled := idempotency.Record{Key: "k3", Fingerprint: "f", ExpiresAt: now.Add(time.Hour), LockedUntil: now.Add(-time.Second)}
+	if current, err := store.Begin(ctx, stalled); err != nil || current != nil {
+		t.Fatalf("expected reservation, got %+v %v", current, err)
+	}
+	retry := idempotency.Record{Key: "k3", Fingerprint: "f", ExpiresAt: now.Add(time.Hour), LockedUntil: now.Add(time.Minute)}
+	if current, err := store.Begin(ctx, retry); err != nil || current != nil {
+		t.Fatalf("expected takeover of expired lock, got %+v %v", current, err)
+	}
+	if current, err := store.Begin(ctx, retry); err != nil || current == nil || !current.InProgress() {
+		t.Fatalf("expected active lock to be kept, got %+v %v", current, err)
+	}
+}
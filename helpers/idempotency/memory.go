@@ -0,0 +1,74 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+// MemoryStore sirve para una sola réplica y para pruebas.
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]Record
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: map[string]Record{}, now: time.Now}
+}
+
+func (s *MemoryStore) Begin(ctx context.Context, record Record) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.lastSweep = now
+		s.purge(now)
+	}
+	if current, found := s.records[record.Key]; found && now.Before(current.ExpiresAt) {
+		if !current.InProgress() || now.Before(current.LockedUntil) {
+			return &current, nil
+		}
+	}
+	s.records[record.Key] = record
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(ctx context.Context, key string, status int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, found := s.records[key]; found {
+		current.Status = status
+		current.ContentType = contentType
+		current.Body = body
+		s.records[key] = current
+	}
+	return nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+func (s *MemoryStore) Purge(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purge(s.now())
+	return nil
+}
+
+func (s *MemoryStore) purge(now time.Time) {
+	for key, record := range s.records {
+		if !now.Before(record.ExpiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/user0608/goones/answer"
+	"github.com/user0608/goones/errs"
+)
+
+const maxKeyLength = 255
+
+// Middleware aplica la cabecera Idempotency-Key sobre DefaultStore. La llave se
+// separa por empresa y usuario; un reintento con el mismo cuerpo devuelve la
+// respuesta guardada, con otro cuerpo se rechaza y mientras el primer intento
+// siga en proceso, hasta LockTimeout, se responde 409. Sin la cabecera la
+// petición pasa igual.
+// Debe ir después de la autenticación.
+func Middleware(retention time.Duration) echo.MiddlewareFunc {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			idempotencyKey := strings.TrimSpace(c.Request().Header.Get(HeaderKey))
+			if idempotencyKey == "" || !mutating(c.Request().Method) {
+				return next(c)
+			}
+			if len(idempotencyKey) > maxKeyLength {
+				return answer.Err(c, errs.BadRequestf("%s no debe superar los %d caracteres", HeaderKey, maxKeyLength))
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return answer.Err(c, errs.BadRequestError(err, "no se pudo leer el cuerpo de la solicitud"))
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := c.Request().Context()
+			key := scopedKey(ctx, idempotencyKey)
+			fingerprint := fingerprintOf(c, body)
+			store := DefaultStore()
+
+			now := time.Now()
+			current, err := store.Begin(ctx, Record{
+				Key:         key,
+				Fingerprint: fingerprint,
+				ExpiresAt:   now.Add(retention),
+				LockedUntil: now.Add(LockTimeout),
+			})
+			if err != nil {
+				slog.Error("idempotency store failed", "key", key, "error", err)
+				return next(c)
+			}
+			if current != nil {
+				return replay(c, *current, fingerprint)
+			}
+			return capture(c, next, store, key)
+		}
+	}
+}
+
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func scopedKey(ctx context.Context, idempotencyKey string) string {
+	return identitysdk.Empresa(ctx) + "/" + identitysdk.Username(ctx) + "/" + idempotencyKey
+}
+
+// fingerprintOf identifica la operación: la misma llave en otra ruta también se rechaza.
+func fingerprintOf(c echo.Context, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request().Method + " " + c.Path() + " " + c.Request().URL.RawQuery + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func replay(c echo.Context, current Record, fingerprint string) error {
+	if current.Fingerprint != fingerprint {
+		return answer.Err(c, errs.BadRequestf("%s ya fue usada con una solicitud distinta", HeaderKey))
+	}
+	if current.InProgress() {
+		c.Response().Header().Set("Retry-After", "1")
+		return c.JSON(http.StatusConflict, map[string]any{
+			"type":    "error",
+			"message": fmt.Sprintf("La solicitud con %s aún está en proceso", HeaderKey),
+		})
+	}
+	c.Response().Header().Set(HeaderReplayed, "true")
+	if current.ContentType != "" {
+		c.Response().Header().Set(echo.HeaderContentType, current.ContentType)
+	}
+	c.Response().WriteHeader(current.Status)
+	_, err := c.Response().Write(current.Body)
+	return err
+}
+
+type recorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// capture ejecuta la ruta y guarda la respuesta; los errores del servidor
+// liberan la llave para que el cliente pueda reintentar.
+func capture(c echo.Context, next echo.HandlerFunc, store Store, key string) (err error) {
+	// se usa un contexto propio: la petición puede cancelarse antes de guardar
+	ctx := context.WithoutCancel(c.Request().Context())
+	completed := false
+	defer func() {
+		if !completed {
+			if releaseErr := store.Release(ctx, key); releaseErr != nil {
+				slog.Error("idempotency release failed", "key", key, "error", releaseErr)
+			}
+		}
+	}()
+
+	response := c.Response()
+	rec := &recorder{ResponseWriter: response.Writer}
+	response.Writer = rec
+	err = next(c)
+	response.Writer = rec.ResponseWriter
+
+	if err != nil || !response.Committed || response.Status >= http.StatusInternalServerError {
+		return err
+	}
+	if storeErr := store.Complete(ctx, key, response.Status, response.Header().Get(echo.HeaderContentType), rec.body.Bytes()); storeErr != nil {
+		slog.Error("idempotency complete failed", "key", key, "error", storeErr)
+		return nil
+	}
+	completed = true
+	return nil
+}
@@ -0,0 +1,51 @@
+package idempotency
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+const purgeInterval = time.Hour
+
+// Module guarda los intentos en la tabla _idempotency_keys y elimina los vencidos cada hora.
+var Module = fx.Module(
+	"idempotency",
+	fx.Provide(
+		fx.Annotate(
+			NewPgStore,
+			fx.As(new(Store)),
+		),
+	),
+	fx.Invoke(SetDefaultStore),
+	fx.Invoke(startPurge),
+)
+
+func startPurge(lc fx.Lifecycle, store Store) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				ticker := time.NewTicker(purgeInterval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						if err := store.Purge(ctx); err != nil {
+							slog.Error("idempotency purge failed", "error", err)
+						}
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
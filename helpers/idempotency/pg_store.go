@@ -0,0 +1,139 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	connection "github.com/sfperusacdev/identitysdk/pg-connection"
+	"github.com/user0608/goones/errs"
+	"gorm.io/gorm"
+)
+
+type pgRecord struct {
+	Key         string `gorm:"primaryKey"`
+	Fingerprint string
+	Status      int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time
+	LockedUntil time.Time
+}
+
+func (pgRecord) TableName() string { return "_idempotency_keys" }
+
+type PgStore struct {
+	manager connection.StorageManager
+	schema  connection.Schema
+}
+
+var _ Store = (*PgStore)(nil)
+
+func NewPgStore(manager connection.StorageManager) *PgStore {
+	return &PgStore{manager: manager}
+}
+
+// idempotencyTable guarda la respuesta de cada llave hasta expires_at; el
+// índice permite que Purge elimine los vencidos sin recorrer la tabla.
+const idempotencyTable = `
+	CREATE TABLE IF NOT EXISTS _idempotency_keys (
+		key TEXT PRIMARY KEY,
+		fingerprint VARCHAR(64) NOT NULL,
+		status INTEGER NOT NULL DEFAULT 0,
+		content_type TEXT NOT NULL DEFAULT '',
+		body BYTEA,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		expires_at TIMESTAMPTZ NOT NULL,
+		locked_until TIMESTAMPTZ NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS _idempotency_keys_expires_idx ON _idempotency_keys (expires_at)`
+
+func (s *PgStore) conn(ctx context.Context) (*gorm.DB, error) {
+	tx := s.manager.Conn(ctx)
+	if tx == nil {
+		return nil, nil
+	}
+	if err := s.schema.Ensure(s.manager, idempotencyTable); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (s *PgStore) Begin(ctx context.Context, record Record) (*Record, error) {
+	tx, err := s.conn(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	// una reserva en proceso cuyo bloqueo venció pertenece a un intento que no terminó
+	const expired = `
+	DELETE FROM _idempotency_keys
+	WHERE key = ? AND (expires_at <= now() OR (status = 0 AND locked_until <= now()))`
+	if err := tx.Exec(expired, record.Key).Error; err != nil {
+		return nil, errs.Pgf(err)
+	}
+	const insert = `
+	INSERT INTO _idempotency_keys (key, fingerprint, expires_at, locked_until)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT (key) DO NOTHING`
+	result := tx.Exec(insert, record.Key, record.Fingerprint, record.ExpiresAt, record.LockedUntil)
+	if result.Error != nil {
+		return nil, errs.Pgf(result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var current pgRecord
+	if err := tx.Where("key = ?", record.Key).Take(&current).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// se liberó entre el insert y la consulta; el cliente puede reintentar
+			return &Record{Key: record.Key, Fingerprint: record.Fingerprint}, nil
+		}
+		return nil, errs.Pgf(err)
+	}
+	return &Record{
+		Key:         current.Key,
+		Fingerprint: current.Fingerprint,
+		Status:      current.Status,
+		ContentType: current.ContentType,
+		Body:        current.Body,
+		ExpiresAt:   current.ExpiresAt,
+		LockedUntil: current.LockedUntil,
+	}, nil
+}
+
+func (s *PgStore) Complete(ctx context.Context, key string, status int, contentType string, body []byte) error {
+	tx, err := s.conn(ctx)
+	if err != nil || tx == nil {
+		return err
+	}
+	if err := tx.Model(&pgRecord{}).Where("key = ?", key).Updates(map[string]any{
+		"status":       status,
+		"content_type": contentType,
+		"body":         body,
+	}).Error; err != nil {
+		return errs.Pgf(err)
+	}
+	return nil
+}
+
+func (s *PgStore) Release(ctx context.Context, key string) error {
+	tx, err := s.conn(ctx)
+	if err != nil || tx == nil {
+		return err
+	}
+	if err := tx.Where("key = ?", key).Delete(&pgRecord{}).Error; err != nil {
+		return errs.Pgf(err)
+	}
+	return nil
+}
+
+func (s *PgStore) Purge(ctx context.Context) error {
+	tx, err := s.conn(ctx)
+	if err != nil || tx == nil {
+		return err
+	}
+	if err := tx.Exec("DELETE FROM _idempotency_keys WHERE expires_at <= now()").Error; err != nil {
+		return errs.Pgf(err)
+	}
+	return nil
+}
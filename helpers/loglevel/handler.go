@@ -0,0 +1,111 @@
+package loglevel
+
+import (
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk/binds"
+	"github.com/sfperusacdev/identitysdk/httpapi"
+	"github.com/sfperusacdev/identitysdk/logging"
+	"github.com/user0608/goones/answer"
+	"github.com/user0608/goones/errs"
+)
+
+type LevelsResponse struct {
+	Base    string                `json:"base"`
+	Tenants []logging.TenantLevel `json:"tenants"`
+}
+
+// ListHandler devuelve el nivel base y los niveles vigentes por empresa.
+type ListHandler struct {
+	httpapi.AccessKeyProtection
+	httpapi.MethodGet
+}
+
+var _ httpapi.Route = (*ListHandler)(nil)
+
+func NewListHandler() *ListHandler {
+	return &ListHandler{}
+}
+
+func (h *ListHandler) GetPath() string {
+	return "/api/v1/_/log_levels"
+}
+
+func (h *ListHandler) HandleRequest(c echo.Context) error {
+	return answer.Ok(c, LevelsResponse{
+		Base:    logging.BaseLevel().String(),
+		Tenants: logging.TenantLevels(),
+	})
+}
+
+type SetLevelRequest struct {
+	Level string `json:"level"`
+	// Duration p. ej. "30m"; vacío mantiene el nivel hasta que se elimine
+	Duration string `json:"duration"`
+}
+
+// SetHandler cambia el nivel de log de una empresa, normalmente para depurar
+// un incidente sin subir el nivel de todo el servicio.
+type SetHandler struct {
+	httpapi.AccessKeyProtection
+	httpapi.MethodPut
+}
+
+var _ httpapi.Route = (*SetHandler)(nil)
+
+func NewSetHandler() *SetHandler {
+	return &SetHandler{}
+}
+
+func (h *SetHandler) GetPath() string {
+	return "/api/v1/_/log_levels/:empresa"
+}
+
+func (h *SetHandler) HandleRequest(c echo.Context) error {
+	empresa := strings.TrimSpace(c.Param("empresa"))
+	if empresa == "" {
+		return answer.Err(c, errs.BadRequestDirect("empresa es requerida"))
+	}
+	var req SetLevelRequest
+	if err := binds.JSON(c, &req); err != nil {
+		return answer.JsonErr(c)
+	}
+	if strings.TrimSpace(req.Level) == "" {
+		return answer.Err(c, errs.BadRequestDirect("level es requerido"))
+	}
+	level, err := logging.ParseLevel(req.Level)
+	if err != nil {
+		return answer.Err(c, errs.BadRequestf("nivel inválido: %s", req.Level))
+	}
+	var ttl time.Duration
+	if req.Duration != "" {
+		ttl, err = time.ParseDuration(req.Duration)
+		if err != nil || ttl <= 0 {
+			return answer.Err(c, errs.BadRequestf("duración inválida: %s", req.Duration))
+		}
+	}
+	return answer.Ok(c, logging.SetTenantLevel(empresa, level, ttl))
+}
+
+// ClearHandler devuelve la empresa al nivel base.
+type ClearHandler struct {
+	httpapi.AccessKeyProtection
+	httpapi.MethodDelete
+}
+
+var _ httpapi.Route = (*ClearHandler)(nil)
+
+func NewClearHandler() *ClearHandler {
+	return &ClearHandler{}
+}
+
+func (h *ClearHandler) GetPath() string {
+	return "/api/v1/_/log_levels/:empresa"
+}
+
+func (h *ClearHandler) HandleRequest(c echo.Context) error {
+	logging.ClearTenantLevel(c.Param("empresa"))
+	return answer.Success(c)
+}
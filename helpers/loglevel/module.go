@@ -0,0 +1,16 @@
+package loglevel
+
+import (
+	"github.com/sfperusacdev/identitysdk/httpapi"
+	"go.uber.org/fx"
+)
+
+// Module expone la consulta y el cambio del nivel de log por empresa, protegidos con access key.
+var Module = fx.Module(
+	"loglevel",
+	fx.Provide(
+		httpapi.AsRoute(NewListHandler),
+		httpapi.AsRoute(NewSetHandler),
+		httpapi.AsRoute(NewClearHandler),
+	),
+)
@@ -1,6 +1,10 @@
 package monitoring
 
-import "time"
+import (
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/xreq"
+)
 
 type Response struct {
 	Status  string    `json:"status"`
@@ -9,6 +13,8 @@ type Response struct {
 	Process Process   `json:"process"`
 	Runtime Runtime   `json:"runtime"`
 	Host    Host      `json:"host"`
+	// HTTPClient intentos del cliente HTTP compartido por resultado
+	HTTPClient map[xreq.Outcome]uint64 `json:"http_client"`
 }
 
 type Process struct {
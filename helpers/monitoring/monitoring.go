@@ -1,10 +1,13 @@
 package monitoring
 
 import (
+	"bytes"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sfperusacdev/identitysdk/httpapi"
+	"github.com/sfperusacdev/identitysdk/metrics"
 )
 
 type HandlerNameHandler struct {
@@ -23,7 +26,17 @@ func (h *HandlerNameHandler) GetPath() string {
 	return "/metrics"
 }
 
+// HandleRequest responde en el formato de texto de Prometheus; el resumen JSON
+// anterior se obtiene con ?format=json o Accept: application/json.
 func (h *HandlerNameHandler) HandleRequest(c echo.Context) error {
-	resp := h.service.Collect()
-	return c.JSON(http.StatusOK, resp)
+	if c.QueryParam("format") == "json" ||
+		strings.HasPrefix(c.Request().Header.Get(echo.HeaderAccept), echo.MIMEApplicationJSON) {
+		resp := h.service.Collect()
+		return c.JSON(http.StatusOK, resp)
+	}
+	var buff bytes.Buffer
+	if err := metrics.Default.WriteText(&buff); err != nil {
+		return err
+	}
+	return c.Blob(http.StatusOK, metrics.ContentType, buff.Bytes())
 }
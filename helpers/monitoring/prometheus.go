@@ -0,0 +1,39 @@
+package monitoring
+
+import (
+	"sync"
+
+	"github.com/sfperusacdev/identitysdk/metrics"
+)
+
+var (
+	goroutines     = metrics.NewGauge("go_goroutines", "Goroutines en ejecución.")
+	heapAlloc      = metrics.NewGauge("go_memstats_heap_alloc_bytes", "Bytes asignados en el heap.")
+	gcCycles       = metrics.NewGauge("go_gc_cycles", "Ciclos de GC completados.")
+	processThreads = metrics.NewGauge("process_threads", "Hilos del proceso.")
+	processFDs     = metrics.NewGauge("process_open_fds", "Descriptores de archivo abiertos.")
+	processUptime  = metrics.NewGauge("process_uptime_seconds", "Segundos desde que inició el servicio.")
+	hostLoad1      = metrics.NewGauge("host_load1", "Carga promedio del host en el último minuto.")
+	hostMemoryUsed = metrics.NewGauge("host_memory_used_bytes", "Memoria usada del host.")
+	hostDiskUsed   = metrics.NewGauge("host_disk_used_bytes", "Disco usado en /.")
+
+	registerOnce sync.Once
+)
+
+// registerProcessGauges actualiza las métricas del proceso y del host en cada exposición.
+func (s *MetricsService) registerProcessGauges() {
+	registerOnce.Do(func() {
+		metrics.Default.OnCollect(func() {
+			snapshot := s.Collect()
+			goroutines.With().Set(float64(snapshot.Process.Goroutines))
+			heapAlloc.With().Set(float64(snapshot.Process.HeapAlloc))
+			gcCycles.With().Set(float64(snapshot.Process.GCCycles))
+			processThreads.With().Set(float64(snapshot.Process.Threads))
+			processFDs.With().Set(float64(snapshot.Process.OpenFDs))
+			processUptime.With().Set(snapshot.Uptime)
+			hostLoad1.With().Set(snapshot.Host.Load1)
+			hostMemoryUsed.With().Set(float64(snapshot.Host.Memory.UsedBytes))
+			hostDiskUsed.With().Set(float64(snapshot.Host.Disk.UsedBytes))
+		})
+	})
+}
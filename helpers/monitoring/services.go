@@ -10,6 +10,8 @@ import (
 	"github.com/shirou/gopsutil/v4/load"
 	"github.com/shirou/gopsutil/v4/mem"
 	"github.com/shirou/gopsutil/v4/process"
+
+	"github.com/sfperusacdev/identitysdk/xreq"
 )
 
 type MetricsService struct {
@@ -19,7 +21,9 @@ type MetricsService struct {
 
 func NewMetricsService() *MetricsService {
 	p, _ := process.NewProcess(int32(os.Getpid()))
-	return &MetricsService{start: time.Now(), proc: p}
+	service := &MetricsService{start: time.Now(), proc: p}
+	service.registerProcessGauges()
+	return service
 }
 
 func (s *MetricsService) Collect() Response {
@@ -36,9 +40,10 @@ func (s *MetricsService) Collect() Response {
 	fds, _ := s.proc.NumFDs()
 
 	return Response{
-		Status: "ok",
-		Time:   time.Now().UTC(),
-		Uptime: time.Since(s.start).Seconds(),
+		Status:     "ok",
+		Time:       time.Now().UTC(),
+		Uptime:     time.Since(s.start).Seconds(),
+		HTTPClient: xreq.OutcomeStats(),
 		Process: Process{
 			PID:            os.Getpid(),
 			Goroutines:     runtime.NumGoroutine(),
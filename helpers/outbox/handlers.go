@@ -0,0 +1,90 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/sfperusacdev/identitysdk/services"
+	"github.com/sfperusacdev/identitysdk/xreq"
+)
+
+// Handler entrega un mensaje; un error programa un reintento.
+type Handler interface {
+	Handle(ctx context.Context, msg Message) error
+}
+
+type HandlerFunc func(ctx context.Context, msg Message) error
+
+func (f HandlerFunc) Handle(ctx context.Context, msg Message) error { return f(ctx, msg) }
+
+// Typed decodifica el payload en T antes de llamar a fn; un payload inválido
+// se reintenta hasta pasar a dead_letter.
+func Typed[T any](fn func(ctx context.Context, payload T) error) Handler {
+	return HandlerFunc(func(ctx context.Context, msg Message) error {
+		var payload T
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return err
+		}
+		return fn(ctx, payload)
+	})
+}
+
+// Webhook envía el payload por POST a url. La cabecera Idempotency-Key es la
+// misma en todos los reintentos del mensaje para que el destino descarte
+// duplicados.
+func Webhook(url string, opts ...xreq.RequestOption) Handler {
+	return HandlerFunc(func(ctx context.Context, msg Message) error {
+		options := []xreq.RequestOption{
+			xreq.WithMethod(http.MethodPost),
+			xreq.WithJsonContentType(),
+			xreq.WithRequestBody(bytes.NewReader(msg.Payload)),
+			xreq.WithHeader("X-Outbox-Topic", msg.Topic),
+			xreq.WithHeader("Idempotency-Key", "outbox-"+strconv.FormatInt(msg.ID, 10)),
+			xreq.WithIdempotent(),
+		}
+		return xreq.MakeRequest(ctx, url, "", append(options, opts...)...)
+	})
+}
+
+const (
+	// TopicMail tema de los correos que se envían por el servicio de mensajería
+	TopicMail = "_mensajeria.mail"
+	// TopicSMS tema de los SMS que se envían por el servicio de mensajería
+	TopicSMS = "_mensajeria.sms"
+)
+
+// MailHandler envía los mensajes de TopicMail con SendBatchMails.
+func MailHandler(bridge *services.ExternalBridgeService) Handler {
+	return Typed(func(ctx context.Context, mails []services.Mail) error {
+		return bridge.SendBatchMails(ctx, mails...)
+	})
+}
+
+// SMSHandler envía los mensajes de TopicSMS con SendBatchSMS.
+func SMSHandler(bridge *services.ExternalBridgeService) Handler {
+	return Typed(func(ctx context.Context, smss []services.SMS) error {
+		return bridge.SendBatchSMS(ctx, smss...)
+	})
+}
+
+// AddMail agrega correos al Outbox por defecto; se envían con la empresa de ctx.
+func AddMail(ctx context.Context, mails ...services.Mail) error {
+	if len(mails) == 0 {
+		slog.Warn("mails is empty, operation skipped")
+		return nil
+	}
+	return Add(ctx, TopicMail, mails)
+}
+
+// AddSMS agrega SMS al Outbox por defecto; se envían con la empresa de ctx.
+func AddSMS(ctx context.Context, smss ...services.SMS) error {
+	if len(smss) == 0 {
+		slog.Warn("sms is empty, operation skipped")
+		return nil
+	}
+	return Add(ctx, TopicSMS, smss)
+}
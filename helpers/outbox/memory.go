@@ -0,0 +1,103 @@
+package outbox
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryStore no participa de transacciones ni sobrevive a reinicios; sirve
+// para pruebas.
+type MemoryStore struct {
+	mu          sync.Mutex
+	nextID      int64
+	messages    map[int64]Message
+	lockedUntil map[int64]time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		messages:    map[int64]Message{},
+		lockedUntil: map[int64]time.Time{},
+	}
+}
+
+func (s *MemoryStore) Insert(ctx context.Context, msg *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	msg.ID = s.nextID
+	msg.CreatedAt = time.Now()
+	s.messages[msg.ID] = *msg
+	return nil
+}
+
+func (s *MemoryStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	pending := s.sorted(StatePending)
+	blocked := map[string]bool{}
+	var claimed []Message
+	for _, msg := range pending {
+		if len(claimed) >= limit {
+			break
+		}
+		if msg.Key != "" {
+			if blocked[msg.Key] {
+				continue
+			}
+			blocked[msg.Key] = true
+		}
+		if msg.AvailableAt.After(now) || s.lockedUntil[msg.ID].After(now) {
+			continue
+		}
+		s.lockedUntil[msg.ID] = now.Add(lease)
+		claimed = append(claimed, msg)
+	}
+	return claimed, nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, found := s.messages[msg.ID]; found {
+		s.messages[msg.ID] = msg
+		delete(s.lockedUntil, msg.ID)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Purge(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, msg := range s.messages {
+		if msg.State == StateDelivered && msg.DeliveredAt.Before(before) {
+			delete(s.messages, id)
+		}
+	}
+	return nil
+}
+
+// Messages devuelve los mensajes en el estado indicado, o todos si state es
+// vacío, ordenados por ID.
+func (s *MemoryStore) Messages(state State) []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sorted(state)
+}
+
+func (s *MemoryStore) sorted(state State) []Message {
+	var messages []Message
+	for _, msg := range s.messages {
+		if state == "" || msg.State == state {
+			messages = append(messages, msg)
+		}
+	}
+	slices.SortFunc(messages, func(a, b Message) int { return cmp.Compare(a.ID, b.ID) })
+	return messages
+}
@@ -0,0 +1,68 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sfperusacdev/identitysdk/services"
+	"go.uber.org/fx"
+)
+
+const SubscriptionTag = `group:"outbox-subscriptions"`
+
+type Subscription struct {
+	Topic   string
+	Handler Handler
+}
+
+// AsSubscription registra en el Outbox la Subscription que devuelve fn.
+func AsSubscription(fn any) any {
+	return fx.Annotate(
+		fn,
+		fx.ResultTags(SubscriptionTag),
+	)
+}
+
+type outboxParams struct {
+	fx.In
+	Lifecycle     fx.Lifecycle
+	Store         Store
+	Subscriptions []Subscription                  `group:"outbox-subscriptions"`
+	Bridge        *services.ExternalBridgeService `optional:"true"`
+}
+
+func newOutbox(p outboxParams) *Outbox {
+	o := New(p.Store, Config{})
+	if p.Bridge != nil {
+		o.Subscribe(TopicMail, MailHandler(p.Bridge))
+		o.Subscribe(TopicSMS, SMSHandler(p.Bridge))
+	} else {
+		slog.Warn("ExternalBridgeService is not available, outbox mail and sms handlers skipped")
+	}
+	for _, subscription := range p.Subscriptions {
+		o.Subscribe(subscription.Topic, subscription.Handler)
+	}
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			o.Start()
+			return nil
+		},
+		OnStop: o.Stop,
+	})
+	return o
+}
+
+// Module guarda los mensajes de Add en _outbox y los entrega a las
+// suscripciones registradas con AsSubscription; los correos y SMS de
+// AddMail/AddSMS se envían por el servicio de mensajería.
+var Module = fx.Module(
+	"outbox",
+	fx.Provide(
+		fx.Annotate(
+			NewPgStore,
+			fx.As(new(Store)),
+		),
+		newOutbox,
+	),
+	fx.Invoke(SetDefault),
+)
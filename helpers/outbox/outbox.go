@@ -0,0 +1,378 @@
+// Package outbox guarda los efectos secundarios de una operación (correos,
+// SMS, llamadas a otros servicios) en la misma transacción que sus datos y
+// los entrega después del commit.
+//
+// Add escribe el mensaje con la transacción de StorageManager.WithTx que lleve
+// el contexto; si la transacción se revierte, el mensaje desaparece con ella.
+// El dispatcher reserva los mensajes listos, los entrega a los handlers de su
+// tema en un DomainExecutor y reintenta con backoff hasta MaxAttempts. Los
+// mensajes con la misma clave se entregan en orden, uno a la vez, aun con
+// varias réplicas. La entrega es al menos una vez: los handlers deben tolerar
+// duplicados.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/helpers/domainexecutor"
+	connection "github.com/sfperusacdev/identitysdk/pg-connection"
+)
+
+type State string
+
+const (
+	StatePending    State = "pending"
+	StateDelivered  State = "delivered"
+	StateDeadLetter State = "dead_letter"
+)
+
+var ErrNotConfigured = errors.New("outbox not configured")
+
+type Message struct {
+	ID    int64
+	Topic string
+	// Key ordena la entrega: los mensajes con la misma clave se entregan en
+	// el orden en que se agregaron. Vacía no impone orden
+	Key     string
+	Empresa string
+	Payload json.RawMessage
+	State   State
+	// Attempts intentos de entrega realizados
+	Attempts    int
+	LastError   string
+	AvailableAt time.Time
+	CreatedAt   time.Time
+	DeliveredAt time.Time
+}
+
+// Store guarda los mensajes del outbox.
+type Store interface {
+	// Insert guarda el mensaje con la transacción de ctx, si la hay.
+	Insert(ctx context.Context, msg *Message) error
+	// Claim reserva por lease hasta limit mensajes listos, solo el más antiguo
+	// pendiente de cada clave, para que ninguna otra réplica los tome.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]Message, error)
+	// Update guarda el resultado de un intento y libera la reserva.
+	Update(ctx context.Context, msg Message) error
+	// Purge elimina los mensajes entregados antes de before.
+	Purge(ctx context.Context, before time.Time) error
+}
+
+type Config struct {
+	// PollInterval espera entre consultas cuando no hay mensajes; por defecto 1s
+	PollInterval time.Duration
+	// BatchSize mensajes reservados por consulta; por defecto 100
+	BatchSize int
+	// Lease tiempo que un mensaje reservado queda fuera del alcance de otras
+	// réplicas; por defecto 5m
+	Lease time.Duration
+	// DeliveryTimeout límite de cada entrega; por defecto 30s
+	DeliveryTimeout time.Duration
+	// MaxAttempts intentos antes de pasar el mensaje a dead_letter; por defecto 10
+	MaxAttempts int
+	// InitialBackoff espera tras el primer fallo, se duplica en cada intento; por defecto 1s
+	InitialBackoff time.Duration
+	// MaxBackoff espera máxima entre intentos; por defecto 10m
+	MaxBackoff time.Duration
+	// Retention tiempo que se conservan los mensajes entregados; por defecto 7 días
+	Retention time.Duration
+}
+
+const purgeInterval = time.Hour
+
+type AddOption func(*Message)
+
+// WithKey entrega en orden los mensajes de un mismo agregado, p. ej. "trabajador:T001".
+func WithKey(key string) AddOption {
+	return func(m *Message) {
+		m.Key = key
+	}
+}
+
+type Outbox struct {
+	store    Store
+	cfg      Config
+	executor *domainexecutor.DomainExecutor
+	now      func() time.Time
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+
+	runMu  sync.Mutex
+	stop   chan struct{}
+	done   chan struct{}
+	purged time.Time
+}
+
+func New(store Store, cfg Config) *Outbox {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = 5 * time.Minute
+	}
+	if cfg.DeliveryTimeout <= 0 {
+		cfg.DeliveryTimeout = 30 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 10
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 10 * time.Minute
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 7 * 24 * time.Hour
+	}
+	return &Outbox{
+		store: store,
+		cfg:   cfg,
+		executor: domainexecutor.New(domainexecutor.Config{
+			IdleEvictAfter: time.Minute,
+			QueueCapacity:  1,
+			Name:           "outbox",
+		}),
+		now:      time.Now,
+		handlers: map[string][]Handler{},
+	}
+}
+
+// Subscribe agrega un handler al tema. Un reintento vuelve a entregar el
+// mensaje a todos los handlers del tema.
+func (o *Outbox) Subscribe(topic string, handler Handler) {
+	if handler == nil {
+		slog.Warn("outbox handler is nil, operation skipped")
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.handlers[topic] = append(o.handlers[topic], handler)
+}
+
+// Add guarda el mensaje con la transacción de ctx; payload se serializa como JSON.
+func (o *Outbox) Add(ctx context.Context, topic string, payload any, opts ...AddOption) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	msg := Message{
+		Topic:       topic,
+		Empresa:     empresa(ctx),
+		Payload:     raw,
+		State:       StatePending,
+		AvailableAt: o.now(),
+	}
+	for _, apply := range opts {
+		apply(&msg)
+	}
+	if !connection.InTx(ctx) {
+		slog.Warn("outbox message added outside a transaction, it is committed immediately", "topic", topic)
+	}
+	return o.store.Insert(ctx, &msg)
+}
+
+func (o *Outbox) Start() {
+	o.runMu.Lock()
+	defer o.runMu.Unlock()
+	if o.stop != nil {
+		return
+	}
+	o.stop = make(chan struct{})
+	o.done = make(chan struct{})
+	go o.loop(o.stop, o.done)
+}
+
+// Stop espera a que termine la entrega en curso; los mensajes pendientes
+// quedan en el store para la siguiente ejecución.
+func (o *Outbox) Stop(ctx context.Context) error {
+	o.runMu.Lock()
+	stop, done := o.stop, o.done
+	o.stop, o.done = nil, nil
+	o.runMu.Unlock()
+	if stop == nil {
+		return nil
+	}
+	close(stop)
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (o *Outbox) loop(stop, done chan struct{}) {
+	defer close(done)
+	for {
+		claimed, err := o.dispatch(context.Background())
+		if err != nil {
+			slog.Error("outbox dispatch failed", "error", err)
+		}
+		o.purge()
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		// con mensajes entregados puede haber otros de la misma clave esperando
+		if claimed > 0 && err == nil {
+			continue
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(o.cfg.PollInterval):
+		}
+	}
+}
+
+func (o *Outbox) purge() {
+	now := o.now()
+	if now.Sub(o.purged) < purgeInterval {
+		return
+	}
+	o.purged = now
+	if err := o.store.Purge(context.Background(), now.Add(-o.cfg.Retention)); err != nil {
+		slog.Error("outbox purge failed", "error", err)
+	}
+}
+
+// dispatch reserva un lote y lo entrega; devuelve cuántos mensajes reservó.
+func (o *Outbox) dispatch(ctx context.Context) (int, error) {
+	messages, err := o.store.Claim(ctx, o.cfg.BatchSize, o.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+	var wg sync.WaitGroup
+	for _, msg := range messages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			domain := msg.Key
+			if domain == "" {
+				domain = "#" + strconv.FormatInt(msg.ID, 10)
+			}
+			err := o.executor.Execute(ctx, domain, func(ctx context.Context) error {
+				return o.deliver(ctx, msg)
+			}, nil)
+			o.settle(msg, err)
+		}()
+	}
+	wg.Wait()
+	return len(messages), nil
+}
+
+func (o *Outbox) deliver(ctx context.Context, msg Message) (err error) {
+	o.mu.RLock()
+	handlers := o.handlers[msg.Topic]
+	o.mu.RUnlock()
+	if len(handlers) == 0 {
+		return fmt.Errorf("no outbox handler for topic %s", msg.Topic)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.cfg.DeliveryTimeout)
+	defer cancel()
+	if msg.Empresa != "" {
+		ctx = identitysdk.CtxWithDomain(ctx, msg.Empresa)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler.Handle(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (o *Outbox) settle(msg Message, err error) {
+	msg.Attempts++
+	switch {
+	case err == nil:
+		msg.State = StateDelivered
+		msg.LastError = ""
+		msg.DeliveredAt = o.now()
+	case msg.Attempts >= o.cfg.MaxAttempts:
+		msg.State = StateDeadLetter
+		msg.LastError = err.Error()
+		slog.Error("outbox message moved to dead letter", "id", msg.ID, "topic", msg.Topic, "error", err)
+	default:
+		msg.LastError = err.Error()
+		msg.AvailableAt = o.now().Add(o.backoff(msg.Attempts))
+		slog.Warn("outbox delivery failed, retrying", "id", msg.ID, "topic", msg.Topic, "attempts", msg.Attempts, "error", err)
+	}
+	if err := o.store.Update(context.Background(), msg); err != nil {
+		slog.Error("outbox update failed", "id", msg.ID, "error", err)
+	}
+}
+
+func (o *Outbox) backoff(attempts int) time.Duration {
+	wait := o.cfg.InitialBackoff
+	for i := 1; i < attempts && wait < o.cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, o.cfg.MaxBackoff)
+}
+
+// empresa devuelve la empresa de la sesión o "" si el contexto no tiene identidad.
+func empresa(ctx context.Context) string {
+	value := identitysdk.Empresa(ctx)
+	if len(value) >= 4 && value[:4] == "####" {
+		return ""
+	}
+	return value
+}
+
+var (
+	defaultOutboxMu sync.RWMutex
+	defaultOutbox   *Outbox
+)
+
+// SetDefault define el Outbox de Add; lo registra Module.
+func SetDefault(o *Outbox) {
+	if o == nil {
+		return
+	}
+	defaultOutboxMu.Lock()
+	defer defaultOutboxMu.Unlock()
+	defaultOutbox = o
+}
+
+func Default() *Outbox {
+	defaultOutboxMu.RLock()
+	defer defaultOutboxMu.RUnlock()
+	return defaultOutbox
+}
+
+// Add guarda el mensaje en el Outbox por defecto dentro de la transacción de ctx.
+//
+//	err := storage.WithTx(ctx, func(ctx context.Context) error {
+//		if err := repo.Save(ctx, solicitud); err != nil {
+//			return err
+//		}
+//		return outbox.Add(ctx, "solicitudes.aprobada", solicitud, outbox.WithKey(solicitud.ID))
+//	})
+func Add(ctx context.Context, topic string, payload any, opts ...AddOption) error {
+	o := Default()
+	if o == nil {
+		return ErrNotConfigured
+	}
+	return o.Add(ctx, topic, payload, opts...)
+}
@@ -0,0 +1,148 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/helpers/outbox"
+)
+
+type evento struct {
+	Trabajador string `json:"trabajador"`
+	Orden      int    `json:"orden"`
+}
+
+func testConfig() outbox.Config {
+	return outbox.Config{
+		PollInterval:   10 * time.Millisecond,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     20 * time.Millisecond,
+		MaxAttempts:    3,
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not reached")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestOutboxDeliversInOrderPerKeyWithRetries(t *testing.T) {
+	store := outbox.NewMemoryStore()
+	o := outbox.New(store, testConfig())
+
+	var mu sync.Mutex
+	var delivered []int
+	var empresas []string
+	failed := false
+	o.Subscribe("trabajador.actualizado", outbox.Typed(func(ctx context.Context, e evento) error {
+		mu.Lock()
+		defer mu.Unlock()
+		// el primer mensaje falla una vez: el segundo de la misma clave debe esperarlo
+		if e.Orden == 1 && !failed {
+			failed = true
+			return errors.New("servicio no disponible")
+		}
+		delivered = append(delivered, e.Orden)
+		empresas = append(empresas, identitysdk.Empresa(ctx))
+		return nil
+	}))
+
+	ctx := identitysdk.CtxWithDomain(context.Background(), "sfperu")
+	for orden := 1; orden <= 3; orden++ {
+		if err := o.Add(ctx, "trabajador.actualizado", evento{Trabajador: "T001", Orden: orden}, outbox.WithKey("trabajador:T001")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	o.Start()
+	waitFor(t, func() bool { return len(store.Messages(outbox.StateDelivered)) == 3 })
+	if err := o.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(delivered, []int{1, 2, 3}) {
+		t.Fatalf("expected ordered delivery, got %v", delivered)
+	}
+	if slices.ContainsFunc(empresas, func(e string) bool { return e != "sfperu" }) {
+		t.Fatalf("expected handlers to run with the message empresa, got %v", empresas)
+	}
+	if first := store.Messages(outbox.StateDelivered)[0]; first.Attempts != 2 || first.DeliveredAt.IsZero() {
+		t.Fatalf("unexpected first message %+v", first)
+	}
+}
+
+func TestOutboxMovesToDeadLetter(t *testing.T) {
+	store := outbox.NewMemoryStore()
+	o := outbox.New(store, testConfig())
+	o.Subscribe("reporte.generado", outbox.HandlerFunc(func(ctx context.Context, msg outbox.Message) error {
+		return errors.New("destino rechazó el mensaje")
+	}))
+	if err := o.Add(context.Background(), "reporte.generado", map[string]string{"id": "R1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Add(context.Background(), "sin.handler", nil); err != nil {
+		t.Fatal(err)
+	}
+	o.Start()
+	waitFor(t, func() bool { return len(store.Messages(outbox.StateDeadLetter)) == 2 })
+	if err := o.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range store.Messages(outbox.StateDeadLetter) {
+		if msg.Attempts != 3 || msg.LastError == "" {
+			t.Fatalf("unexpected dead letter %+v", msg)
+		}
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		headers http.Header
+		body    string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		headers, body = r.Header.Clone(), string(raw)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := outbox.NewMemoryStore()
+	o := outbox.New(store, testConfig())
+	o.Subscribe("planilla.cerrada", outbox.Webhook(server.URL+"/hooks/planilla"))
+	if err := o.Add(context.Background(), "planilla.cerrada", evento{Trabajador: "T002", Orden: 7}); err != nil {
+		t.Fatal(err)
+	}
+	o.Start()
+	waitFor(t, func() bool { return len(store.Messages(outbox.StateDelivered)) == 1 })
+	if err := o.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if body != `{"trabajador":"T002","orden":7}` {
+		t.Fatalf("unexpected body %s", body)
+	}
+	if headers.Get("X-Outbox-Topic") != "planilla.cerrada" || headers.Get("Idempotency-Key") != "outbox-1" {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+}
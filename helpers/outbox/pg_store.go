@@ -0,0 +1,180 @@
+package outbox
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	connection "github.com/sfperusacdev/identitysdk/pg-connection"
+	"github.com/user0608/goones/errs"
+	"gorm.io/gorm"
+)
+
+// PgStore guarda los mensajes en la tabla _outbox. Insert usa la transacción
+// de ctx; el dispatcher trabaja con su propia conexión.
+type PgStore struct {
+	manager connection.StorageManager
+	schema  connection.Schema
+}
+
+var _ Store = (*PgStore)(nil)
+
+func NewPgStore(manager connection.StorageManager) *PgStore {
+	return &PgStore{manager: manager}
+}
+
+// outboxTable guarda los mensajes hasta entregarlos; los índices parciales
+// cubren la búsqueda de pendientes y el orden por llave.
+const outboxTable = `
+	CREATE TABLE IF NOT EXISTS _outbox (
+		id BIGSERIAL PRIMARY KEY,
+		topic VARCHAR(255) NOT NULL,
+		key VARCHAR(255),
+		empresa VARCHAR(255) NOT NULL DEFAULT '',
+		payload JSONB NOT NULL DEFAULT 'null',
+		state VARCHAR(32) NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		available_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		locked_until TIMESTAMPTZ,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		delivered_at TIMESTAMPTZ
+	);
+	CREATE INDEX IF NOT EXISTS _outbox_pending_idx ON _outbox (id) WHERE state = 'pending';
+	CREATE INDEX IF NOT EXISTS _outbox_pending_key_idx ON _outbox (key, id) WHERE state = 'pending'`
+
+func (s *PgStore) conn() (*gorm.DB, error) {
+	tx := s.manager.Conn(context.Background())
+	if tx == nil {
+		return nil, nil
+	}
+	if err := s.schema.Ensure(s.manager, outboxTable); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (s *PgStore) Insert(ctx context.Context, msg *Message) error {
+	if err := s.schema.Ensure(s.manager, outboxTable); err != nil {
+		return err
+	}
+	tx := s.manager.Conn(ctx)
+	if tx == nil {
+		return nil // skip
+	}
+	const insert = `
+	INSERT INTO _outbox (topic, key, empresa, payload, state, available_at)
+	VALUES (?, ?, ?, ?, ?, ?)
+	RETURNING id, created_at`
+	row := tx.WithContext(ctx).Raw(insert,
+		msg.Topic,
+		nullString(msg.Key),
+		msg.Empresa,
+		string(msg.Payload),
+		string(msg.State),
+		msg.AvailableAt,
+	).Row()
+	if err := row.Scan(&msg.ID, &msg.CreatedAt); err != nil {
+		return errs.Pgf(err)
+	}
+	return nil
+}
+
+// Claim toma solo el mensaje pendiente más antiguo de cada clave; los demás
+// esperan a que ese se entregue o pase a dead_letter. SKIP LOCKED evita que
+// dos réplicas se bloqueen entre sí mientras reservan.
+func (s *PgStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]Message, error) {
+	tx, err := s.conn()
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	const claim = `
+	UPDATE _outbox SET locked_until = now() + make_interval(secs => ?)
+	WHERE id IN (
+		SELECT o.id FROM _outbox o
+		WHERE o.state = 'pending'
+			AND o.available_at <= now()
+			AND (o.locked_until IS NULL OR o.locked_until < now())
+			AND (o.key IS NULL OR NOT EXISTS (
+				SELECT 1 FROM _outbox p
+				WHERE p.key = o.key AND p.state = 'pending' AND p.id < o.id
+			))
+		ORDER BY o.id
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id, topic, COALESCE(key, ''), empresa, payload, state, attempts, last_error, available_at, created_at`
+	rows, err := tx.WithContext(ctx).Raw(claim, lease.Seconds(), limit).Rows()
+	if err != nil {
+		return nil, errs.Pgf(err)
+	}
+	defer rows.Close()
+	var messages []Message
+	for rows.Next() {
+		var (
+			msg     Message
+			payload []byte
+			state   string
+		)
+		if err := rows.Scan(
+			&msg.ID, &msg.Topic, &msg.Key, &msg.Empresa, &payload, &state,
+			&msg.Attempts, &msg.LastError, &msg.AvailableAt, &msg.CreatedAt,
+		); err != nil {
+			return nil, errs.Pgf(err)
+		}
+		msg.Payload = payload
+		msg.State = State(state)
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errs.Pgf(err)
+	}
+	// RETURNING no garantiza el orden
+	slices.SortFunc(messages, func(a, b Message) int { return cmp.Compare(a.ID, b.ID) })
+	return messages, nil
+}
+
+func (s *PgStore) Update(ctx context.Context, msg Message) error {
+	tx, err := s.conn()
+	if err != nil || tx == nil {
+		return err
+	}
+	var deliveredAt sql.NullTime
+	if !msg.DeliveredAt.IsZero() {
+		deliveredAt = sql.NullTime{Time: msg.DeliveredAt, Valid: true}
+	}
+	const update = `
+	UPDATE _outbox
+	SET state = ?, attempts = ?, last_error = ?, available_at = ?, delivered_at = ?, locked_until = NULL
+	WHERE id = ?`
+	if err := tx.WithContext(ctx).Exec(update,
+		string(msg.State),
+		msg.Attempts,
+		msg.LastError,
+		msg.AvailableAt,
+		deliveredAt,
+		msg.ID,
+	).Error; err != nil {
+		return errs.Pgf(err)
+	}
+	return nil
+}
+
+func (s *PgStore) Purge(ctx context.Context, before time.Time) error {
+	tx, err := s.conn()
+	if err != nil || tx == nil {
+		return err
+	}
+	if err := tx.WithContext(ctx).
+		Exec("DELETE FROM _outbox WHERE state = ? AND delivered_at < ?", string(StateDelivered), before).
+		Error; err != nil {
+		return errs.Pgf(err)
+	}
+	return nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
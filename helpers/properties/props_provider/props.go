@@ -10,6 +10,7 @@ import (
 
 	"github.com/sfperusacdev/identitysdk"
 
+	"github.com/sfperusacdev/identitysdk/helpers/audit"
 	"github.com/sfperusacdev/identitysdk/helpers/properties"
 	"github.com/sfperusacdev/identitysdk/helpers/properties/models"
 	connection "github.com/sfperusacdev/identitysdk/pg-connection"
@@ -213,9 +214,16 @@ func (r *SystemPropsPgProvider) Update(ctx context.Context, entries []models.Bas
 
 		placeholders := make([]string, 0, len(entries))
 		values := make([]any, 0, len(entries)*2)
+		keys := make([]string, 0, len(entries))
 		for _, entry := range entries {
 			placeholders = append(placeholders, "(?, ?)")
 			values = append(values, identitysdk.Empresa(ctx, entry.ID), entry.Value)
+			keys = append(keys, identitysdk.Empresa(ctx, entry.ID))
+		}
+
+		var previous []PropItem
+		if err := tx.Where("key in ?", keys).Select("key", "value").Find(&previous).Error; err != nil {
+			return errs.Pgf(err)
 		}
 
 		finalQry := fmt.Sprintf(qry, strings.Join(placeholders, ","))
@@ -223,6 +231,25 @@ func (r *SystemPropsPgProvider) Update(ctx context.Context, entries []models.Bas
 			return errs.Pgf(err)
 		}
 
+		previousValues := make(map[string]string, len(previous))
+		for _, item := range previous {
+			previousValues[item.Key] = item.Value
+		}
+		events := make([]audit.Event, 0, len(entries))
+		for _, entry := range entries {
+			old, found := previousValues[identitysdk.Empresa(ctx, entry.ID)]
+			if !found || old == entry.Value {
+				continue
+			}
+			events = append(events, audit.Event{
+				Action:    "system_properties.update",
+				Entity:    "_system_properties",
+				EntityKey: entry.ID,
+				Old:       old,
+				New:       entry.Value,
+			})
+		}
+		audit.RecordOrLog(ctx, events...)
 		return nil
 	})
 
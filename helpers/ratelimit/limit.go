@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Scope indica a quién se le aplica el límite.
+type Scope string
+
+const (
+	ScopeEmpresa  Scope = "empresa"
+	ScopeUsername Scope = "username"
+	// ScopeApiKey limita por la credencial de la petición (api key o token).
+	ScopeApiKey Scope = "api_key"
+	ScopeIP     Scope = "ip"
+)
+
+// Limit es un token bucket: Requests por Per, con capacidad Burst (por defecto Requests).
+type Limit struct {
+	Scope    Scope
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+func PerSecond(scope Scope, requests int) Limit {
+	return Limit{Scope: scope, Requests: requests, Per: time.Second}
+}
+
+func PerMinute(scope Scope, requests int) Limit {
+	return Limit{Scope: scope, Requests: requests, Per: time.Minute}
+}
+
+func PerHour(scope Scope, requests int) Limit {
+	return Limit{Scope: scope, Requests: requests, Per: time.Hour}
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// ratePerMillisecond devuelve los tokens que se recuperan por milisegundo.
+func (l Limit) ratePerMillisecond() float64 {
+	return float64(l.Requests) / float64(l.Per.Milliseconds())
+}
+
+func (l Limit) valid() bool {
+	return l.Requests > 0 && l.Per >= time.Millisecond
+}
+
+type Decision struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Store guarda los buckets; el de memoria sirve para una réplica y el de
+// Redis comparte los límites entre réplicas.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Decision, error)
+	// Refund devuelve al bucket el token de un Take permitido.
+	Refund(ctx context.Context, key string, limit Limit) error
+}
+
+var (
+	defaultStoreMu sync.RWMutex
+	defaultStore   Store = NewMemoryStore()
+)
+
+func SetDefaultStore(store Store) {
+	if store == nil {
+		return
+	}
+	defaultStoreMu.Lock()
+	defer defaultStoreMu.Unlock()
+	defaultStore = store
+}
+
+func DefaultStore() Store {
+	defaultStoreMu.RLock()
+	defer defaultStoreMu.RUnlock()
+	return defaultStore
+}
@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	idleFor time.Duration
+}
+
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Decision, error) {
+	if !limit.valid() {
+		return Decision{Allowed: true}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	capacity, rate := limit.capacity(), limit.ratePerMillisecond()
+	b, found := s.buckets[key]
+	if !found {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+	// tiempo para llenar el bucket desde cero; pasado ese tiempo se puede descartar
+	b.idleFor = time.Duration(capacity/rate) * time.Millisecond
+	elapsed := float64(now.Sub(b.updated).Milliseconds())
+	b.tokens = math.Min(capacity, b.tokens+math.Max(0, elapsed)*rate)
+	b.updated = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return Decision{Allowed: true, Remaining: int(b.tokens)}, nil
+	}
+	wait := math.Ceil((1 - b.tokens) / rate)
+	return Decision{RetryAfter: time.Duration(wait) * time.Millisecond}, nil
+}
+
+func (s *MemoryStore) Refund(ctx context.Context, key string, limit Limit) error {
+	if !limit.valid() {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b, found := s.buckets[key]; found {
+		b.tokens = math.Min(limit.capacity(), b.tokens+1)
+	}
+	return nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) > b.idleFor {
+			delete(s.buckets, key)
+		}
+	}
+}
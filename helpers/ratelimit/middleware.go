@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk"
+)
+
+// Middleware aplica los límites sobre DefaultStore. Debe ir después de la
+// autenticación para conocer la empresa, el usuario o la api key; si el
+// sujeto no se conoce se limita por IP. Si el store falla la petición pasa.
+// Si un límite rechaza, se devuelven los tokens ya tomados de los anteriores.
+func Middleware(limits ...Limit) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			route := c.Request().Method + " " + c.Path()
+			store := DefaultStore()
+			taken := make([]int, 0, len(limits))
+			keys := make([]string, len(limits))
+			for i, limit := range limits {
+				scope, subject := subjectFor(c, limit.Scope)
+				keys[i] = string(scope) + ":" + subject + ":" + route
+				decision, err := store.Take(ctx, keys[i], limit)
+				if err != nil {
+					slog.Error("rate limit store failed", "key", keys[i], "error", err)
+					continue
+				}
+				if !decision.Allowed {
+					for _, j := range taken {
+						if err := store.Refund(ctx, keys[j], limits[j]); err != nil {
+							slog.Error("rate limit refund failed", "key", keys[j], "error", err)
+						}
+					}
+					return tooManyRequests(c, limit, decision)
+				}
+				taken = append(taken, i)
+			}
+			return next(c)
+		}
+	}
+}
+
+func subjectFor(c echo.Context, scope Scope) (Scope, string) {
+	ctx := c.Request().Context()
+	var subject string
+	switch scope {
+	case ScopeEmpresa:
+		subject = identitysdk.Empresa(ctx)
+	case ScopeUsername:
+		subject = identitysdk.Empresa(ctx) + "/" + identitysdk.Username(ctx)
+	case ScopeApiKey:
+		if token := identitysdk.Token(ctx); !strings.HasPrefix(token, "####") {
+			sum := sha256.Sum256([]byte(token))
+			subject = hex.EncodeToString(sum[:12])
+		}
+	}
+	if subject == "" || strings.Contains(subject, "####") {
+		return ScopeIP, c.RealIP()
+	}
+	return scope, subject
+}
+
+func tooManyRequests(c echo.Context, limit Limit, decision Decision) error {
+	seconds := int(math.Ceil(decision.RetryAfter.Seconds()))
+	c.Response().Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+	message := fmt.Sprintf("Demasiadas solicitudes: límite de %d por %s (%s), intente nuevamente en %d s",
+		limit.Requests, limit.Per, limit.Scope, max(seconds, 1))
+	return c.JSON(http.StatusTooManyRequests, map[string]any{"type": "error", "message": message})
+}
@@ -0,0 +1,116 @@
+package ratelimit_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/helpers/ratelimit"
+)
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+	limit := ratelimit.Limit{Scope: ratelimit.ScopeEmpresa, Requests: 2, Per: 100 * time.Millisecond}
+
+	for i := range 2 {
+		if d, _ := store.Take(ctx, "k", limit); !d.Allowed {
+			t.Fatalf("request %d should be allowed", i)
+		}
+	}
+	d, _ := store.Take(ctx, "k", limit)
+	if d.Allowed || d.RetryAfter <= 0 || d.RetryAfter > 50*time.Millisecond {
+		t.Fatalf("expected denial with retry after ~50ms, got %+v", d)
+	}
+	if d, _ := store.Take(ctx, "otro", limit); !d.Allowed {
+		t.Fatal("buckets must be independent per key")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if d, _ := store.Take(ctx, "k", limit); !d.Allowed {
+		t.Fatalf("expected refill after wait, got %+v", d)
+	}
+}
+
+func TestMiddlewareLimitsPerEmpresa(t *testing.T) {
+	ratelimit.SetDefaultStore(ratelimit.NewMemoryStore())
+
+	e := echo.New()
+	e.GET("/items", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := identitysdk.CtxWithDomain(c.Request().Context(), c.QueryParam("empresa"))
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}, ratelimit.Middleware(ratelimit.PerMinute(ratelimit.ScopeEmpresa, 1)))
+
+	call := func(empresa string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items?empresa="+empresa, nil))
+		return rec
+	}
+
+	if rec := call("sfperu"); rec.Code != http.StatusOK {
+		t.Fatalf("expected first request to pass, got %d", rec.Code)
+	}
+	rec := call("sfperu")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "60" {
+		t.Fatalf("expected 429 with Retry-After, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	var body struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Type != "error" || body.Message == "" {
+		t.Fatalf("expected answer error body, got %s", rec.Body.String())
+	}
+	if rec := call("otra"); rec.Code != http.StatusOK {
+		t.Fatalf("other companies must not be limited, got %d", rec.Code)
+	}
+}
+
+func TestMiddlewareRefundsEarlierLimitsOnDenial(t *testing.T) {
+	ratelimit.SetDefaultStore(ratelimit.NewMemoryStore())
+
+	e := echo.New()
+	e.GET("/items", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := identitysdk.CtxWithDomain(c.Request().Context(), "sfperu")
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}, ratelimit.Middleware(
+		ratelimit.PerMinute(ratelimit.ScopeEmpresa, 2),
+		ratelimit.PerMinute(ratelimit.ScopeIP, 1),
+	))
+
+	call := func(ip string) int {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := call("10.0.0.1"); code != http.StatusOK {
+		t.Fatalf("expected first request to pass, got %d", code)
+	}
+	for range 3 {
+		if code := call("10.0.0.1"); code != http.StatusTooManyRequests {
+			t.Fatalf("expected the ip limit to deny, got %d", code)
+		}
+	}
+	// las denegaciones por IP no deben consumir el límite de la empresa
+	if code := call("10.0.0.2"); code != http.StatusOK {
+		t.Fatalf("denied requests must not spend the empresa bucket, got %d", code)
+	}
+}
@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// tokenBucketScript aplica el token bucket de forma atómica usando el reloj de Redis.
+const tokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed, wait = 0, 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  wait = math.ceil((1 - tokens) / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], ttl)
+return {allowed, math.floor(tokens), wait}
+`
+
+// refundScript devuelve un token sin pasar la capacidad; si el bucket expiró no hace nada.
+const refundScript = `
+local tokens = tonumber(redis.call('HGET', KEYS[1], 'tokens'))
+if tokens then
+  redis.call('HSET', KEYS[1], 'tokens', tostring(math.min(tonumber(ARGV[1]), tokens + 1)))
+end
+return 0
+`
+
+// RedisClient ejecuta comandos crudos; *cache.RedisStore lo implementa.
+type RedisClient interface {
+	Do(ctx context.Context, args ...any) (any, error)
+}
+
+type RedisStore struct {
+	client RedisClient
+	prefix string
+}
+
+var _ Store = (*RedisStore)(nil)
+
+func NewRedisStore(client RedisClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix + "ratelimit:"}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit) (Decision, error) {
+	if !limit.valid() {
+		return Decision{Allowed: true}, nil
+	}
+	capacity, rate := limit.capacity(), limit.ratePerMillisecond()
+	ttl := int64(capacity/rate) + 1000
+	reply, err := s.client.Do(ctx, "EVAL", tokenBucketScript, 1, s.prefix+key, rate, capacity, ttl)
+	if err != nil {
+		return Decision{}, err
+	}
+	values, ok := reply.([]any)
+	if !ok || len(values) != 3 {
+		return Decision{}, fmt.Errorf("ratelimit: unexpected redis reply %v", reply)
+	}
+	var numbers [3]int64
+	for i, value := range values {
+		if numbers[i], ok = value.(int64); !ok {
+			return Decision{}, fmt.Errorf("ratelimit: unexpected redis reply %v", reply)
+		}
+	}
+	return Decision{
+		Allowed:    numbers[0] == 1,
+		Remaining:  int(numbers[1]),
+		RetryAfter: time.Duration(numbers[2]) * time.Millisecond,
+	}, nil
+}
+
+func (s *RedisStore) Refund(ctx context.Context, key string, limit Limit) error {
+	if !limit.valid() {
+		return nil
+	}
+	_, err := s.client.Do(ctx, "EVAL", refundScript, 1, s.prefix+key, limit.capacity())
+	return err
+}
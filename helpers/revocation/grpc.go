@@ -0,0 +1,39 @@
+package revocation
+
+import (
+	"context"
+
+	"github.com/sfperusacdev/identitysdk"
+	identitypb "github.com/sfperusacdev/identitysdk/grpc/gen/identity"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type RevocationGrpcService struct {
+	identitypb.UnimplementedRevocationServiceServer
+}
+
+func NewRevocationGrpcService() *RevocationGrpcService {
+	return &RevocationGrpcService{}
+}
+
+func (s *RevocationGrpcService) Register(server gogrpc.ServiceRegistrar) {
+	identitypb.RegisterRevocationServiceServer(server, s)
+}
+
+func (s *RevocationGrpcService) Revoke(ctx context.Context, req *identitypb.RevokeRequest) (*identitypb.RevokeResponse, error) {
+	if len(req.GetTokens()) == 0 && len(req.GetUsernames()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "se requiere al menos un token o usuario")
+	}
+	tokens := make([]identitysdk.RevokedToken, 0, len(req.GetTokens()))
+	for _, token := range req.GetTokens() {
+		tokens = append(tokens, identitysdk.RevokedToken{ID: token.GetId(), ExpiresAt: token.GetExp()})
+	}
+	identitysdk.RevokeTokens(ctx, tokens...)
+	identitysdk.RevokeUsers(ctx, req.GetUsernames()...)
+	return &identitypb.RevokeResponse{
+		RevokedTokens:    int32(len(tokens)),
+		RevokedUsernames: int32(len(req.GetUsernames())),
+	}, nil
+}
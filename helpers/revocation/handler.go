@@ -0,0 +1,44 @@
+package revocation
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/binds"
+	"github.com/sfperusacdev/identitysdk/httpapi"
+	"github.com/user0608/goones/answer"
+	"github.com/user0608/goones/errs"
+)
+
+type RevokeRequest struct {
+	Tokens    []identitysdk.RevokedToken `json:"tokens"`
+	Usernames []string                   `json:"usernames"`
+}
+
+type RevokeHandler struct {
+	httpapi.AccessKeyProtection
+	httpapi.MethodPost
+}
+
+var _ httpapi.Route = (*RevokeHandler)(nil)
+
+func NewRevokeHandler() *RevokeHandler {
+	return &RevokeHandler{}
+}
+
+func (h *RevokeHandler) GetPath() string {
+	return "/api/v1/_/revocations"
+}
+
+func (h *RevokeHandler) HandleRequest(c echo.Context) error {
+	var req RevokeRequest
+	if err := binds.JSON(c, &req); err != nil {
+		return answer.JsonErr(c)
+	}
+	if len(req.Tokens) == 0 && len(req.Usernames) == 0 {
+		return answer.Err(c, errs.BadRequestDirect("se requiere al menos un token o usuario"))
+	}
+	ctx := c.Request().Context()
+	identitysdk.RevokeTokens(ctx, req.Tokens...)
+	identitysdk.RevokeUsers(ctx, req.Usernames...)
+	return answer.Success(c)
+}
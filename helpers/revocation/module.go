@@ -0,0 +1,16 @@
+package revocation
+
+import (
+	identitygrpc "github.com/sfperusacdev/identitysdk/grpc"
+	"github.com/sfperusacdev/identitysdk/httpapi"
+	"go.uber.org/fx"
+)
+
+// Module expone la revocación de sesiones por HTTP y gRPC, ambos protegidos con access key.
+var Module = fx.Module(
+	"revocation",
+	fx.Provide(
+		httpapi.AsRoute(NewRevokeHandler),
+		identitygrpc.AsService(NewRevocationGrpcService),
+	),
+)
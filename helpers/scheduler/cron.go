@@ -0,0 +1,214 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron horario de una expresión cron evaluado en una zona horaria.
+//
+// Acepta 5 campos (minuto hora día mes día-semana), 6 campos con segundos al
+// inicio, o los atajos @yearly, @monthly, @weekly, @daily y @hourly. Cada campo
+// admite *, listas (1,15), rangos (1-5), pasos (*/10, 8-18/2) y nombres de mes
+// y día (JAN, MON). Si día y día-semana están restringidos basta con que
+// coincida uno de los dos, como en cron.
+type Cron struct {
+	second, minute, hour, dom, month, dow uint64
+	domStar, dowStar                      bool
+	location                              *time.Location
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondsField = cronField{min: 0, max: 59}
+	minutesField = cronField{min: 0, max: 59}
+	hoursField   = cronField{min: 0, max: 23}
+	domField     = cronField{min: 1, max: 31}
+	monthField   = cronField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 también es domingo
+	dowField = cronField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron interpreta expr en location; location nil usa UTC.
+func ParseCron(expr string, location *time.Location) (*Cron, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	spec := strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 or 6 fields", expr)
+	}
+
+	c := &Cron{location: location}
+	var err error
+	for i, target := range []struct {
+		bits  *uint64
+		field cronField
+	}{
+		{&c.second, secondsField},
+		{&c.minute, minutesField},
+		{&c.hour, hoursField},
+		{&c.dom, domField},
+		{&c.month, monthField},
+		{&c.dow, dowField},
+	} {
+		if *target.bits, err = parseCronField(fields[i], target.field); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domStar = fields[3] == "*" || fields[3] == "?"
+	c.dowStar = fields[5] == "*" || fields[5] == "?"
+	return c, nil
+}
+
+func parseCronField(field string, bounds cronField) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			value, err := strconv.Atoi(stepPart)
+			if err != nil || value <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			step = value
+		}
+
+		var from, to int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			from, to = bounds.min, bounds.max
+		case strings.Contains(rangePart, "-"):
+			start, end, _ := strings.Cut(rangePart, "-")
+			var err error
+			if from, err = bounds.value(start); err != nil {
+				return 0, err
+			}
+			if to, err = bounds.value(end); err != nil {
+				return 0, err
+			}
+		default:
+			value, err := bounds.value(rangePart)
+			if err != nil {
+				return 0, err
+			}
+			from, to = value, value
+			if hasStep {
+				to = bounds.max
+			}
+		}
+		if from > to {
+			return 0, fmt.Errorf("invalid range %q", part)
+		}
+		for value := from; value <= to; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+func (f cronField) value(raw string) (int, error) {
+	if value, ok := f.names[strings.ToLower(raw)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", raw)
+	}
+	if value < f.min || value > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", value, f.min, f.max)
+	}
+	return value, nil
+}
+
+func (c *Cron) Location() *time.Location { return c.location }
+
+// Next devuelve el primer horario posterior a t, en la zona de t, o el tiempo
+// cero si no hay uno en los próximos cinco años.
+func (c *Cron) Next(t time.Time) time.Time {
+	original := t.Location()
+	t = t.In(c.location).Truncate(time.Second).Add(time.Second)
+	yearLimit := t.Year() + 5
+	loc := c.location
+
+wrap:
+	for t.Year() <= yearLimit {
+		for c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			if t.Month() == time.January {
+				continue wrap
+			}
+		}
+		for !c.dayMatches(t) {
+			month := t.Month()
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			if t.Month() != month {
+				continue wrap
+			}
+		}
+		for c.hour&(1<<uint(t.Hour())) == 0 {
+			day := t.Day()
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if t.Day() != day {
+				continue wrap
+			}
+		}
+		for c.minute&(1<<uint(t.Minute())) == 0 {
+			hour := t.Hour()
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			if t.Hour() != hour {
+				continue wrap
+			}
+		}
+		for c.second&(1<<uint(t.Second())) == 0 {
+			minute := t.Minute()
+			t = t.Add(time.Second)
+			if t.Minute() != minute {
+				continue wrap
+			}
+		}
+		return t.In(original)
+	}
+	return time.Time{}
+}
+
+func (c *Cron) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
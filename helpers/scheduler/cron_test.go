@@ -0,0 +1,52 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/helpers/scheduler"
+)
+
+func TestCronNext(t *testing.T) {
+	lima, err := time.LoadLocation("America/Lima")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2026-03-06 es viernes
+	from := time.Date(2026, 3, 6, 10, 30, 15, 0, time.UTC)
+	cases := []struct {
+		expr     string
+		location *time.Location
+		want     time.Time
+	}{
+		{"*/15 * * * *", time.UTC, time.Date(2026, 3, 6, 10, 45, 0, 0, time.UTC)},
+		{"0 2 * * *", lima, time.Date(2026, 3, 7, 2, 0, 0, 0, lima)},
+		{"0 8 * * *", lima, time.Date(2026, 3, 6, 8, 0, 0, 0, lima)},
+		{"30 18 * * mon-fri", lima, time.Date(2026, 3, 6, 18, 30, 0, 0, lima)},
+		{"0 9 * * SAT,SUN", time.UTC, time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 */3 *", time.UTC, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.UTC, time.Time{}},
+		{"0 12 13 * 5", time.UTC, time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)},
+		{"@monthly", lima, time.Date(2026, 4, 1, 0, 0, 0, 0, lima)},
+		{"*/20 * * * * *", time.UTC, time.Date(2026, 3, 6, 10, 30, 20, 0, time.UTC)},
+		{"0 0 0 29 2 *", time.UTC, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.UTC, time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		cron, err := scheduler.ParseCron(tc.expr, tc.location)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.expr, err)
+		}
+		if got := cron.Next(from); !got.Equal(tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.expr, tc.want, got.In(tc.location))
+		}
+	}
+}
+
+func TestParseCronRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"", "* * *", "60 * * * *", "* 24 * * *", "5-1 * * * *", "*/0 * * * *", "* * * foo *"} {
+		if _, err := scheduler.ParseCron(expr, nil); err == nil {
+			t.Fatalf("expected %q to be rejected", expr)
+		}
+	}
+}
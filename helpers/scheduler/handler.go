@@ -0,0 +1,30 @@
+package scheduler
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk/httpapi"
+	"github.com/user0608/goones/answer"
+)
+
+// JobsHandler lista los jobs con su última y próxima ejecución según esta réplica.
+type JobsHandler struct {
+	httpapi.AccessKeyProtection
+	httpapi.MethodGet
+	scheduler *Scheduler
+}
+
+var _ httpapi.Route = (*JobsHandler)(nil)
+
+func NewJobsHandler(scheduler *Scheduler) *JobsHandler {
+	return &JobsHandler{scheduler: scheduler}
+}
+
+func (h *JobsHandler) GetPath() string {
+	return "/api/v1/_/scheduler/jobs"
+}
+
+func (h *JobsHandler) ResponseSchema() any { return []JobStatus{} }
+
+func (h *JobsHandler) HandleRequest(c echo.Context) error {
+	return answer.Ok(c, h.scheduler.Jobs())
+}
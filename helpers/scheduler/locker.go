@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	connection "github.com/sfperusacdev/identitysdk/pg-connection"
+	"github.com/user0608/goones/errs"
+)
+
+// Locker evita que dos réplicas ejecuten el mismo horario de un job.
+type Locker interface {
+	// Lock reserva la ejecución de job para slot; ok es false si otra réplica
+	// la está ejecutando o ya la ejecutó. unlock se llama al terminar.
+	Lock(ctx context.Context, job string, slot time.Time) (unlock func(), ok bool, err error)
+}
+
+// LocalLocker sirve para una sola réplica y para pruebas; el scheduler ya
+// evita que un job se solape consigo mismo dentro del proceso.
+type LocalLocker struct{}
+
+var _ Locker = LocalLocker{}
+
+func (LocalLocker) Lock(ctx context.Context, job string, slot time.Time) (func(), bool, error) {
+	return func() {}, true, nil
+}
+
+// PgLocker elige una réplica por ejecución con un advisory lock de Postgres
+// que se mantiene mientras el job corre, y registra en _scheduler_jobs el
+// último horario ejecutado para que una réplica con el reloj atrasado no lo
+// repita.
+type PgLocker struct {
+	manager connection.StorageManager
+	schema  connection.Schema
+}
+
+var _ Locker = (*PgLocker)(nil)
+
+func NewPgLocker(manager connection.StorageManager) *PgLocker {
+	return &PgLocker{manager: manager}
+}
+
+// jobsTable recuerda el último horario ejecutado de cada job.
+const jobsTable = `
+	CREATE TABLE IF NOT EXISTS _scheduler_jobs (
+		name VARCHAR(255) PRIMARY KEY,
+		last_slot TIMESTAMPTZ NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`
+
+func (l *PgLocker) Lock(ctx context.Context, job string, slot time.Time) (func(), bool, error) {
+	tx := l.manager.Conn(context.Background())
+	if tx == nil {
+		return func() {}, true, nil // skip
+	}
+	if err := l.schema.Ensure(l.manager, jobsTable); err != nil {
+		return nil, false, err
+	}
+	db, err := tx.DB()
+	if err != nil {
+		return nil, false, errs.Pgf(err)
+	}
+	// el advisory lock pertenece a la sesión, por eso se fija una conexión del pool
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, false, errs.Pgf(err)
+	}
+
+	key := lockKey(job)
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, false, errs.Pgf(err)
+	}
+	if !locked {
+		conn.Close()
+		return nil, false, nil
+	}
+	unlock := func() {
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+		conn.Close()
+	}
+
+	const claim = `
+	INSERT INTO _scheduler_jobs (name, last_slot) VALUES ($1, $2)
+	ON CONFLICT (name) DO UPDATE SET last_slot = EXCLUDED.last_slot, updated_at = now()
+	WHERE _scheduler_jobs.last_slot < EXCLUDED.last_slot`
+	result, err := conn.ExecContext(ctx, claim, job, slot)
+	if err != nil {
+		unlock()
+		return nil, false, errs.Pgf(err)
+	}
+	if claimed, _ := result.RowsAffected(); claimed == 0 {
+		unlock()
+		return nil, false, nil
+	}
+	return unlock, true, nil
+}
+
+func lockKey(job string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte("scheduler:" + job))
+	return int64(hash.Sum64())
+}
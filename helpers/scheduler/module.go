@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/sfperusacdev/identitysdk/httpapi"
+	"github.com/sfperusacdev/identitysdk/services"
+	"go.uber.org/fx"
+)
+
+const JobTag = `group:"scheduler-jobs"`
+
+// AsJob registra en el scheduler el Job que devuelve fn.
+func AsJob(fn any) any {
+	return fx.Annotate(
+		fn,
+		fx.ResultTags(JobTag),
+	)
+}
+
+type schedulerParams struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Jobs      []Job `group:"scheduler-jobs"`
+	Locker    Locker
+	Bridge    *services.ExternalBridgeService `optional:"true"`
+}
+
+func newScheduler(p schedulerParams) (*Scheduler, error) {
+	opts := []Option{WithLocker(p.Locker)}
+	if p.Bridge != nil {
+		opts = append(opts, WithTenants(p.Bridge.GetDominios))
+	}
+	s := New(opts...)
+	for _, job := range p.Jobs {
+		if err := s.Add(job); err != nil {
+			return nil, err
+		}
+	}
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			s.Start()
+			return nil
+		},
+		OnStop: s.Stop,
+	})
+	return s, nil
+}
+
+// Module ejecuta los jobs registrados con AsJob, coordinando las réplicas con
+// advisory locks de Postgres, y expone su estado protegido con access key.
+var Module = fx.Module(
+	"scheduler",
+	fx.Provide(
+		fx.Annotate(
+			NewPgLocker,
+			fx.As(new(Locker)),
+		),
+		newScheduler,
+		httpapi.AsRoute(NewJobsHandler),
+	),
+	fx.Invoke(func(*Scheduler) {}),
+)
@@ -0,0 +1,373 @@
+// Package scheduler ejecuta jobs según expresiones cron.
+//
+// Cada job corre en su propia goroutine y nunca se solapa consigo mismo: si
+// la ejecución anterior sigue en curso, el horario se omite. Con varias
+// réplicas, el Locker decide cuál ejecuta cada horario. Los jobs PerTenant se
+// ejecutan una vez por empresa en un DomainExecutor, con la empresa como
+// dominio y en el contexto.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/helpers/domainexecutor"
+)
+
+// DefaultTimeZone zona en la que se evalúan los jobs que no indican una.
+const DefaultTimeZone = "America/Lima"
+
+const defaultTenantConcurrency = 4
+
+type Job struct {
+	// Name identifica al job en el Locker y en la ruta de consulta; debe ser único
+	Name string
+	// Schedule expresión cron, ver Cron
+	Schedule string
+	// TimeZone zona en la que se evalúa Schedule; por defecto DefaultTimeZone
+	TimeZone string
+	// PerTenant ejecuta Run una vez por empresa de la lista de empresas
+	PerTenant bool
+	// TenantConcurrency empresas que se procesan a la vez; por defecto 4
+	TenantConcurrency int
+	// Timeout límite de cada ejecución; 0 sin límite
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// TenantsFunc devuelve las empresas para los jobs PerTenant,
+// normalmente ExternalBridgeService.GetDominios.
+type TenantsFunc func(ctx context.Context) ([]string, error)
+
+// Run ejecución de un job en esta réplica.
+type Run struct {
+	Slot       time.Time `json:"slot"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at,omitzero"`
+	Error      string    `json:"error,omitempty"`
+	// Tenants empresas procesadas en jobs PerTenant
+	Tenants int `json:"tenants,omitempty"`
+}
+
+type JobStatus struct {
+	Name      string    `json:"name"`
+	Schedule  string    `json:"schedule"`
+	TimeZone  string    `json:"time_zone"`
+	PerTenant bool      `json:"per_tenant"`
+	Running   bool      `json:"running"`
+	LastRun   *Run      `json:"last_run,omitempty"`
+	NextRun   time.Time `json:"next_run,omitzero"`
+}
+
+type Option func(*Scheduler)
+
+func WithLocker(locker Locker) Option {
+	return func(s *Scheduler) {
+		if locker == nil {
+			slog.Warn("Locker is nil, operation skipped")
+			return
+		}
+		s.locker = locker
+	}
+}
+
+func WithTenants(tenants TenantsFunc) Option {
+	return func(s *Scheduler) {
+		if tenants == nil {
+			slog.Warn("TenantsFunc is nil, operation skipped")
+			return
+		}
+		s.tenants = tenants
+	}
+}
+
+// WithExecutor comparte un DomainExecutor para que los jobs PerTenant se
+// ejecuten en serie con el resto de tareas de la empresa.
+func WithExecutor(executor *domainexecutor.DomainExecutor) Option {
+	return func(s *Scheduler) {
+		if executor == nil {
+			slog.Warn("DomainExecutor is nil, operation skipped")
+			return
+		}
+		s.executor = executor
+	}
+}
+
+type Scheduler struct {
+	locker   Locker
+	tenants  TenantsFunc
+	executor *domainexecutor.DomainExecutor
+	now      func() time.Time
+
+	mu      sync.Mutex
+	jobs    []*jobState
+	started bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+type jobState struct {
+	job  Job
+	cron *Cron
+
+	running bool
+	lastRun *Run
+	nextRun time.Time
+}
+
+func New(opts ...Option) *Scheduler {
+	s := &Scheduler{
+		locker: LocalLocker{},
+		now:    time.Now,
+	}
+	for _, apply := range opts {
+		apply(s)
+	}
+	if s.executor == nil {
+		s.executor = domainexecutor.New(domainexecutor.Config{
+			IdleEvictAfter: time.Minute,
+			QueueCapacity:  1,
+			Name:           "scheduler",
+		})
+	}
+	return s
+}
+
+// Add registra un job; los jobs agregados después de Start empiezan de inmediato.
+func (s *Scheduler) Add(job Job) error {
+	if job.Name == "" {
+		return errors.New("scheduler job name is required")
+	}
+	if job.Run == nil {
+		return fmt.Errorf("scheduler job %s: Run is required", job.Name)
+	}
+	if job.TimeZone == "" {
+		job.TimeZone = DefaultTimeZone
+	}
+	if job.TenantConcurrency <= 0 {
+		job.TenantConcurrency = defaultTenantConcurrency
+	}
+	location, err := time.LoadLocation(job.TimeZone)
+	if err != nil {
+		return fmt.Errorf("scheduler job %s: %w", job.Name, err)
+	}
+	cron, err := ParseCron(job.Schedule, location)
+	if err != nil {
+		return fmt.Errorf("scheduler job %s: %w", job.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.ContainsFunc(s.jobs, func(state *jobState) bool { return state.job.Name == job.Name }) {
+		return fmt.Errorf("scheduler job %s already registered", job.Name)
+	}
+	state := &jobState{job: job, cron: cron}
+	s.jobs = append(s.jobs, state)
+	if s.started {
+		s.startJob(state)
+	}
+	return nil
+}
+
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	for _, state := range s.jobs {
+		s.startJob(state)
+	}
+}
+
+// Stop cancela el contexto de las ejecuciones en curso y espera a que terminen.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return nil
+	}
+	s.started = false
+	s.cancel()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Jobs devuelve los jobs registrados con su última y próxima ejecución en esta réplica.
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]JobStatus, 0, len(s.jobs))
+	for _, state := range s.jobs {
+		status := JobStatus{
+			Name:      state.job.Name,
+			Schedule:  state.job.Schedule,
+			TimeZone:  state.job.TimeZone,
+			PerTenant: state.job.PerTenant,
+			Running:   state.running,
+			NextRun:   state.nextRun,
+		}
+		if state.nextRun.IsZero() {
+			status.NextRun = state.cron.Next(s.now())
+		}
+		if state.lastRun != nil {
+			run := *state.lastRun
+			status.LastRun = &run
+		}
+		jobs = append(jobs, status)
+	}
+	return jobs
+}
+
+// startJob se llama con s.mu tomado.
+func (s *Scheduler) startJob(state *jobState) {
+	s.wg.Add(1)
+	go s.loop(s.ctx, state)
+}
+
+func (s *Scheduler) loop(ctx context.Context, state *jobState) {
+	defer s.wg.Done()
+	for {
+		next := state.cron.Next(s.now())
+		if next.IsZero() {
+			slog.Warn("scheduler job has no next run", "job", state.job.Name, "schedule", state.job.Schedule)
+			return
+		}
+		s.mu.Lock()
+		state.nextRun = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.mu.Lock()
+		if state.running {
+			s.mu.Unlock()
+			slog.Warn("scheduler job still running, run skipped", "job", state.job.Name, "slot", next)
+			continue
+		}
+		state.running = true
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go s.run(ctx, state, next)
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, state *jobState, slot time.Time) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		state.running = false
+		s.mu.Unlock()
+	}()
+	job := state.job
+
+	unlock, ok, err := s.locker.Lock(ctx, job.Name, slot)
+	if err != nil {
+		slog.Error("scheduler lock failed", "job", job.Name, "error", err)
+		return
+	}
+	if !ok {
+		slog.Debug("scheduler job taken by another replica", "job", job.Name, "slot", slot)
+		return
+	}
+	defer unlock()
+
+	run := &Run{Slot: slot, StartedAt: s.now()}
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+	if job.PerTenant {
+		run.Tenants, err = s.runPerTenant(ctx, job)
+	} else {
+		err = safeRun(ctx, job.Run)
+	}
+	run.FinishedAt = s.now()
+	if err != nil {
+		run.Error = err.Error()
+		slog.Error("scheduler job failed", "job", job.Name, "slot", slot, "error", err)
+	}
+
+	s.mu.Lock()
+	state.lastRun = run
+	s.mu.Unlock()
+}
+
+// runPerTenant ejecuta el job para cada empresa en el DomainExecutor y reúne
+// los errores; el fallo de una empresa no detiene a las demás.
+func (s *Scheduler) runPerTenant(ctx context.Context, job Job) (int, error) {
+	if s.tenants == nil {
+		return 0, errors.New("scheduler has no TenantsFunc for per-tenant jobs")
+	}
+	tenants, err := s.tenants(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		errs   []error
+		tokens = make(chan struct{}, job.TenantConcurrency)
+	)
+	for _, empresa := range tenants {
+		select {
+		case tokens <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return len(tenants), errors.Join(append(errs, ctx.Err())...)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-tokens }()
+			tenantCtx := identitysdk.CtxWithDomain(ctx, empresa)
+			err := s.executor.Execute(tenantCtx, empresa, func(ctx context.Context) error {
+				return safeRun(ctx, job.Run)
+			}, nil)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", empresa, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return len(tenants), errors.Join(errs...)
+}
+
+func safeRun(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
@@ -0,0 +1,152 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/helpers/scheduler"
+)
+
+type busyLocker struct{ calls atomic.Int32 }
+
+func (l *busyLocker) Lock(ctx context.Context, job string, slot time.Time) (func(), bool, error) {
+	l.calls.Add(1)
+	return nil, false, nil
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not reached")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSchedulerRunsPerTenantJobs(t *testing.T) {
+	s := scheduler.New(scheduler.WithTenants(func(ctx context.Context) ([]string, error) {
+		return []string{"sfperu", "agro", "fallida"}, nil
+	}))
+
+	var mu sync.Mutex
+	var seen []string
+	err := s.Add(scheduler.Job{
+		Name:      "recalculo",
+		Schedule:  "* * * * * *",
+		TimeZone:  "America/Lima",
+		PerTenant: true,
+		Run: func(ctx context.Context) error {
+			empresa := identitysdk.Empresa(ctx)
+			mu.Lock()
+			seen = append(seen, empresa)
+			mu.Unlock()
+			if empresa == "fallida" {
+				return errors.New("sin planilla")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+
+	waitFor(t, func() bool {
+		jobs := s.Jobs()
+		return jobs[0].LastRun != nil && !jobs[0].LastRun.FinishedAt.IsZero()
+	})
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	job := s.Jobs()[0]
+	if job.LastRun.Tenants != 3 || job.LastRun.Error != "fallida: sin planilla" || job.TimeZone != "America/Lima" || job.NextRun.IsZero() {
+		t.Fatalf("unexpected job status %+v %+v", job, job.LastRun)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, empresa := range []string{"sfperu", "agro", "fallida"} {
+		if !slices.Contains(seen, empresa) {
+			t.Fatalf("expected %s to be processed, got %v", empresa, seen)
+		}
+	}
+}
+
+func TestSchedulerSkipsOverlapAndLockedRuns(t *testing.T) {
+	s := scheduler.New()
+	var runs atomic.Int32
+	release := make(chan struct{})
+	if err := s.Add(scheduler.Job{
+		Name:     "lento",
+		Schedule: "* * * * * *",
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			<-release
+			return nil
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	waitFor(t, func() bool { return s.Jobs()[0].Running })
+	// dejamos pasar al menos dos horarios mientras la primera ejecución sigue en curso
+	time.Sleep(2100 * time.Millisecond)
+	close(release)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if runs.Load() != 1 {
+		t.Fatalf("expected overlapping runs to be skipped, got %d runs", runs.Load())
+	}
+
+	locker := &busyLocker{}
+	locked := scheduler.New(scheduler.WithLocker(locker))
+	var lockedRuns atomic.Int32
+	if err := locked.Add(scheduler.Job{
+		Name:     "otra-replica",
+		Schedule: "* * * * * *",
+		Run: func(ctx context.Context) error {
+			lockedRuns.Add(1)
+			return nil
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	locked.Start()
+	waitFor(t, func() bool { return locker.calls.Load() > 0 })
+	if err := locked.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if lockedRuns.Load() != 0 || locked.Jobs()[0].LastRun != nil {
+		t.Fatal("expected job held by another replica not to run")
+	}
+}
+
+func TestSchedulerAddValidatesJobs(t *testing.T) {
+	s := scheduler.New()
+	run := func(ctx context.Context) error { return nil }
+	for _, job := range []scheduler.Job{
+		{Schedule: "@daily", Run: run},
+		{Name: "sin-run", Schedule: "@daily"},
+		{Name: "zona", Schedule: "@daily", TimeZone: "America/Nowhere", Run: run},
+		{Name: "cron", Schedule: "cada hora", Run: run},
+	} {
+		if err := s.Add(job); err == nil {
+			t.Fatalf("expected job %+v to be rejected", job)
+		}
+	}
+	if err := s.Add(scheduler.Job{Name: "diario", Schedule: "@daily", Run: run}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(scheduler.Job{Name: "diario", Schedule: "@hourly", Run: run}); err == nil {
+		t.Fatal("expected duplicated job name to be rejected")
+	}
+}
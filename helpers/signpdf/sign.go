@@ -17,7 +17,6 @@ import (
 	"github.com/rs/xid"
 	"github.com/sfperusacdev/identitysdk"
 	pyhankoconfig "github.com/sfperusacdev/identitysdk/helpers/signpdf/pyhanko_config"
-	"github.com/user0608/ifdevmode"
 )
 
 type PyhankoPDFSigner struct {
@@ -205,8 +204,13 @@ func (py *PyhankoPDFSigner) Sign(
 
 	py.ensureBoxDefaults(box)
 
+	// con el nivel debug activo para la empresa se conservan la configuración y
+	// el PDF de entrada para revisar la llamada a pyHanko; las llaves se eliminan siempre
+	logger := identitysdk.Logger(ctx)
+	keepFiles := logger.Enabled(ctx, slog.LevelDebug)
+
 	keyPath, certPath, cleanUpKeys, err := py.storeKeyAndCertFiles([]byte(keyPEM), []byte(certPEM))
-	if cleanUpKeys != nil && !ifdevmode.Yes() {
+	if cleanUpKeys != nil {
 		defer cleanUpKeys()
 	}
 	if err != nil {
@@ -214,7 +218,7 @@ func (py *PyhankoPDFSigner) Sign(
 	}
 
 	configPath, configCleanup, err := py.prepareConfigFile(box, py.extractCommonName(certPEM))
-	if configCleanup != nil && !ifdevmode.Yes() {
+	if configCleanup != nil && !keepFiles {
 		defer configCleanup()
 	}
 	if err != nil {
@@ -222,12 +226,15 @@ func (py *PyhankoPDFSigner) Sign(
 	}
 
 	inputPDFPath, inputCleanup, err := py.storeTempFile(pdfData)
-	if inputCleanup != nil && !ifdevmode.Yes() {
+	if inputCleanup != nil && !keepFiles {
 		defer inputCleanup()
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to store input PDF: %w", err)
 	}
+	if keepFiles {
+		logger.Debug("pyHanko files kept", "config", configPath, "input", inputPDFPath)
+	}
 
 	id := xid.New().String()
 	safeSignName := fmt.Sprintf("%s_%s", strings.ReplaceAll(signName, " ", "_"), id)
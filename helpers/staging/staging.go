@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/sfperusacdev/identitysdk/configs"
+	"github.com/sfperusacdev/identitysdk/health"
 	"go.uber.org/fx"
 )
 
@@ -53,7 +54,7 @@ func NewStagingFilesArea(lc fx.Lifecycle, config configs.GeneralServiceConfigPro
 			go staging.runCleaner(ctx)
 			return nil
 		},
-		OnStop: func(_ context.Context) error {
+		OnStop: health.OnShutdown(health.PhaseCleanup, "staging-cleaner", func(_ context.Context) error {
 			mu.Lock()
 			c := cancel
 			cancel = nil
@@ -62,7 +63,7 @@ func NewStagingFilesArea(lc fx.Lifecycle, config configs.GeneralServiceConfigPro
 				c()
 			}
 			return nil
-		},
+		}),
 	})
 
 	return staging
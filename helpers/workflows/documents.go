@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/helpers/audit"
 	"github.com/sfperusacdev/identitysdk/helpers/workflows/workflows_entities"
 	connection "github.com/sfperusacdev/identitysdk/pg-connection"
 	"github.com/sfperusacdev/identitysdk/services"
@@ -109,6 +110,7 @@ func (m *DocumentWorkflowStateManager) ChangeState(ctx context.Context, req Chan
 	}
 
 	var affectedTargets = []string{}
+	var previousStates = make(map[string]string, len(records))
 	var listErrors = []error{}
 	var initialStates = make(map[string]struct{})
 	for _, r := range records {
@@ -134,6 +136,7 @@ func (m *DocumentWorkflowStateManager) ChangeState(ctx context.Context, req Chan
 			continue
 		}
 		affectedTargets = append(affectedTargets, r.Code)
+		previousStates[r.Code] = currentState
 	}
 	if req.RequireSameInitialState && len(initialStates) > 1 {
 		return nil, errs.BadRequestf("todos los documentos deben tener el mismo estado inicial")
@@ -153,5 +156,18 @@ func (m *DocumentWorkflowStateManager) ChangeState(ctx context.Context, req Chan
 	if rs.Error != nil {
 		return nil, errs.Pgf(rs.Error)
 	}
+
+	var targetState = identitysdk.RemovePrefix(req.Change.TargetState)
+	var events = make([]audit.Event, 0, len(affectedTargets))
+	for _, code := range affectedTargets {
+		events = append(events, audit.Event{
+			Action:    "workflow.change_state",
+			Entity:    req.Entity.TableName,
+			EntityKey: code,
+			Old:       map[string]any{req.Entity.StateColumn: previousStates[code]},
+			New:       map[string]any{req.Entity.StateColumn: targetState},
+		})
+	}
+	audit.RecordOrLog(ctx, events...)
 	return affectedTargets, nil
 }
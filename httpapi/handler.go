@@ -4,17 +4,21 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk/permissions"
 )
 
 type DefaultHandler struct {
 	Method      string
 	Path        string
 	Permissions []string
-	Handler     echo.HandlerFunc
+	// PermissionRule expresión opcional, p. ej. permissions.MustParse("planilla.aprobar || admin")
+	PermissionRule permissions.Rule
+	Handler        echo.HandlerFunc
 }
 
 var _ Route = (*DefaultHandler)(nil)
 var _ PermissionChecker = (*DefaultHandler)(nil)
+var _ PermissionRuleChecker = (*DefaultHandler)(nil)
 
 func (h *DefaultHandler) GetMethod() string {
 	return defaultMethod(h.Method)
@@ -26,6 +30,10 @@ func (h *DefaultHandler) CheckPermissions() []string {
 	return h.Permissions
 }
 
+func (h *DefaultHandler) CheckPermissionRule() permissions.Rule {
+	return h.PermissionRule
+}
+
 func (h *DefaultHandler) HandleRequest(c echo.Context) error {
 	return handleDefaultRequest(c, h.Handler)
 }
@@ -35,11 +43,14 @@ type DefaultSucursalHandler struct {
 	Method      string
 	Path        string
 	Permissions []string
-	Handler     echo.HandlerFunc
+	// PermissionRule expresión opcional, p. ej. permissions.MustParse("planilla.aprobar || admin")
+	PermissionRule permissions.Rule
+	Handler        echo.HandlerFunc
 }
 
 var _ Route = (*DefaultSucursalHandler)(nil)
 var _ PermissionChecker = (*DefaultSucursalHandler)(nil)
+var _ PermissionRuleChecker = (*DefaultSucursalHandler)(nil)
 
 func (h *DefaultSucursalHandler) GetMethod() string {
 	return defaultMethod(h.Method)
@@ -51,6 +62,10 @@ func (h *DefaultSucursalHandler) CheckPermissions() []string {
 	return h.Permissions
 }
 
+func (h *DefaultSucursalHandler) CheckPermissionRule() permissions.Rule {
+	return h.PermissionRule
+}
+
 func (h *DefaultSucursalHandler) HandleRequest(c echo.Context) error {
 	return handleDefaultRequest(c, h.Handler)
 }
@@ -0,0 +1,27 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk/health"
+)
+
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+// livenessHandler responde mientras el proceso atienda peticiones.
+func livenessHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, health.Report{Status: health.StatusOK})
+}
+
+// readinessHandler responde 503 mientras el servicio arranca, se apaga o falla algún check.
+func readinessHandler(c echo.Context) error {
+	report := health.Readiness(c.Request().Context())
+	if !report.OK() {
+		return c.JSON(http.StatusServiceUnavailable, report)
+	}
+	return c.JSON(http.StatusOK, report)
+}
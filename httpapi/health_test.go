@@ -0,0 +1,30 @@
+package httpapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sfperusacdev/identitysdk/health"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	server := NewTestServer(t)
+	defer health.SetReady(false)
+
+	response, err := server.Client().Get(server.URL + LivenessPath)
+	require.NoError(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, err = server.Client().Get(server.URL + ReadinessPath)
+	require.NoError(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+
+	health.SetReady(true)
+	response, err = server.Client().Get(server.URL + ReadinessPath)
+	require.NoError(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+}
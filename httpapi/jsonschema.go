@@ -0,0 +1,139 @@
+package httpapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	invalidNameChars  = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+)
+
+// schemaBuilder genera esquemas JSON por reflexión siguiendo las reglas de
+// encoding/json. Los structs con nombre se registran en components y se
+// referencian con $ref, lo que también resuelve tipos recursivos.
+type schemaBuilder struct {
+	components map[string]any
+	names      map[reflect.Type]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{components: map[string]any{}, names: map[reflect.Type]string{}}
+}
+
+func (b *schemaBuilder) schemaOf(value any) map[string]any {
+	if value == nil {
+		return map[string]any{}
+	}
+	return b.schema(reflect.TypeOf(value))
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return map[string]any{}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return map[string]any{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + b.register(t)}
+	}
+	return map[string]any{}
+}
+
+func (b *schemaBuilder) register(t reflect.Type) string {
+	if name, found := b.names[t]; found {
+		return name
+	}
+	name := invalidNameChars.ReplaceAllString(t.Name(), "_")
+	if b.components[name] != nil {
+		// mismo nombre en otro paquete
+		name = invalidNameChars.ReplaceAllString(t.String(), "_")
+	}
+	for i := 2; b.components[name] != nil; i++ {
+		name = invalidNameChars.ReplaceAllString(t.String(), "_") + "_" + strconv.Itoa(i)
+	}
+	b.names[t] = name
+	b.components[name] = map[string]any{} // placeholder para tipos recursivos
+	b.components[name] = b.object(t)
+	return name
+}
+
+func (b *schemaBuilder) object(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	b.collectFields(t, properties, &required)
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (b *schemaBuilder) collectFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			b.collectFields(fieldType, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = b.schema(field.Type)
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") && field.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk/metrics"
+)
+
+var (
+	httpRequests = metrics.NewCounter(
+		"http_server_requests_total",
+		"Peticiones HTTP atendidas por ruta y código de estado.",
+		"method", "route", "status",
+	)
+	httpRequestDuration = metrics.NewHistogram(
+		"http_server_request_duration_seconds",
+		"Latencia de las peticiones HTTP por ruta.",
+		nil,
+		"method", "route",
+	)
+)
+
+// metricsMiddleware registra la cantidad y latencia de peticiones por ruta registrada.
+func metricsMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		started := time.Now()
+		err := next(c)
+
+		route := c.Path()
+		if route == "" {
+			// rutas no registradas: se agrupan para no crear una serie por URL
+			route = "unmatched"
+		}
+		status := c.Response().Status
+		if httpErr, ok := err.(*echo.HTTPError); ok && !c.Response().Committed {
+			status = httpErr.Code
+		} else if err != nil && !c.Response().Committed {
+			status = http.StatusInternalServerError
+		}
+		method := c.Request().Method
+		httpRequests.With(method, route, strconv.Itoa(status)).Inc()
+		httpRequestDuration.With(method, route).Observe(time.Since(started).Seconds())
+		return err
+	}
+}
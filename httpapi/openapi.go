@@ -0,0 +1,163 @@
+package httpapi
+
+import "strings"
+
+type OpenAPIInfo struct {
+	Title       string
+	Description string
+	Version     string
+}
+
+var securitySchemes = map[AuthMode]map[string]any{
+	AuthJwt:          {"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+	AuthPublicClient: {"type": "http", "scheme": "bearer", "bearerFormat": "JWT", "description": "token de cliente público"},
+	AuthApiKey:       {"type": "apiKey", "in": "header", "name": "X-API-KEY"},
+	AuthAccessKey:    {"type": "apiKey", "in": "header", "name": "X-Access-Token"},
+}
+
+// BuildOpenAPI genera un documento OpenAPI 3 a partir del catálogo de rutas.
+// Las respuestas siguen el formato de answer: {"type", "message", "data"}.
+func BuildOpenAPI(info OpenAPIInfo, catalog RouteCatalog) map[string]any {
+	schemas := newSchemaBuilder()
+	paths := map[string]any{}
+	usedSchemes := map[string]any{}
+
+	for _, route := range catalog {
+		path, params := openAPIPath(route.Path)
+		if route.Sucursal {
+			params = append(params, map[string]any{
+				"name": "sucursal", "in": "query", "required": true,
+				"schema": map[string]any{"type": "string"},
+			})
+		}
+
+		operation := map[string]any{
+			"operationId": operationID(route.Method, route.Path),
+			"responses": map[string]any{
+				"200": jsonContent("OK", envelope(schemas.schemaOf(route.response))),
+				"default": jsonContent("Error", map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"type":    map[string]any{"type": "string"},
+						"message": map[string]any{"type": "string"},
+					},
+				}),
+			},
+		}
+		if route.Summary != "" {
+			operation["summary"] = route.Summary
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		if route.request != nil {
+			body := jsonContent("", schemas.schemaOf(route.request))
+			delete(body, "description")
+			body["required"] = true
+			operation["requestBody"] = body
+		}
+
+		requirement := map[string]any{}
+		for _, mode := range route.Auth {
+			if scheme, found := securitySchemes[mode]; found {
+				usedSchemes[string(mode)] = scheme
+				requirement[string(mode)] = []string{}
+			}
+		}
+		if len(requirement) > 0 {
+			operation["security"] = []any{requirement}
+		}
+		if permissions := routePermissions(route); permissions != "" {
+			operation["description"] = "Permisos: " + permissions
+			operation["x-permissions"] = permissions
+		}
+
+		item, _ := paths[path].(map[string]any)
+		if item == nil {
+			item = map[string]any{}
+			paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = operation
+	}
+
+	components := map[string]any{"schemas": schemas.components}
+	if len(usedSchemes) > 0 {
+		components["securitySchemes"] = usedSchemes
+	}
+	document := map[string]any{
+		"openapi":    "3.0.3",
+		"info":       map[string]any{"title": info.Title, "version": info.Version},
+		"paths":      paths,
+		"components": components,
+	}
+	if info.Description != "" {
+		document["info"].(map[string]any)["description"] = info.Description
+	}
+	return document
+}
+
+func routePermissions(route RouteInfo) string {
+	parts := append([]string{}, route.Permissions...)
+	if route.PermissionRule != "" {
+		parts = append(parts, route.PermissionRule)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// openAPIPath convierte /items/:id/* de echo en /items/{id}/{path}.
+func openAPIPath(path string) (string, []any) {
+	segments := strings.Split(path, "/")
+	var params []any
+	for i, segment := range segments {
+		name := ""
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			name = segment[1:]
+		case segment == "*":
+			name = "path"
+		default:
+			continue
+		}
+		segments[i] = "{" + name + "}"
+		params = append(params, map[string]any{
+			"name": name, "in": "path", "required": true,
+			"schema": map[string]any{"type": "string"},
+		})
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(path, "/") {
+		segment = strings.Trim(segment, ":*{}")
+		if segment == "" || segment == "_" {
+			continue
+		}
+		for _, part := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
+func envelope(data map[string]any) map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"type":    map[string]any{"type": "string"},
+			"message": map[string]any{"type": "string"},
+			"data":    data,
+		},
+	}
+}
+
+func jsonContent(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schema},
+		},
+	}
+}
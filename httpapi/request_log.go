@@ -0,0 +1,49 @@
+package httpapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/sfperusacdev/identitysdk"
+)
+
+// requestIDMiddleware reutiliza la cabecera X-Request-ID o genera una nueva y la
+// guarda en el contexto para identitysdk.Logger.
+func requestIDMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		requestID := c.Request().Header.Get(echo.HeaderXRequestID)
+		if requestID == "" || len(requestID) > 128 {
+			var raw [16]byte
+			_, _ = rand.Read(raw[:])
+			requestID = hex.EncodeToString(raw[:])
+		}
+		c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+		ctx := identitysdk.CtxWithRequestID(c.Request().Context(), requestID)
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
+	}
+}
+
+// logRequest registra cada petición con los datos de identidad disponibles al terminar.
+func logRequest(c echo.Context, v middleware.RequestLoggerValues) error {
+	level := slog.LevelInfo
+	if v.Status >= 500 {
+		level = slog.LevelError
+	}
+	attrs := []any{
+		"method", v.Method,
+		"uri", v.URI,
+		"status", v.Status,
+		"ip", v.RemoteIP,
+		"latency", v.Latency.String(),
+	}
+	if v.Error != nil {
+		attrs = append(attrs, "error", v.Error.Error())
+	}
+	ctx := c.Request().Context()
+	identitysdk.Logger(ctx).Log(ctx, level, "request", attrs...)
+	return nil
+}
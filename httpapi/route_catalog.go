@@ -0,0 +1,127 @@
+package httpapi
+
+import (
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk/permissions"
+	"github.com/user0608/goones/answer"
+)
+
+type AuthMode string
+
+const (
+	AuthPublic       AuthMode = "public"
+	AuthJwt          AuthMode = "jwt"
+	AuthApiKey       AuthMode = "api_key"
+	AuthAccessKey    AuthMode = "access_key"
+	AuthPublicClient AuthMode = "public_client"
+)
+
+const RoutesCatalogPath = "/api/v1/_/routes"
+
+// RequestSchemaProvider devuelve un valor de ejemplo del body (p. ej. CreateRequest{});
+// se usa para generar el esquema JSON de la petición.
+type RequestSchemaProvider interface {
+	RequestSchema() any
+}
+
+// ResponseSchemaProvider devuelve un valor de ejemplo de data en la respuesta.
+type ResponseSchemaProvider interface {
+	ResponseSchema() any
+}
+
+// RouteDescriber agrega una descripción corta a la ruta en el catálogo.
+type RouteDescriber interface {
+	RouteSummary() string
+}
+
+type RouteInfo struct {
+	Method         string     `json:"method"`
+	Path           string     `json:"path"`
+	Auth           []AuthMode `json:"auth"`
+	Sucursal       bool       `json:"sucursal"`
+	Permissions    []string   `json:"permissions,omitempty"`
+	PermissionRule string     `json:"permission_rule,omitempty"`
+	Summary        string     `json:"summary,omitempty"`
+
+	rule     permissions.Rule
+	request  any
+	response any
+}
+
+// ReferencedPermissions devuelve los permisos que exige la ruta, sin prefijos.
+func (r RouteInfo) ReferencedPermissions() []string {
+	ids := permissions.Referenced(permissions.FromList(r.Permissions))
+	return append(ids, permissions.Referenced(r.rule)...)
+}
+
+// RouteCatalog describe las rutas registradas en el servidor.
+type RouteCatalog []RouteInfo
+
+func NewRouteCatalog(routes []Route) RouteCatalog {
+	catalog := make(RouteCatalog, 0, len(routes))
+	for _, route := range routes {
+		catalog = append(catalog, DescribeRoute(route))
+	}
+	sort.SliceStable(catalog, func(i, j int) bool {
+		if catalog[i].Path != catalog[j].Path {
+			return catalog[i].Path < catalog[j].Path
+		}
+		return catalog[i].Method < catalog[j].Method
+	})
+	return catalog
+}
+
+// DescribeRoute resume la ruta con el mismo criterio que buildMiddlewares.
+func DescribeRoute(route Route) RouteInfo {
+	info := RouteInfo{Method: route.GetMethod(), Path: route.GetPath()}
+
+	if r, ok := route.(PermissionChecker); ok {
+		info.Permissions = r.CheckPermissions()
+	}
+	if r, ok := route.(PermissionRuleChecker); ok {
+		if rule := r.CheckPermissionRule(); rule != nil {
+			info.rule = rule
+			info.PermissionRule = rule.String()
+		}
+	}
+	if _, ok := route.(sucursalValidator); ok {
+		info.Sucursal = true
+	}
+
+	if _, ok := route.(accessKeyProtect); ok {
+		info.Auth = append(info.Auth, AuthAccessKey)
+	}
+	if _, ok := route.(publicClientJwtProtect); ok {
+		info.Auth = append(info.Auth, AuthPublicClient)
+	}
+	if _, ok := route.(apikeyProtect); ok {
+		info.Auth = append(info.Auth, AuthApiKey)
+	}
+	if len(info.Auth) == 0 {
+		_, public := route.(publicRoute)
+		if !public || info.Sucursal || len(info.Permissions) > 0 || info.PermissionRule != "" {
+			info.Auth = append(info.Auth, AuthJwt)
+		} else {
+			info.Auth = append(info.Auth, AuthPublic)
+		}
+	}
+
+	if r, ok := route.(RouteDescriber); ok {
+		info.Summary = r.RouteSummary()
+	}
+	if r, ok := route.(RequestSchemaProvider); ok {
+		info.request = r.RequestSchema()
+	}
+	if r, ok := route.(ResponseSchemaProvider); ok {
+		info.response = r.ResponseSchema()
+	}
+	return info
+}
+
+func routeCatalogHandler(catalog RouteCatalog) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return answer.Ok(c, catalog)
+	}
+}
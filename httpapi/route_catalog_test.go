@@ -0,0 +1,117 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sfperusacdev/identitysdk/permissions"
+	"github.com/stretchr/testify/require"
+)
+
+type crearPlanillaRequest struct {
+	Codigo  string    `json:"codigo"`
+	Fecha   time.Time `json:"fecha"`
+	Detalle []struct {
+		Monto float64 `json:"monto"`
+	} `json:"detalle,omitempty"`
+}
+
+type planilla struct {
+	Codigo string    `json:"codigo"`
+	Padre  *planilla `json:"padre"`
+}
+
+type crearPlanillaRoute struct {
+	DefaultSucursalHandler
+}
+
+func (*crearPlanillaRoute) RequestSchema() any  { return crearPlanillaRequest{} }
+func (*crearPlanillaRoute) ResponseSchema() any { return planilla{} }
+func (*crearPlanillaRoute) RouteSummary() string {
+	return "Crea una planilla"
+}
+
+type publicApiKeyRoute struct {
+	DefaultApiKeyHandler
+}
+
+func TestRouteCatalogEndpoint(t *testing.T) {
+	server := NewTestServer(t,
+		&crearPlanillaRoute{DefaultSucursalHandler{
+			Method:         http.MethodPost,
+			Path:           "/api/v1/planillas/:id",
+			Permissions:    []string{"planilla.crear"},
+			PermissionRule: permissions.MustParse("planilla.aprobar || g:admin"),
+			Handler:        func(c echo.Context) error { return nil },
+		}},
+		&publicApiKeyRoute{DefaultApiKeyHandler{
+			Path:    "/api/v1/integracion",
+			Handler: func(c echo.Context) error { return nil },
+		}},
+	)
+
+	response, err := server.Client().Get(server.URL + RoutesCatalogPath)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var body struct {
+		Data []RouteInfo `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	require.Len(t, body.Data, 2)
+	require.Equal(t, []AuthMode{AuthApiKey}, body.Data[0].Auth)
+	require.Equal(t, RouteInfo{
+		Method:         http.MethodPost,
+		Path:           "/api/v1/planillas/:id",
+		Auth:           []AuthMode{AuthJwt},
+		Sucursal:       true,
+		Permissions:    []string{"planilla.crear"},
+		PermissionRule: "planilla.aprobar || g:admin",
+		Summary:        "Crea una planilla",
+	}, body.Data[1])
+
+	info := DescribeRoute(&crearPlanillaRoute{DefaultSucursalHandler{
+		Permissions:    []string{"g:planilla.crear"},
+		PermissionRule: permissions.MustParse("planilla.aprobar || g:planilla.crear"),
+	}})
+	require.Equal(t, []string{"planilla.crear", "planilla.aprobar", "planilla.crear"}, info.ReferencedPermissions())
+}
+
+func TestBuildOpenAPI(t *testing.T) {
+	catalog := NewRouteCatalog([]Route{&crearPlanillaRoute{DefaultSucursalHandler{
+		Method: http.MethodPost,
+		Path:   "/api/v1/planillas/:id",
+	}}})
+	document := BuildOpenAPI(OpenAPIInfo{Title: "svc", Version: "1.0.0"}, catalog)
+
+	raw, err := json.Marshal(document)
+	require.NoError(t, err)
+	var doc struct {
+		Paths map[string]map[string]struct {
+			OperationID string           `json:"operationId"`
+			Parameters  []map[string]any `json:"parameters"`
+			Security    []map[string]any `json:"security"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]any `json:"properties"`
+				Required   []string                  `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &doc))
+
+	operation := doc.Paths["/api/v1/planillas/{id}"]["post"]
+	require.Equal(t, "postApiV1PlanillasId", operation.OperationID)
+	require.Len(t, operation.Parameters, 2)
+	require.Contains(t, operation.Security[0], "jwt")
+
+	request := doc.Components.Schemas["crearPlanillaRequest"]
+	require.Equal(t, []string{"codigo", "fecha"}, request.Required)
+	require.Equal(t, "date-time", request.Properties["fecha"]["format"])
+	require.Equal(t, "#/components/schemas/planilla", doc.Components.Schemas["planilla"].Properties["padre"]["$ref"])
+}
@@ -0,0 +1,9 @@
+package httpapi
+
+import "time"
+
+// Idempotent activa la cabecera Idempotency-Key en la ruta; la respuesta se
+// conserva durante IdempotencyRetention (0 usa idempotency.DefaultRetention).
+type Idempotent interface {
+	IdempotencyRetention() time.Duration
+}
@@ -0,0 +1,9 @@
+package httpapi
+
+import "github.com/sfperusacdev/identitysdk/helpers/ratelimit"
+
+// RateLimited declara límites por empresa, usuario o api key para la ruta,
+// p. ej. ratelimit.PerMinute(ratelimit.ScopeEmpresa, 120).
+type RateLimited interface {
+	RateLimits() []ratelimit.Limit
+}
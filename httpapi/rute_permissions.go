@@ -1,5 +1,13 @@
 package httpapi
 
+import "github.com/sfperusacdev/identitysdk/permissions"
+
 type PermissionChecker interface {
 	CheckPermissions() []string
 }
+
+// PermissionRuleChecker declara una expresión de permisos (ver permissions.Parse);
+// se evalúa además de CheckPermissions.
+type PermissionRuleChecker interface {
+	CheckPermissionRule() permissions.Rule
+}
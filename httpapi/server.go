@@ -10,7 +10,11 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/health"
+	"github.com/sfperusacdev/identitysdk/helpers/idempotency"
+	"github.com/sfperusacdev/identitysdk/helpers/ratelimit"
 	"github.com/sfperusacdev/identitysdk/permissions"
+	"github.com/sfperusacdev/identitysdk/tracing"
 	"go.uber.org/fx"
 )
 
@@ -28,6 +32,12 @@ var Module = fx.Module("http-server",
 				RouteTag,
 			),
 		),
+		fx.Annotate(
+			NewRouteCatalog,
+			fx.ParamTags(
+				RouteTag,
+			),
+		),
 	),
 )
 
@@ -45,13 +55,28 @@ func newEchoServer(
 	e.HideBanner = true
 
 	// Middleware global
-	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
-		Format: "time=${time_unix}, method=${method}, uri=${uri}, status=${status}, ip=${remote_ip}, latency=${latency_human}\n",
+	e.Use(requestIDMiddleware)
+	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
 		Skipper: func(c echo.Context) bool {
+			switch c.Path() {
+			case LivenessPath, ReadinessPath:
+				return true
+			}
 			return strings.Contains(c.Path(), "/api/v1/_/system_properties")
 		},
+		LogMethod:     true,
+		LogURI:        true,
+		LogStatus:     true,
+		LogRemoteIP:   true,
+		LogLatency:    true,
+		LogError:      true,
+		HandleError:   true,
+		LogValuesFunc: logRequest,
 	}))
 	e.Use(middleware.Recover())
+	e.Use(tracingMiddleware)
+	e.Use(metricsMiddleware)
+	e.Use(operationMiddleware)
 
 	for _, route := range listRoutes {
 		middlewares := buildMiddlewares(
@@ -77,6 +102,10 @@ func newEchoServer(
 		}
 	}
 
+	e.GET(RoutesCatalogPath, routeCatalogHandler(NewRouteCatalog(listRoutes)), echo.MiddlewareFunc(accessKeyMiddleware))
+	e.GET(LivenessPath, livenessHandler)
+	e.GET(ReadinessPath, readinessHandler)
+
 	return e
 }
 
@@ -133,6 +162,26 @@ func buildMiddlewares(
 		}
 	}
 
+	if r, ok := route.(PermissionRuleChecker); ok {
+		if rule := r.CheckPermissionRule(); rule != nil {
+			if !isRouteProtected {
+				isRouteProtected = true
+				middlewares = append(middlewares, echo.MiddlewareFunc(jwtMiddleware))
+			}
+			middlewares = append(middlewares, permissions.NewRuleMiddleware(rule))
+		}
+	}
+
+	if r, ok := route.(RateLimited); ok {
+		if limits := r.RateLimits(); len(limits) > 0 {
+			middlewares = append(middlewares, ratelimit.Middleware(limits...))
+		}
+	}
+
+	if r, ok := route.(Idempotent); ok {
+		middlewares = append(middlewares, idempotency.Middleware(r.IdempotencyRetention()))
+	}
+
 	if r, ok := route.(MiddlewaresProvider); ok {
 		middlewares = append(middlewares, r.GetMiddlewares()...)
 	}
@@ -140,6 +189,46 @@ func buildMiddlewares(
 	return middlewares
 }
 
+// operationMiddleware guarda la ruta en el contexto (identitysdk.Operation).
+func operationMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := identitysdk.CtxWithOperation(c.Request().Context(), c.Request().Method+" "+c.Path())
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
+	}
+}
+
+// tracingMiddleware continúa la traza del traceparent entrante y registra un span por petición.
+func tracingMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		ctx := tracing.Extract(req.Context(), req.Header)
+		ctx, span := tracing.Start(ctx, req.Method+" "+c.Path(), tracing.SpanKindServer)
+		defer span.End()
+		c.SetRequest(req.WithContext(ctx))
+		c.Response().Header().Set(tracing.HeaderTraceParent, span.SpanContext().TraceParent())
+
+		err := next(c)
+		span.SetError(err)
+
+		ctx = c.Request().Context()
+		span.SetAttribute("http.method", req.Method)
+		span.SetAttribute("http.route", c.Path())
+		status := c.Response().Status
+		if httpErr, ok := err.(*echo.HTTPError); ok && !c.Response().Committed {
+			status = httpErr.Code
+		}
+		span.SetAttribute("http.status_code", status)
+		if empresa := identitysdk.Empresa(ctx); !strings.HasPrefix(empresa, "####") {
+			span.SetAttribute("empresa", empresa)
+		}
+		if username := identitysdk.Username(ctx); !strings.HasPrefix(username, "####") {
+			span.SetAttribute("username", username)
+		}
+		return err
+	}
+}
+
 type ServeURLString string
 
 func StartWebServer(lc fx.Lifecycle, e *echo.Echo, address ServeURLString) {
@@ -153,7 +242,8 @@ func StartWebServer(lc fx.Lifecycle, e *echo.Echo, address ServeURLString) {
 			}()
 			return nil
 		},
-		OnStop: func(ctx context.Context) error {
+		// Shutdown deja de aceptar conexiones y espera las peticiones en curso
+		OnStop: health.OnShutdown(health.PhaseServers, "http-server", func(ctx context.Context) error {
 			slog.Info("Shutting down HTTP server")
 			if err := e.Shutdown(ctx); err != nil {
 				slog.Error("Error shutting down HTTP server", "error", err)
@@ -161,6 +251,6 @@ func StartWebServer(lc fx.Lifecycle, e *echo.Echo, address ServeURLString) {
 			}
 			slog.Info("HTTP server stopped successfully")
 			return nil
-		},
+		}),
 	})
 }
@@ -0,0 +1,23 @@
+package identitysdk
+
+import (
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/metrics"
+)
+
+var identityRequestDuration = metrics.NewHistogram(
+	"identity_request_duration_seconds",
+	"Latencia de las validaciones contra identity por operación y resultado.",
+	nil,
+	"operation", "outcome",
+)
+
+// observeIdentityCall se usa con defer; err apunta al error devuelto por la función.
+func observeIdentityCall(operation string, started time.Time, err *error) {
+	outcome := "success"
+	if *err != nil {
+		outcome = "error"
+	}
+	identityRequestDuration.With(operation, outcome).Observe(time.Since(started).Seconds())
+}
@@ -11,21 +11,32 @@ import (
 	"strings"
 	"time"
 
-	"github.com/allegro/bigcache/v3"
+	"github.com/sfperusacdev/identitysdk/cache"
 	"github.com/sfperusacdev/identitysdk/entities"
+	"github.com/sfperusacdev/identitysdk/internal/revocation"
 )
 
-type MemCacheService interface {
-	Set(ctx context.Context, token string, data entities.ApikeyData)
-	Get(ctx context.Context, token string) *entities.ApikeyData
+const (
+	keyPrefix = "apikey:"
+	ttl       = time.Minute
+)
+
+type MemCacheService = cache.ApiKeyCache
+
+// entry guarda el usuario dueño de la api key para que RevokeUsers la invalide.
+type entry struct {
+	Data     entities.ApikeyData
+	Username string
+	StoredAt time.Time
 }
 
 type sessioncache struct {
-	cache *bigcache.BigCache
+	store cache.Store
 	err   error
 }
 type jwtData struct {
-	ID string `json:"id"`
+	ID       string `json:"id"`
+	Username string `json:"username"`
 }
 
 func (*sessioncache) apikeyData(token string) (jwtData, error) {
@@ -33,9 +44,11 @@ func (*sessioncache) apikeyData(token string) (jwtData, error) {
 	if len(parts) != 3 {
 		return jwtData{}, errors.New("invalid jwt token value")
 	}
-	data, err := base64.RawStdEncoding.DecodeString(parts[1])
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
-		return jwtData{}, err
+		if data, err = base64.RawStdEncoding.DecodeString(parts[1]); err != nil {
+			return jwtData{}, err
+		}
 	}
 	var jsonObject jwtData
 	if err := json.Unmarshal(data, &jsonObject); err != nil {
@@ -62,7 +75,7 @@ func (s *sessioncache) Validar(ctx context.Context, token string) (jwtData, erro
 	return info, nil
 }
 
-func (*sessioncache) encodeGob(data entities.ApikeyData) ([]byte, error) {
+func (*sessioncache) encodeGob(data entry) ([]byte, error) {
 	var buf bytes.Buffer
 	enc := gob.NewEncoder(&buf)
 	err := enc.Encode(data)
@@ -72,15 +85,15 @@ func (*sessioncache) encodeGob(data entities.ApikeyData) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-func (*sessioncache) decodeGob(data []byte) (entities.ApikeyData, error) {
-	var jwtdata entities.ApikeyData
+func (*sessioncache) decodeGob(data []byte) (entry, error) {
+	var e entry
 	buf := bytes.NewBuffer(data)
 	dec := gob.NewDecoder(buf)
-	err := dec.Decode(&jwtdata)
+	err := dec.Decode(&e)
 	if err != nil {
-		return entities.ApikeyData{}, err
+		return entry{}, err
 	}
-	return jwtdata, nil
+	return e, nil
 }
 
 func (s *sessioncache) Set(ctx context.Context, token string, data entities.ApikeyData) {
@@ -88,12 +101,12 @@ func (s *sessioncache) Set(ctx context.Context, token string, data entities.Apik
 	if err != nil {
 		return
 	}
-	encodedData, err := s.encodeGob(data)
+	encodedData, err := s.encodeGob(entry{Data: data, Username: info.Username, StoredAt: time.Now()})
 	if err != nil {
 		slog.Error("Failed to encode data using Gob", "cacheKey", info.ID, "error", err)
 		return
 	}
-	if err := s.cache.Set(info.ID, encodedData); err != nil {
+	if err := s.store.Set(ctx, keyPrefix+info.ID, encodedData, ttl); err != nil {
 		slog.Error("Failed to set data in cache",
 			"cacheKey", info.ID,
 			"error", err.Error(),
@@ -106,27 +119,45 @@ func (s *sessioncache) Get(ctx context.Context, token string) *entities.ApikeyDa
 	if err != nil {
 		return nil
 	}
-	foundData, err := s.cache.Get(info.ID)
+	foundData, err := s.store.Get(ctx, keyPrefix+info.ID)
 	if err != nil {
-		if !errors.Is(err, bigcache.ErrEntryNotFound) {
+		if !errors.Is(err, cache.ErrNotFound) {
 			slog.Error("Failed to retrieve data from cache", "cacheKey", info.ID, "action", "discard")
 		}
 		return nil
 	}
-	jwtData, err := s.decodeGob(foundData)
+	e, err := s.decodeGob(foundData)
 	if err != nil {
 		slog.Error("Failed to decode data using Gob", "cacheKey", info.ID, "error", err)
 		return nil
 	}
-	return &jwtData
+	if e.Username != "" && revocation.Default.IsStale(ctx, e.Username, e.StoredAt) {
+		s.Delete(ctx, info.ID)
+		return nil
+	}
+	return &e.Data
+}
+
+func (s *sessioncache) Delete(ctx context.Context, tokenID string) {
+	if s.err != nil {
+		return
+	}
+	if err := s.store.Delete(ctx, keyPrefix+tokenID); err != nil {
+		slog.Error("Failed to delete data from cache", "cacheKey", tokenID, "error", err)
+	}
+}
+
+// New crea la caché de api keys sobre el store indicado.
+func New(store cache.Store) MemCacheService {
+	return &sessioncache{store: store}
 }
 
 var DefaultCache MemCacheService
 
 func init() {
-	cache, err := bigcache.New(context.Background(), bigcache.DefaultConfig(time.Minute))
+	store, err := cache.NewMemoryStore(ttl)
 	if err != nil {
 		slog.Warn("creating cache")
 	}
-	DefaultCache = &sessioncache{cache, err}
+	DefaultCache = &sessioncache{store, err}
 }
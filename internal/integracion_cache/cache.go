@@ -8,21 +8,21 @@ import (
 	"log/slog"
 	"time"
 
-	"github.com/allegro/bigcache/v3"
+	"github.com/sfperusacdev/identitysdk/cache"
+	"github.com/sfperusacdev/identitysdk/entities"
 )
 
-type IntegracionState struct {
-	ExternalReff   string `json:"external_reff"`
-	IntegrationURL string `json:"integration_url"`
-}
+const (
+	keyPrefix = "integracion:"
+	ttl       = time.Minute
+)
 
-type MemCacheService interface {
-	Set(ctx context.Context, empresa string, state IntegracionState)
-	Get(ctx context.Context, empresa string) *IntegracionState
-}
+type IntegracionState = entities.IntegracionState
+
+type MemCacheService = cache.IntegracionCache
 
 type sessioncache struct {
-	cache *bigcache.BigCache
+	store cache.Store
 	err   error
 }
 
@@ -53,7 +53,10 @@ func (s *sessioncache) Set(ctx context.Context, empresa string, record Integraci
 		slog.Error("Failed to encode data using Gob", "cacheKey", empresa, "error", err)
 		return
 	}
-	if err := s.cache.Set(empresa, encodedData); err != nil {
+	if s.err != nil {
+		return
+	}
+	if err := s.store.Set(ctx, keyPrefix+empresa, encodedData, ttl); err != nil {
 		slog.Error("Failed to set data in cache",
 			"cacheKey", empresa,
 			"error", err.Error(),
@@ -62,9 +65,12 @@ func (s *sessioncache) Set(ctx context.Context, empresa string, record Integraci
 }
 
 func (s *sessioncache) Get(ctx context.Context, empresa string) *IntegracionState {
-	foundData, err := s.cache.Get(empresa)
+	if s.err != nil {
+		return nil
+	}
+	foundData, err := s.store.Get(ctx, keyPrefix+empresa)
 	if err != nil {
-		if !errors.Is(err, bigcache.ErrEntryNotFound) {
+		if !errors.Is(err, cache.ErrNotFound) {
 			slog.Error("Failed to retrieve data from cache", "cacheKey", empresa, "action", "discard")
 		}
 		return nil
@@ -77,12 +83,17 @@ func (s *sessioncache) Get(ctx context.Context, empresa string) *IntegracionStat
 	return record
 }
 
+// New crea la caché sobre el store indicado.
+func New(store cache.Store) MemCacheService {
+	return &sessioncache{store: store}
+}
+
 var DefaultCache MemCacheService
 
 func init() {
-	cache, err := bigcache.New(context.Background(), bigcache.DefaultConfig(time.Minute))
+	store, err := cache.NewMemoryStore(ttl)
 	if err != nil {
 		slog.Warn("creating cache")
 	}
-	DefaultCache = &sessioncache{cache, err}
+	DefaultCache = &sessioncache{store, err}
 }
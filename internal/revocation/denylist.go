@@ -0,0 +1,284 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/cache"
+)
+
+const (
+	defaultMaxEntries = 50_000
+	// DefaultRetention se usa cuando no se conoce el exp del token revocado y
+	// como vida máxima de los tokens para las revocaciones por usuario.
+	DefaultRetention = 24 * time.Hour
+
+	tokenKeyPrefix = "revoked:token:"
+	userKeyPrefix  = "revoked:user:"
+
+	// missTTL tiempo durante el que se recuerda que una clave no estaba en el
+	// store compartido; las revocaciones de otras réplicas pueden tardar eso en verse.
+	missTTL = 5 * time.Second
+)
+
+type userEntry struct {
+	revokedAt time.Time
+	until     time.Time
+}
+
+// DenyList mantiene los tokens y usuarios revocados hasta que sus tokens
+// expiran. El tamaño es acotado: al llenarse se descartan primero las
+// entradas vencidas y luego las que vencen antes.
+type DenyList struct {
+	mu         sync.RWMutex
+	maxEntries int
+	tokens     map[string]time.Time
+	users      map[string]userEntry
+	// misses claves ausentes en el store compartido y hasta cuándo se recuerdan
+	misses map[string]time.Time
+	now    func() time.Time
+	// userRetention vida máxima de un token; una revocación por usuario dura eso.
+	userRetention time.Duration
+	// store opcional compartido entre réplicas; la lista local actúa como primer nivel.
+	store cache.Store
+}
+
+func NewDenyList(maxEntries int) *DenyList {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxEntries
+	}
+	return &DenyList{
+		maxEntries:    maxEntries,
+		tokens:        map[string]time.Time{},
+		users:         map[string]userEntry{},
+		misses:        map[string]time.Time{},
+		now:           time.Now,
+		userRetention: DefaultRetention,
+	}
+}
+
+var Default = NewDenyList(defaultMaxEntries)
+
+// SetStore comparte las revocaciones con otras réplicas a través del store.
+func (d *DenyList) SetStore(store cache.Store) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.store = store
+}
+
+// SetUserRetention define la vida máxima de los tokens emitidos por identity:
+// una revocación por usuario se conserva ese tiempo para cubrir cualquier
+// token emitido antes de ella.
+func (d *DenyList) SetUserRetention(retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.userRetention = retention
+}
+
+func (d *DenyList) retention() time.Duration {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.userRetention
+}
+
+func (d *DenyList) sharedStore() cache.Store {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.store
+}
+
+// RevokeToken agrega el token a la lista hasta until.
+func (d *DenyList) RevokeToken(ctx context.Context, tokenID string, until time.Time) {
+	if tokenID == "" {
+		return
+	}
+	now := d.now()
+	if until.IsZero() {
+		until = now.Add(DefaultRetention)
+	}
+	if !until.After(now) {
+		return
+	}
+	d.storeToken(tokenID, until)
+	if store := d.sharedStore(); store != nil {
+		value := []byte(strconv.FormatInt(until.UnixNano(), 10))
+		if err := store.Set(ctx, tokenKeyPrefix+tokenID, value, until.Sub(now)); err != nil {
+			slog.Error("failed to share token revocation", "token_id", tokenID, "error", err)
+		}
+	}
+}
+
+func (d *DenyList) storeToken(tokenID string, until time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if current, found := d.tokens[tokenID]; found && current.After(until) {
+		return
+	}
+	d.ensureCapacity(d.now())
+	d.tokens[tokenID] = until
+}
+
+// RevokeUser invalida todas las sesiones del usuario emitidas o cacheadas antes de revokedAt.
+func (d *DenyList) RevokeUser(ctx context.Context, username string, revokedAt time.Time) {
+	if username == "" {
+		return
+	}
+	if revokedAt.IsZero() {
+		revokedAt = d.now()
+	}
+	d.storeUser(username, revokedAt)
+	if store := d.sharedStore(); store != nil {
+		value := []byte(strconv.FormatInt(revokedAt.UnixNano(), 10))
+		if err := store.Set(ctx, userKeyPrefix+username, value, d.retention()); err != nil {
+			slog.Error("failed to share user revocation", "username", username, "error", err)
+		}
+	}
+}
+
+func (d *DenyList) storeUser(username string, revokedAt time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if current, found := d.users[username]; found && current.revokedAt.After(revokedAt) {
+		return
+	}
+	d.ensureCapacity(d.now())
+	d.users[username] = userEntry{revokedAt: revokedAt, until: revokedAt.Add(d.userRetention)}
+}
+
+func (d *DenyList) IsTokenRevoked(ctx context.Context, tokenID string) bool {
+	d.mu.RLock()
+	until, found := d.tokens[tokenID]
+	d.mu.RUnlock()
+	if found && d.now().Before(until) {
+		return true
+	}
+	until, found = d.lookupShared(ctx, tokenKeyPrefix+tokenID)
+	if !found || !d.now().Before(until) {
+		return false
+	}
+	d.storeToken(tokenID, until)
+	return true
+}
+
+// UserRevokedAt devuelve el momento de la última revocación vigente del usuario.
+func (d *DenyList) UserRevokedAt(ctx context.Context, username string) (time.Time, bool) {
+	d.mu.RLock()
+	entry, found := d.users[username]
+	d.mu.RUnlock()
+	found = found && d.now().Before(entry.until)
+
+	if shared, ok := d.lookupShared(ctx, userKeyPrefix+username); ok && (!found || shared.After(entry.revokedAt)) {
+		d.storeUser(username, shared)
+		return shared, true
+	}
+	if !found {
+		return time.Time{}, false
+	}
+	return entry.revokedAt, true
+}
+
+// lookupShared consulta key en el store compartido. Una ausencia o un error se
+// recuerdan durante missTTL para no repetir la consulta en cada petición.
+func (d *DenyList) lookupShared(ctx context.Context, key string) (time.Time, bool) {
+	store := d.sharedStore()
+	if store == nil {
+		return time.Time{}, false
+	}
+	d.mu.RLock()
+	missUntil, missed := d.misses[key]
+	d.mu.RUnlock()
+	if missed && d.now().Before(missUntil) {
+		return time.Time{}, false
+	}
+	value, err := store.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, cache.ErrNotFound) {
+			slog.Error("failed to read shared revocation", "key", key, "error", err)
+		}
+		d.storeMiss(key)
+		return time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
+
+func (d *DenyList) storeMiss(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	if len(d.misses) >= d.maxEntries {
+		for k, until := range d.misses {
+			if !now.Before(until) {
+				delete(d.misses, k)
+			}
+		}
+		if len(d.misses) >= d.maxEntries {
+			clear(d.misses)
+		}
+	}
+	d.misses[key] = now.Add(missTTL)
+}
+
+// IsStale indica si un dato del usuario guardado en storedAt quedó invalidado por una revocación.
+func (d *DenyList) IsStale(ctx context.Context, username string, storedAt time.Time) bool {
+	revokedAt, found := d.UserRevokedAt(ctx, username)
+	return found && !storedAt.After(revokedAt)
+}
+
+func (d *DenyList) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.tokens) + len(d.users)
+}
+
+func (d *DenyList) ensureCapacity(now time.Time) {
+	if len(d.tokens)+len(d.users) < d.maxEntries {
+		return
+	}
+	for id, until := range d.tokens {
+		if !now.Before(until) {
+			delete(d.tokens, id)
+		}
+	}
+	for username, entry := range d.users {
+		if !now.Before(entry.until) {
+			delete(d.users, username)
+		}
+	}
+	for len(d.tokens)+len(d.users) >= d.maxEntries {
+		d.evictSoonest()
+	}
+}
+
+func (d *DenyList) evictSoonest() {
+	var (
+		tokenID  string
+		username string
+		soonest  time.Time
+	)
+	for id, until := range d.tokens {
+		if soonest.IsZero() || until.Before(soonest) {
+			tokenID, username, soonest = id, "", until
+		}
+	}
+	for name, entry := range d.users {
+		if soonest.IsZero() || entry.until.Before(soonest) {
+			tokenID, username, soonest = "", name, entry.until
+		}
+	}
+	if username != "" {
+		delete(d.users, username)
+		return
+	}
+	delete(d.tokens, tokenID)
+}
@@ -0,0 +1,131 @@
+package revocation
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/cache"
+	"github.com/sfperusacdev/identitysdk/cache/cachetest"
+)
+
+func TestDenyListTokenUntilExp(t *testing.T) {
+	now := time.Now()
+	ctx := context.Background()
+	d := NewDenyList(10)
+	d.now = func() time.Time { return now }
+
+	d.RevokeToken(ctx, "a", now.Add(time.Minute))
+	d.RevokeToken(ctx, "expired", now.Add(-time.Minute))
+
+	if !d.IsTokenRevoked(ctx, "a") {
+		t.Fatal("expected token a to be revoked")
+	}
+	if d.IsTokenRevoked(ctx, "expired") {
+		t.Fatal("already expired tokens should not be stored")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if d.IsTokenRevoked(ctx, "a") {
+		t.Fatal("revocation should end at token exp")
+	}
+}
+
+func TestDenyListUserStaleness(t *testing.T) {
+	now := time.Now()
+	ctx := context.Background()
+	d := NewDenyList(10)
+	d.now = func() time.Time { return now }
+
+	d.RevokeUser(ctx, "kevin", now)
+	if !d.IsStale(ctx, "kevin", now.Add(-time.Second)) {
+		t.Fatal("data stored before the revocation must be stale")
+	}
+	if d.IsStale(ctx, "kevin", now.Add(time.Second)) {
+		t.Fatal("data stored after the revocation must be valid")
+	}
+	if d.IsStale(ctx, "otro", now.Add(-time.Second)) {
+		t.Fatal("other users are not affected")
+	}
+}
+
+func TestDenyListUserRetention(t *testing.T) {
+	now := time.Now()
+	ctx := context.Background()
+	d := NewDenyList(10)
+	d.now = func() time.Time { return now }
+	d.SetUserRetention(72 * time.Hour)
+
+	d.RevokeUser(ctx, "kevin", now)
+	now = now.Add(48 * time.Hour)
+	if !d.IsStale(ctx, "kevin", now.Add(-49*time.Hour)) {
+		t.Fatal("user revocation must last the maximum token lifetime")
+	}
+	now = now.Add(25 * time.Hour)
+	if d.IsStale(ctx, "kevin", now.Add(-74*time.Hour)) {
+		t.Fatal("user revocation must end after the maximum token lifetime")
+	}
+}
+
+func TestDenyListIsBounded(t *testing.T) {
+	now := time.Now()
+	ctx := context.Background()
+	d := NewDenyList(3)
+	d.now = func() time.Time { return now }
+
+	d.RevokeToken(ctx, "soonest", now.Add(time.Minute))
+	d.RevokeToken(ctx, "b", now.Add(time.Hour))
+	d.RevokeToken(ctx, "c", now.Add(time.Hour))
+	d.RevokeToken(ctx, "d", now.Add(time.Hour))
+
+	if d.Len() != 3 {
+		t.Fatalf("expected 3 entries, got %d", d.Len())
+	}
+	if d.IsTokenRevoked(ctx, "soonest") {
+		t.Fatal("the entry expiring first should be evicted")
+	}
+	if !d.IsTokenRevoked(ctx, "d") {
+		t.Fatal("newest entry should be kept")
+	}
+}
+
+type countingStore struct {
+	cache.Store
+	gets atomic.Int32
+}
+
+func (s *countingStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.gets.Add(1)
+	return s.Store.Get(ctx, key)
+}
+
+func TestDenyListRemembersSharedMisses(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	shared := &countingStore{Store: cachetest.NewStore()}
+	d := NewDenyList(10)
+	d.now = func() time.Time { return now }
+	d.SetStore(shared)
+
+	for range 3 {
+		if d.IsTokenRevoked(ctx, "a") {
+			t.Fatal("token a is not revoked")
+		}
+	}
+	if shared.gets.Load() != 1 {
+		t.Fatalf("expected 1 shared lookup, got %d", shared.gets.Load())
+	}
+
+	// otra réplica revoca el token: se ve al vencer la ausencia recordada
+	other := NewDenyList(10)
+	other.SetStore(shared)
+	other.RevokeToken(ctx, "a", now.Add(time.Hour))
+	if d.IsTokenRevoked(ctx, "a") {
+		t.Fatal("the miss should still be remembered")
+	}
+	now = now.Add(missTTL)
+	if !d.IsTokenRevoked(ctx, "a") {
+		t.Fatal("expected the shared revocation once the miss expired")
+	}
+}
@@ -11,19 +11,31 @@ import (
 	"strings"
 	"time"
 
-	"github.com/allegro/bigcache/v3"
+	"github.com/sfperusacdev/identitysdk/cache"
 	"github.com/sfperusacdev/identitysdk/entities"
+	"github.com/sfperusacdev/identitysdk/internal/revocation"
 )
 
 var maxchache = 10 * time.Second
 
-type MemCacheService interface {
-	Set(ctx context.Context, token string, data entities.JwtData)
-	Get(ctx context.Context, token string) *entities.JwtData
+// freshFor es el tiempo que una sesión se considera vigente sin volver a consultar identity.
+// Las entradas se conservan hasta retainFor para poder servirlas si identity no responde.
+var (
+	freshFor  = time.Minute
+	retainFor = 30 * time.Minute
+)
+
+const keyPrefix = "session:"
+
+type MemCacheService = cache.SessionCache
+
+type entry struct {
+	Data     entities.JwtData
+	StoredAt time.Time
 }
 
 type sessioncache struct {
-	cache *bigcache.BigCache
+	store cache.Store
 	err   error
 }
 type jwtData struct {
@@ -36,9 +48,11 @@ func (*sessioncache) tokenData(token string) (jwtData, error) {
 	if len(parts) != 3 {
 		return jwtData{}, errors.New("invalid jwt token value")
 	}
-	data, err := base64.RawStdEncoding.DecodeString(parts[1])
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
-		return jwtData{}, err
+		if data, err = base64.RawStdEncoding.DecodeString(parts[1]); err != nil {
+			return jwtData{}, err
+		}
 	}
 	var jsonObject jwtData
 	if err := json.Unmarshal(data, &jsonObject); err != nil {
@@ -50,6 +64,12 @@ func (*sessioncache) tokenData(token string) (jwtData, error) {
 	return jsonObject, nil
 }
 
+// TokenID devuelve el claim id del token sin verificar la firma.
+func TokenID(token string) (string, error) {
+	info, err := (*sessioncache)(nil).tokenData(token)
+	return info.ID, err
+}
+
 func (s *sessioncache) Validar(ctx context.Context, token string) (jwtData, error) {
 	info, err := s.tokenData(token)
 	if err != nil {
@@ -70,7 +90,7 @@ func (s *sessioncache) Validar(ctx context.Context, token string) (jwtData, erro
 	return info, nil
 }
 
-func (*sessioncache) encodeGob(data entities.JwtData) ([]byte, error) {
+func (*sessioncache) encodeGob(data entry) ([]byte, error) {
 	var buf bytes.Buffer
 	enc := gob.NewEncoder(&buf)
 	err := enc.Encode(data)
@@ -80,15 +100,15 @@ func (*sessioncache) encodeGob(data entities.JwtData) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-func (*sessioncache) decodeGob(data []byte) (entities.JwtData, error) {
-	var jwtdata entities.JwtData
+func (*sessioncache) decodeGob(data []byte) (entry, error) {
+	var e entry
 	buf := bytes.NewBuffer(data)
 	dec := gob.NewDecoder(buf)
-	err := dec.Decode(&jwtdata)
+	err := dec.Decode(&e)
 	if err != nil {
-		return entities.JwtData{}, err
+		return entry{}, err
 	}
-	return jwtdata, nil
+	return e, nil
 }
 
 func (s *sessioncache) Set(ctx context.Context, token string, data entities.JwtData) {
@@ -96,12 +116,16 @@ func (s *sessioncache) Set(ctx context.Context, token string, data entities.JwtD
 	if err != nil {
 		return
 	}
-	encodedData, err := s.encodeGob(data)
+	encodedData, err := s.encodeGob(entry{Data: data, StoredAt: time.Now()})
 	if err != nil {
 		slog.Error("Failed to encode data using Gob", "cacheKey", info.ID, "error", err)
 		return
 	}
-	if err := s.cache.Set(info.ID, encodedData); err != nil {
+	ttl := min(retainFor, time.Until(time.Unix(info.Exp, 0).Add(maxchache)))
+	if ttl <= 0 {
+		return // los stores tratan ttl <= 0 como sin expiración
+	}
+	if err := s.store.Set(ctx, keyPrefix+info.ID, encodedData, ttl); err != nil {
 		slog.Error("Failed to set data in cache",
 			"cacheKey", info.ID,
 			"error", err.Error(),
@@ -110,31 +134,57 @@ func (s *sessioncache) Set(ctx context.Context, token string, data entities.JwtD
 }
 
 func (s *sessioncache) Get(ctx context.Context, token string) *entities.JwtData {
+	data, storedAt := s.Lookup(ctx, token)
+	if data == nil || time.Since(storedAt) > freshFor {
+		return nil
+	}
+	return data
+}
+
+func (s *sessioncache) Lookup(ctx context.Context, token string) (*entities.JwtData, time.Time) {
 	info, err := s.Validar(ctx, token)
 	if err != nil {
-		return nil
+		return nil, time.Time{}
 	}
-	foundData, err := s.cache.Get(info.ID)
+	foundData, err := s.store.Get(ctx, keyPrefix+info.ID)
 	if err != nil {
-		if !errors.Is(err, bigcache.ErrEntryNotFound) {
+		if !errors.Is(err, cache.ErrNotFound) {
 			slog.Error("Failed to retrieve data from cache", "cacheKey", info.ID, "action", "discard")
 		}
-		return nil
+		return nil, time.Time{}
 	}
-	jwtData, err := s.decodeGob(foundData)
+	e, err := s.decodeGob(foundData)
 	if err != nil {
 		slog.Error("Failed to decode data using Gob", "cacheKey", info.ID, "error", err)
-		return nil
+		return nil, time.Time{}
+	}
+	if revocation.Default.IsStale(ctx, e.Data.Jwt.Username, e.StoredAt) {
+		s.Delete(ctx, info.ID)
+		return nil, time.Time{}
+	}
+	return &e.Data, e.StoredAt
+}
+
+func (s *sessioncache) Delete(ctx context.Context, tokenID string) {
+	if s.err != nil {
+		return
 	}
-	return &jwtData
+	if err := s.store.Delete(ctx, keyPrefix+tokenID); err != nil {
+		slog.Error("Failed to delete data from cache", "cacheKey", tokenID, "error", err)
+	}
+}
+
+// New crea la caché de sesiones sobre el store indicado.
+func New(store cache.Store) MemCacheService {
+	return &sessioncache{store: store}
 }
 
 var DefaultCache MemCacheService
 
 func init() {
-	cache, err := bigcache.New(context.Background(), bigcache.DefaultConfig(time.Minute))
+	store, err := cache.NewMemoryStore(retainFor)
 	if err != nil {
 		slog.Warn("creating cache")
 	}
-	DefaultCache = &sessioncache{cache, err}
+	DefaultCache = &sessioncache{store, err}
 }
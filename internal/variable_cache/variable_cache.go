@@ -9,17 +9,19 @@ import (
 	"strings"
 	"time"
 
-	"github.com/allegro/bigcache/v3"
+	"github.com/sfperusacdev/identitysdk/cache"
 	"github.com/sfperusacdev/identitysdk/entities"
 )
 
-type MemVariablesCacheService interface {
-	SetVariables(ctx context.Context, empresa string, variables []entities.Variable)
-	GetVariable(ctx context.Context, empresa string, variableName string) *string
-}
+const (
+	keyPrefix = "variables:"
+	ttl       = time.Minute
+)
+
+type MemVariablesCacheService = cache.VariablesCache
 
 type sessioncache struct {
-	cache *bigcache.BigCache
+	store cache.Store
 	err   error
 }
 
@@ -50,7 +52,10 @@ func (s *sessioncache) SetVariables(ctx context.Context, empresa string, variabl
 		slog.Error("Failed to encode data using Gob", "cacheKey", empresa, "error", err)
 		return
 	}
-	if err := s.cache.Set(empresa, encodedData); err != nil {
+	if s.err != nil {
+		return
+	}
+	if err := s.store.Set(ctx, keyPrefix+empresa, encodedData, ttl); err != nil {
 		slog.Error("Failed to set data in cache",
 			"cacheKey", empresa,
 			"error", err.Error(),
@@ -59,9 +64,12 @@ func (s *sessioncache) SetVariables(ctx context.Context, empresa string, variabl
 }
 
 func (s *sessioncache) GetVariable(ctx context.Context, empresa string, variableName string) *string {
-	foundData, err := s.cache.Get(empresa)
+	if s.err != nil {
+		return nil
+	}
+	foundData, err := s.store.Get(ctx, keyPrefix+empresa)
 	if err != nil {
-		if !errors.Is(err, bigcache.ErrEntryNotFound) {
+		if !errors.Is(err, cache.ErrNotFound) {
 			slog.Error("Failed to retrieve data from cache", "cacheKey", empresa, "action", "discard")
 		}
 		return nil
@@ -80,12 +88,17 @@ func (s *sessioncache) GetVariable(ctx context.Context, empresa string, variable
 	return nil
 }
 
+// New crea la caché sobre el store indicado.
+func New(store cache.Store) MemVariablesCacheService {
+	return &sessioncache{store: store}
+}
+
 var DefaultCache MemVariablesCacheService
 
 func init() {
-	cache, err := bigcache.New(context.Background(), bigcache.DefaultConfig(time.Minute))
+	store, err := cache.NewMemoryStore(ttl)
 	if err != nil {
 		slog.Warn("creating cache")
 	}
-	DefaultCache = &sessioncache{cache, err}
+	DefaultCache = &sessioncache{store, err}
 }
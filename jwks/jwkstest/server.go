@@ -0,0 +1,180 @@
+// Package jwkstest provides a stand-in identity JWKS endpoint that can sign
+// tokens, so services can test offline verification without identity.
+package jwkstest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/jwks"
+)
+
+type signingKey struct {
+	kid     string
+	private *rsa.PrivateKey
+}
+
+// Server publishes an RS256 key set at URL() and signs tokens with the
+// current key. Rotate replaces the signing key while keeping the old one
+// published, mimicking identity during a key rotation.
+type Server struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	keys    []signingKey
+	serial  int
+	fetches int
+	hang    chan struct{}
+}
+
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{}
+	s.Rotate(t)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveJWKS))
+	t.Cleanup(s.Server.Close)
+	return s
+}
+
+// URL returns the JWKS endpoint.
+func (s *Server) URL() string { return s.Server.URL + "/.well-known/jwks.json" }
+
+// Fetches returns how many times the key set was downloaded.
+func (s *Server) Fetches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetches
+}
+
+// Rotate creates a new signing key. Previously issued tokens stay valid
+// until RetireOldKeys is called.
+func (s *Server) Rotate(t testing.TB) {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating rsa key: %v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.serial++
+	s.keys = append(s.keys, signingKey{kid: fmt.Sprintf("test-key-%d", s.serial), private: private})
+}
+
+// RetireOldKeys stops publishing every key except the current one.
+func (s *Server) RetireOldKeys() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = s.keys[len(s.keys)-1:]
+}
+
+// Sign returns a signed token for the given claims. When exp is missing it
+// defaults to one hour from now and a random id is added when absent.
+func (s *Server) Sign(t testing.TB, claims map[string]any) string {
+	t.Helper()
+
+	s.mu.Lock()
+	key := s.keys[len(s.keys)-1]
+	s.mu.Unlock()
+
+	payload := map[string]any{}
+	for k, v := range claims {
+		payload[k] = v
+	}
+	if _, ok := payload["exp"]; !ok {
+		payload["exp"] = time.Now().Add(time.Hour).Unix()
+	}
+	if _, ok := payload["id"]; !ok {
+		payload["id"] = randomID()
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": key.kid})
+	if err != nil {
+		t.Fatalf("encoding header: %v", err)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("encoding claims: %v", err)
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key.private, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// Hang makes the endpoint accept requests without answering them until
+// release is called, mimicking an unresponsive identity.
+func (s *Server) Hang() (release func()) {
+	hang := make(chan struct{})
+	s.mu.Lock()
+	s.hang = hang
+	s.mu.Unlock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			s.hang = nil
+			s.mu.Unlock()
+			close(hang)
+		})
+	}
+}
+
+func (s *Server) serveJWKS(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/.well-known/jwks.json" {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	hang := s.hang
+	s.mu.Unlock()
+	if hang != nil {
+		select {
+		case <-hang:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.fetches++
+	doc := jwks.Document{}
+	for _, key := range s.keys {
+		pub := key.private.PublicKey
+		doc.Keys = append(doc.Keys, jwks.JSONWebKey{
+			Kty: "RSA",
+			Kid: key.kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		})
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(doc)
+}
+
+func randomID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return fmt.Sprintf("%x", b[:])
+}
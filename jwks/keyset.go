@@ -0,0 +1,280 @@
+// Package jwks verifica localmente los JWT emitidos por identity usando las
+// llaves públicas publicadas en su endpoint JWKS.
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	ErrKeySetUnavailable = errors.New("jwks: key set unavailable")
+	ErrUnknownKey        = errors.New("jwks: unknown key id")
+)
+
+const (
+	defaultRefreshInterval    = 15 * time.Minute
+	defaultMinRefreshInterval = 30 * time.Second
+)
+
+// JSONWebKey es la representación de una llave pública dentro del documento JWKS.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type Document struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+type KeySetOption func(*KeySet)
+
+// WithHTTPClient define el cliente usado para descargar el documento JWKS.
+func WithHTTPClient(client *http.Client) KeySetOption {
+	return func(k *KeySet) {
+		if client != nil {
+			k.client = client
+		}
+	}
+}
+
+// WithRefreshInterval define cada cuánto se vuelven a descargar las llaves.
+func WithRefreshInterval(d time.Duration) KeySetOption {
+	return func(k *KeySet) {
+		if d > 0 {
+			k.refreshInterval = d
+		}
+	}
+}
+
+// WithMinRefreshInterval limita la frecuencia de descargas forzadas por un kid desconocido.
+func WithMinRefreshInterval(d time.Duration) KeySetOption {
+	return func(k *KeySet) {
+		if d >= 0 {
+			k.minRefreshInterval = d
+		}
+	}
+}
+
+// KeySet mantiene en memoria las llaves publicadas por identity y las
+// refresca periódicamente o cuando aparece un kid desconocido (rotación).
+type KeySet struct {
+	url                string
+	client             *http.Client
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+	refreshing  bool
+}
+
+func NewKeySet(url string, opts ...KeySetOption) *KeySet {
+	k := &KeySet{
+		url:                url,
+		client:             &http.Client{Timeout: 10 * time.Second},
+		refreshInterval:    defaultRefreshInterval,
+		minRefreshInterval: defaultMinRefreshInterval,
+		keys:               map[string]crypto.PublicKey{},
+	}
+	for _, apply := range opts {
+		apply(k)
+	}
+	return k
+}
+
+func (k *KeySet) URL() string { return k.url }
+
+// Key devuelve la llave pública asociada al kid. Una llave conocida se
+// devuelve de inmediato aunque el conjunto esté vencido, y el refresco corre en
+// segundo plano; un kid desconocido (rotación) refresca antes de responder.
+// Ambos refrescos respetan WithMinRefreshInterval.
+func (k *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	k.mu.RLock()
+	key, found := k.keys[kid]
+	stale := time.Since(k.fetchedAt) > k.refreshInterval
+	canRetry := time.Since(k.lastAttempt) >= k.minRefreshInterval
+	k.mu.RUnlock()
+
+	if found {
+		if stale && canRetry {
+			k.refreshInBackground()
+		}
+		return key, nil
+	}
+	if !canRetry {
+		return nil, ErrUnknownKey
+	}
+	if err := k.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if key, found := k.keys[kid]; found {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// refreshInBackground refresca el conjunto sin bloquear al llamador; si ya
+// hay un refresco en curso no inicia otro.
+func (k *KeySet) refreshInBackground() {
+	k.mu.Lock()
+	if k.refreshing {
+		k.mu.Unlock()
+		return
+	}
+	k.refreshing = true
+	k.mu.Unlock()
+
+	go func() {
+		defer func() {
+			k.mu.Lock()
+			k.refreshing = false
+			k.mu.Unlock()
+		}()
+		if err := k.Refresh(context.Background()); err != nil {
+			// identity no responde: las llaves conocidas siguen siendo válidas
+			slog.Warn("jwks refresh failed, using cached keys", "url", k.url, "error", err)
+		}
+	}()
+}
+
+// Refresh descarga nuevamente el documento JWKS.
+func (k *KeySet) Refresh(ctx context.Context) error {
+	k.mu.Lock()
+	k.lastAttempt = time.Now()
+	k.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrKeySetUnavailable, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrKeySetUnavailable, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: unexpected status %d", ErrKeySetUnavailable, res.StatusCode)
+	}
+
+	var doc Document
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		return fmt.Errorf("%w: %v", ErrKeySetUnavailable, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			slog.Warn("ignoring invalid jwk", "kid", jwk.Kid, "error", err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("%w: no usable keys", ErrKeySetUnavailable)
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.fetchedAt = time.Now()
+	k.mu.Unlock()
+	return nil
+}
+
+// PublicKey convierte el JWK en una llave pública de crypto.
+func (j JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := decodeBigInt(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(j.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid rsa exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, err := decodeBigInt(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(j.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("ec point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", j.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, errors.New("missing key parameter")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,237 @@
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken     = errors.New("jwks: invalid token")
+	ErrTokenExpired     = errors.New("jwks: token expired")
+	ErrTokenNotYetValid = errors.New("jwks: token not valid yet")
+	ErrInvalidAudience  = errors.New("jwks: invalid audience")
+)
+
+// Audience acepta tanto un string como un arreglo en el claim aud.
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// Claims son los claims registrados que se validan localmente.
+type Claims struct {
+	ID        string   `json:"id"`
+	JTI       string   `json:"jti"`
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  Audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	IssuedAt  int64    `json:"iat"`
+}
+
+// TokenID devuelve el identificador del token (id o jti).
+func (c Claims) TokenID() string {
+	if c.ID != "" {
+		return c.ID
+	}
+	return c.JTI
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+type VerifierOption func(*Verifier)
+
+// WithAudience exige que el token contenga al menos una de las audiencias.
+func WithAudience(audience ...string) VerifierOption {
+	return func(v *Verifier) {
+		for _, aud := range audience {
+			if aud = strings.TrimSpace(aud); aud != "" {
+				v.audience = append(v.audience, aud)
+			}
+		}
+	}
+}
+
+// WithLeeway tolera diferencias de reloj al validar exp y nbf.
+func WithLeeway(d time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		if d >= 0 {
+			v.leeway = d
+		}
+	}
+}
+
+// WithClock reemplaza el reloj, útil en pruebas.
+func WithClock(now func() time.Time) VerifierOption {
+	return func(v *Verifier) {
+		if now != nil {
+			v.now = now
+		}
+	}
+}
+
+type Verifier struct {
+	keys     *KeySet
+	audience []string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+func NewVerifier(keys *KeySet, opts ...VerifierOption) *Verifier {
+	v := &Verifier{keys: keys, leeway: 30 * time.Second, now: time.Now}
+	for _, apply := range opts {
+		apply(v)
+	}
+	return v
+}
+
+// Verify valida firma, exp, nbf y aud del token.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+
+	key, err := v.keys.Key(ctx, h.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature encoding", ErrInvalidToken)
+	}
+	if err := verifySignature(h.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: payload: %v", ErrInvalidToken, err)
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+func (v *Verifier) validateClaims(c Claims) error {
+	now := v.now()
+	if c.ExpiresAt == 0 {
+		return fmt.Errorf("%w: missing exp", ErrInvalidToken)
+	}
+	if now.After(time.Unix(c.ExpiresAt, 0).Add(v.leeway)) {
+		return ErrTokenExpired
+	}
+	if c.NotBefore != 0 && now.Add(v.leeway).Before(time.Unix(c.NotBefore, 0)) {
+		return ErrTokenNotYetValid
+	}
+	if len(v.audience) > 0 {
+		if !slices.ContainsFunc(c.Audience, func(aud string) bool {
+			return slices.Contains(v.audience, aud)
+		}) {
+			return ErrInvalidAudience
+		}
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed, signature []byte) error {
+	switch alg {
+	case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match alg")
+		}
+		hashID, digest := hashFor(alg[2:], signed)
+		if strings.HasPrefix(alg, "PS") {
+			return rsa.VerifyPSS(pub, hashID, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.VerifyPKCS1v15(pub, hashID, digest, signature)
+	case "ES256", "ES384", "ES512":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match alg")
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid ecdsa signature size")
+		}
+		_, digest := hashFor(alg[2:], signed)
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errors.New("ecdsa verification failed")
+		}
+		return nil
+	case "EdDSA":
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return errors.New("key type does not match alg")
+		}
+		if !ed25519.Verify(pub, signed, signature) {
+			return errors.New("ed25519 verification failed")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported alg %q", alg)
+	}
+}
+
+func hashFor(bits string, data []byte) (crypto.Hash, []byte) {
+	var h hash.Hash
+	var id crypto.Hash
+	switch bits {
+	case "384":
+		h, id = sha512.New384(), crypto.SHA384
+	case "512":
+		h, id = sha512.New(), crypto.SHA512
+	default:
+		h, id = sha256.New(), crypto.SHA256
+	}
+	h.Write(data)
+	return id, h.Sum(nil)
+}
@@ -0,0 +1,129 @@
+package jwks_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/jwks"
+	"github.com/sfperusacdev/identitysdk/jwks/jwkstest"
+)
+
+func TestVerifyValidToken(t *testing.T) {
+	server := jwkstest.NewServer(t)
+	verifier := jwks.NewVerifier(jwks.NewKeySet(server.URL()), jwks.WithAudience("planillas"))
+
+	token := server.Sign(t, map[string]any{"id": "abc", "aud": "planillas"})
+	claims, err := verifier.Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.TokenID() != "abc" {
+		t.Fatalf("expected token id abc, got %q", claims.TokenID())
+	}
+}
+
+func TestVerifyRejectsInvalidClaims(t *testing.T) {
+	server := jwkstest.NewServer(t)
+	verifier := jwks.NewVerifier(
+		jwks.NewKeySet(server.URL()),
+		jwks.WithAudience("planillas"),
+		jwks.WithLeeway(time.Second),
+	)
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		claims map[string]any
+		want   error
+	}{
+		{"expired", map[string]any{"aud": "planillas", "exp": now.Add(-time.Minute).Unix()}, jwks.ErrTokenExpired},
+		{"not yet valid", map[string]any{"aud": "planillas", "nbf": now.Add(time.Minute).Unix()}, jwks.ErrTokenNotYetValid},
+		{"wrong audience", map[string]any{"aud": []string{"otros"}}, jwks.ErrInvalidAudience},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(context.Background(), server.Sign(t, tt.claims))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsTamperedToken(t *testing.T) {
+	server := jwkstest.NewServer(t)
+	verifier := jwks.NewVerifier(jwks.NewKeySet(server.URL()))
+
+	token := server.Sign(t, map[string]any{"id": "abc"})
+	other := server.Sign(t, map[string]any{"id": "xyz"})
+	tampered := token[:len(token)-10] + other[len(other)-10:]
+
+	if _, err := verifier.Verify(context.Background(), tampered); !errors.Is(err, jwks.ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestVerifyRefreshesKeysOnRotation(t *testing.T) {
+	server := jwkstest.NewServer(t)
+	keySet := jwks.NewKeySet(server.URL(), jwks.WithMinRefreshInterval(0))
+	verifier := jwks.NewVerifier(keySet)
+
+	if _, err := verifier.Verify(context.Background(), server.Sign(t, nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server.Rotate(t)
+	server.RetireOldKeys()
+	if _, err := verifier.Verify(context.Background(), server.Sign(t, nil)); err != nil {
+		t.Fatalf("unexpected error after rotation: %v", err)
+	}
+	if server.Fetches() != 2 {
+		t.Fatalf("expected 2 key set fetches, got %d", server.Fetches())
+	}
+}
+
+func TestVerifyKeySetUnavailable(t *testing.T) {
+	server := jwkstest.NewServer(t)
+	token := server.Sign(t, nil)
+	server.Close()
+
+	verifier := jwks.NewVerifier(jwks.NewKeySet(server.URL()))
+	if _, err := verifier.Verify(context.Background(), token); !errors.Is(err, jwks.ErrKeySetUnavailable) {
+		t.Fatalf("expected ErrKeySetUnavailable, got %v", err)
+	}
+}
+
+func TestVerifyDoesNotWaitForStaleRefresh(t *testing.T) {
+	server := jwkstest.NewServer(t)
+	keySet := jwks.NewKeySet(server.URL(), jwks.WithRefreshInterval(time.Millisecond), jwks.WithMinRefreshInterval(0))
+	verifier := jwks.NewVerifier(keySet)
+	token := server.Sign(t, nil)
+
+	if _, err := verifier.Verify(context.Background(), token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release := server.Hang()
+	t.Cleanup(release)
+	time.Sleep(5 * time.Millisecond)
+
+	for range 3 {
+		done := make(chan error, 1)
+		go func() {
+			_, err := verifier.Verify(context.Background(), token)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("expected cached key, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Verify blocked on the stale key set refresh")
+		}
+	}
+	if server.Fetches() != 1 {
+		t.Fatalf("hung refreshes must not be counted as fetches, got %d", server.Fetches())
+	}
+}
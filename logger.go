@@ -0,0 +1,41 @@
+package identitysdk
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/sfperusacdev/identitysdk/logging"
+	"github.com/sfperusacdev/identitysdk/tracing"
+)
+
+// Logger devuelve un logger con los datos de identidad del contexto (empresa,
+// sucursal, usuario, origen, request id y trace id). Respeta el nivel que se
+// haya configurado para la empresa con logging.SetTenantLevel.
+func Logger(ctx context.Context) *slog.Logger {
+	empresa, sucursal := Empresa_Sucursal(ctx)
+	if strings.HasPrefix(empresa, "####") {
+		empresa = ""
+	}
+	attrs := make([]any, 0, 12)
+	appendAttr := func(key, value string) {
+		if value != "" && !strings.HasPrefix(value, "####") {
+			attrs = append(attrs, key, value)
+		}
+	}
+	appendAttr("empresa", empresa)
+	appendAttr("sucursal", sucursal)
+	appendAttr("username", Username(ctx))
+	if origin := RequestOrigin(ctx); origin != ":unknown" {
+		appendAttr("origin", origin)
+	}
+	appendAttr("request_id", RequestID(ctx))
+	if sc := tracing.SpanContextFromContext(ctx); sc.IsValid() {
+		appendAttr("trace_id", sc.TraceID.String())
+	}
+	logger := logging.ForTenant(empresa)
+	if len(attrs) == 0 {
+		return logger
+	}
+	return logger.With(attrs...)
+}
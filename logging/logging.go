@@ -0,0 +1,174 @@
+// Package logging configura el *slog.Logger del proceso (JSON o texto) y
+// permite subir el nivel de log por empresa en tiempo de ejecución.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+type Config struct {
+	// Format "json" o "text" (por defecto)
+	Format string
+	// Level "debug", "info" (por defecto), "warn" o "error"
+	Level string
+	// Output por defecto os.Stderr
+	Output io.Writer
+}
+
+// ParseLevel acepta los nombres de slog sin distinguir mayúsculas.
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("logging: invalid level %q", value)
+	}
+	return level, nil
+}
+
+// Setup reemplaza slog.Default por un logger con el formato y nivel indicados.
+func Setup(cfg Config) error {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+	output := cfg.Output
+	if output == nil {
+		output = os.Stderr
+	}
+	// el handler interno deja pasar todo; el filtro por nivel lo hace Handler
+	options := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var inner slog.Handler
+	switch strings.ToLower(strings.TrimSpace(cfg.Format)) {
+	case FormatJSON:
+		inner = slog.NewJSONHandler(output, options)
+	case FormatText, "":
+		inner = slog.NewTextHandler(output, options)
+	default:
+		return fmt.Errorf("logging: invalid format %q", cfg.Format)
+	}
+	levels.base.Set(level)
+	slog.SetDefault(slog.New(&Handler{inner: inner}))
+	return nil
+}
+
+// tenantLevels guarda el nivel base y los niveles temporales por empresa.
+type tenantLevels struct {
+	base slog.LevelVar
+
+	mu      sync.RWMutex
+	tenants map[string]TenantLevel
+}
+
+type TenantLevel struct {
+	Empresa string     `json:"empresa"`
+	Level   slog.Level `json:"level"`
+	// Expires en cero indica que no vence
+	Expires time.Time `json:"expires,omitzero"`
+}
+
+var levels = &tenantLevels{tenants: map[string]TenantLevel{}}
+
+// SetTenantLevel cambia el nivel de log de una empresa; con ttl > 0 vuelve
+// al nivel base pasado ese tiempo.
+func SetTenantLevel(empresa string, level slog.Level, ttl time.Duration) TenantLevel {
+	entry := TenantLevel{Empresa: empresa, Level: level}
+	if ttl > 0 {
+		entry.Expires = time.Now().Add(ttl)
+	}
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	levels.tenants[empresa] = entry
+	return entry
+}
+
+func ClearTenantLevel(empresa string) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	delete(levels.tenants, empresa)
+}
+
+// TenantLevels devuelve los niveles vigentes por empresa.
+func TenantLevels() []TenantLevel {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	now := time.Now()
+	result := make([]TenantLevel, 0, len(levels.tenants))
+	for empresa, entry := range levels.tenants {
+		if !entry.Expires.IsZero() && now.After(entry.Expires) {
+			delete(levels.tenants, empresa)
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func BaseLevel() slog.Level { return levels.base.Level() }
+
+func (l *tenantLevels) level(empresa string) slog.Level {
+	base := l.base.Level()
+	if empresa == "" {
+		return base
+	}
+	l.mu.RLock()
+	entry, found := l.tenants[empresa]
+	l.mu.RUnlock()
+	if !found || (!entry.Expires.IsZero() && time.Now().After(entry.Expires)) {
+		return base
+	}
+	return entry.Level
+}
+
+// Enabled indica si los logs de la empresa se emiten en ese nivel.
+func Enabled(empresa string, level slog.Level) bool {
+	return level >= levels.level(empresa)
+}
+
+// Handler filtra por el nivel base o por el de la empresa asociada.
+type Handler struct {
+	inner   slog.Handler
+	empresa string
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return Enabled(h.empresa, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	return h.inner.Handle(ctx, record)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{inner: h.inner.WithAttrs(attrs), empresa: h.empresa}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{inner: h.inner.WithGroup(name), empresa: h.empresa}
+}
+
+// ForTenant devuelve un logger que respeta el nivel configurado para la empresa.
+// Si Setup no se llamó, usa el handler de slog.Default.
+func ForTenant(empresa string) *slog.Logger {
+	handler := slog.Default().Handler()
+	if h, ok := handler.(*Handler); ok {
+		return slog.New(&Handler{inner: h.inner, empresa: empresa})
+	}
+	return slog.New(&Handler{inner: handler, empresa: empresa})
+}
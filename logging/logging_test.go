@@ -0,0 +1,84 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/logging"
+)
+
+func TestTenantLevel(t *testing.T) {
+	if err := logging.Setup(logging.Config{Level: "info", Output: &bytes.Buffer{}}); err != nil {
+		t.Fatal(err)
+	}
+	defer logging.ClearTenantLevel("empresa_a")
+
+	if logging.Enabled("empresa_a", slog.LevelDebug) {
+		t.Fatal("debug should be disabled at base level info")
+	}
+	logging.SetTenantLevel("empresa_a", slog.LevelDebug, time.Minute)
+	if !logging.Enabled("empresa_a", slog.LevelDebug) {
+		t.Fatal("debug should be enabled for empresa_a")
+	}
+	if logging.Enabled("empresa_b", slog.LevelDebug) {
+		t.Fatal("debug should stay disabled for other tenants")
+	}
+
+	logging.SetTenantLevel("empresa_a", slog.LevelDebug, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if logging.Enabled("empresa_a", slog.LevelDebug) {
+		t.Fatal("expired tenant level should fall back to base")
+	}
+	if levels := logging.TenantLevels(); len(levels) != 0 {
+		t.Fatalf("expected expired levels to be removed, got %v", levels)
+	}
+}
+
+func TestLoggerAddsIdentityFields(t *testing.T) {
+	var out bytes.Buffer
+	if err := logging.Setup(logging.Config{Format: logging.FormatJSON, Output: &out}); err != nil {
+		t.Fatal(err)
+	}
+	defer logging.ClearTenantLevel("empresa_a")
+
+	ctx := identitysdk.CtxWithDomain(context.Background(), "empresa_a")
+	ctx = identitysdk.CtxWithUsername(ctx, "jperez")
+	ctx = identitysdk.CtxWithRequestID(ctx, "req-1")
+
+	identitysdk.Logger(ctx).Debug("hidden")
+	if out.Len() != 0 {
+		t.Fatalf("debug should be filtered, got %s", out.String())
+	}
+
+	logging.SetTenantLevel("empresa_a", slog.LevelDebug, 0)
+	identitysdk.Logger(ctx).Debug("visible")
+
+	var record map[string]any
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("invalid json output %q: %v", out.String(), err)
+	}
+	for key, expected := range map[string]string{
+		"msg":        "visible",
+		"empresa":    "empresa_a",
+		"username":   "jperez",
+		"request_id": "req-1",
+	} {
+		if record[key] != expected {
+			t.Fatalf("expected %s=%q, got %v", key, expected, record[key])
+		}
+	}
+}
+
+func TestSetupRejectsInvalidConfig(t *testing.T) {
+	if err := logging.Setup(logging.Config{Level: "verbose"}); err == nil {
+		t.Fatal("expected error for invalid level")
+	}
+	if err := logging.Setup(logging.Config{Format: "xml"}); err == nil {
+		t.Fatal("expected error for invalid format")
+	}
+}
@@ -0,0 +1,222 @@
+// Package metrics registra contadores, gauges e histogramas y los expone en
+// el formato de texto de Prometheus.
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeGauge     metricType = "gauge"
+	typeHistogram metricType = "histogram"
+)
+
+// DefBuckets son los límites en segundos usados para latencias.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Registry agrupa las familias de métricas de un proceso.
+type Registry struct {
+	mu         sync.Mutex
+	families   map[string]*family
+	collectors []func()
+}
+
+func NewRegistry() *Registry {
+	return &Registry{families: map[string]*family{}}
+}
+
+// Default es el registro que exponen /metrics y los helpers del paquete.
+var Default = NewRegistry()
+
+type family struct {
+	name    string
+	help    string
+	kind    metricType
+	labels  []string
+	buckets []float64
+
+	mu     sync.RWMutex
+	series map[string]*series
+}
+
+type series struct {
+	values []string
+	// value guarda los bits de un float64 para counters y gauges
+	value   atomic.Uint64
+	mu      sync.Mutex
+	counts  []uint64
+	sum     float64
+	samples uint64
+}
+
+func (s *series) add(delta float64) {
+	for {
+		current := s.value.Load()
+		next := math.Float64bits(math.Float64frombits(current) + delta)
+		if s.value.CompareAndSwap(current, next) {
+			return
+		}
+	}
+}
+
+func (s *series) load() float64 { return math.Float64frombits(s.value.Load()) }
+
+// register devuelve la familia existente si ya se registró con el mismo nombre,
+// así varias instancias (p. ej. ejecutores) comparten la métrica.
+func (r *Registry) register(name, help string, kind metricType, buckets []float64, labels []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, found := r.families[name]; found {
+		if f.kind != kind || strings.Join(f.labels, ",") != strings.Join(labels, ",") {
+			panic("metrics: " + name + " already registered with a different type or labels")
+		}
+		return f
+	}
+	f := &family{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+		series:  map[string]*series{},
+	}
+	r.families[name] = f
+	return f
+}
+
+// OnCollect ejecuta fn antes de cada exposición, para actualizar gauges calculados.
+func (r *Registry) OnCollect(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, fn)
+}
+
+func (r *Registry) snapshot() ([]*family, []func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+	return families, append([]func(){}, r.collectors...)
+}
+
+func (f *family) with(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic("metrics: " + f.name + " expects labels " + strings.Join(f.labels, ","))
+	}
+	key := strings.Join(values, "\xff")
+	f.mu.RLock()
+	s, found := f.series[key]
+	f.mu.RUnlock()
+	if found {
+		return s
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s, found = f.series[key]; found {
+		return s
+	}
+	s = &series{values: append([]string(nil), values...)}
+	if f.kind == typeHistogram {
+		s.counts = make([]uint64, len(f.buckets))
+	}
+	f.series[key] = s
+	return s
+}
+
+type CounterVec struct{ f *family }
+
+type Counter struct{ s *series }
+
+func (r *Registry) Counter(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{r.register(name, help, typeCounter, nil, labels)}
+}
+
+func NewCounter(name, help string, labels ...string) *CounterVec {
+	return Default.Counter(name, help, labels...)
+}
+
+func (v *CounterVec) With(values ...string) Counter { return Counter{v.f.with(values)} }
+
+func (c Counter) Inc()           { c.s.add(1) }
+func (c Counter) Value() float64 { return c.s.load() }
+
+// Add suma delta; los valores negativos se ignoran porque un counter no decrece.
+func (c Counter) Add(delta float64) {
+	if delta > 0 {
+		c.s.add(delta)
+	}
+}
+
+type GaugeVec struct{ f *family }
+
+type Gauge struct{ s *series }
+
+func (r *Registry) Gauge(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{r.register(name, help, typeGauge, nil, labels)}
+}
+
+func NewGauge(name, help string, labels ...string) *GaugeVec {
+	return Default.Gauge(name, help, labels...)
+}
+
+func (v *GaugeVec) With(values ...string) Gauge { return Gauge{v.f.with(values)} }
+
+func (g Gauge) Set(value float64) { g.s.value.Store(math.Float64bits(value)) }
+func (g Gauge) Add(delta float64) { g.s.add(delta) }
+func (g Gauge) Inc()              { g.s.add(1) }
+func (g Gauge) Dec()              { g.s.add(-1) }
+func (g Gauge) Value() float64    { return g.s.load() }
+
+type HistogramVec struct{ f *family }
+
+type Histogram struct {
+	s       *series
+	buckets []float64
+}
+
+// Histogram registra un histograma; sin buckets usa DefBuckets.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &HistogramVec{r.register(name, help, typeHistogram, buckets, labels)}
+}
+
+func NewHistogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return Default.Histogram(name, help, buckets, labels...)
+}
+
+func (v *HistogramVec) With(values ...string) Histogram {
+	return Histogram{s: v.f.with(values), buckets: v.f.buckets}
+}
+
+func (h Histogram) Observe(value float64) {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	for i, bound := range h.buckets {
+		if value <= bound {
+			h.s.counts[i]++
+		}
+	}
+	h.s.sum += value
+	h.s.samples++
+}
+
+// Count devuelve la cantidad de observaciones.
+func (h Histogram) Count() uint64 {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	return h.s.samples
+}
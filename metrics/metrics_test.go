@@ -0,0 +1,64 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sfperusacdev/identitysdk/metrics"
+)
+
+func TestWriteText(t *testing.T) {
+	registry := metrics.NewRegistry()
+	requests := registry.Counter("requests_total", "Peticiones\ncon salto.", "route", "status")
+	inflight := registry.Gauge("inflight", "En curso.")
+	latency := registry.Histogram("latency_seconds", "Latencia.", []float64{1, 0.1}, "route")
+	registry.Gauge("unused", "Sin series.")
+
+	requests.With("/a", "200").Inc()
+	requests.With("/a", "200").Add(2)
+	requests.With("/a", "200").Add(-5)
+	requests.With(`/b"x`, "500").Inc()
+	registry.OnCollect(func() { inflight.With().Set(3) })
+	latency.With("/a").Observe(0.05)
+	latency.With("/a").Observe(0.5)
+	latency.With("/a").Observe(3)
+
+	var out strings.Builder
+	if err := registry.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	expected := `# HELP inflight En curso.
+# TYPE inflight gauge
+inflight 3
+# HELP latency_seconds Latencia.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/a",le="0.1"} 1
+latency_seconds_bucket{route="/a",le="1"} 2
+latency_seconds_bucket{route="/a",le="+Inf"} 3
+latency_seconds_sum{route="/a"} 3.55
+latency_seconds_count{route="/a"} 3
+# HELP requests_total Peticiones\ncon salto.
+# TYPE requests_total counter
+requests_total{route="/a",status="200"} 3
+requests_total{route="/b\"x",status="500"} 1
+`
+	if out.String() != expected {
+		t.Fatalf("unexpected exposition:\n%s", out.String())
+	}
+}
+
+func TestRegisterReturnsExistingFamily(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.Counter("tasks_total", "Tareas.", "executor").With("a").Inc()
+	registry.Counter("tasks_total", "Tareas.", "executor").With("a").Inc()
+	if value := registry.Counter("tasks_total", "Tareas.", "executor").With("a").Value(); value != 2 {
+		t.Fatalf("expected shared series, got %v", value)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic when labels differ")
+		}
+	}()
+	registry.Counter("tasks_total", "Tareas.", "otro")
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType es el tipo de la exposición de texto de Prometheus.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WriteText escribe todas las métricas en el formato de texto de Prometheus.
+func (r *Registry) WriteText(w io.Writer) error {
+	families, collectors := r.snapshot()
+	for _, collect := range collectors {
+		collect()
+	}
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+func (f *family) write(w *bufio.Writer) {
+	f.mu.RLock()
+	all := make([]*series, 0, len(f.series))
+	for _, s := range f.series {
+		all = append(all, s)
+	}
+	f.mu.RUnlock()
+	if len(all) == 0 {
+		return
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return strings.Join(all[i].values, "\xff") < strings.Join(all[j].values, "\xff")
+	})
+
+	w.WriteString("# HELP " + f.name + " " + escapeHelp(f.help) + "\n")
+	w.WriteString("# TYPE " + f.name + " " + string(f.kind) + "\n")
+	for _, s := range all {
+		if f.kind != typeHistogram {
+			writeSample(w, f.name, f.labels, s.values, "", "", s.load())
+			continue
+		}
+		s.mu.Lock()
+		counts, sum, samples := append([]uint64(nil), s.counts...), s.sum, s.samples
+		s.mu.Unlock()
+		for i, bound := range f.buckets {
+			writeSample(w, f.name+"_bucket", f.labels, s.values, "le", formatFloat(bound), float64(counts[i]))
+		}
+		writeSample(w, f.name+"_bucket", f.labels, s.values, "le", "+Inf", float64(samples))
+		writeSample(w, f.name+"_sum", f.labels, s.values, "", "", sum)
+		writeSample(w, f.name+"_count", f.labels, s.values, "", "", float64(samples))
+	}
+}
+
+func writeSample(w *bufio.Writer, name string, labels, values []string, extraLabel, extraValue string, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 || extraLabel != "" {
+		w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(label + `="` + escapeLabel(values[i]) + `"`)
+		}
+		if extraLabel != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(extraLabel + `="` + extraValue + `"`)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(value string) string  { return helpEscaper.Replace(value) }
+func escapeLabel(value string) string { return labelEscaper.Replace(value) }
@@ -0,0 +1,45 @@
+package permissions
+
+import (
+	"sort"
+	"sync"
+)
+
+// Definition es un permiso publicado en identity por el servicio que lo usa.
+type Definition struct {
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+}
+
+var (
+	descriptionsMu sync.RWMutex
+	descriptions   = map[string]string{}
+)
+
+// Describe registra la descripción que se publica junto al permiso, por
+// ejemplo junto a la constante que lo declara:
+//
+//	var _ = permissions.Describe("planilla.aprobar", "Aprobar planillas")
+func Describe(id, description string) string {
+	descriptionsMu.Lock()
+	defer descriptionsMu.Unlock()
+	descriptions[id] = description
+	return id
+}
+
+// Definitions arma el catálogo ordenado de los permisos indicados con sus descripciones.
+func Definitions(ids []string) []Definition {
+	descriptionsMu.RLock()
+	defer descriptionsMu.RUnlock()
+	seen := map[string]bool{}
+	definitions := make([]Definition, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		definitions = append(definitions, Definition{ID: id, Description: descriptions[id]})
+	}
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].ID < definitions[j].ID })
+	return definitions
+}
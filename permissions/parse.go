@@ -0,0 +1,166 @@
+package permissions
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parse interpreta una expresión de permisos:
+//
+//	planilla.aprobar || admin
+//	g:reportes.ver && (any:planilla.ver || @sucursal:planilla.editar)
+//	!externo
+//
+// "g:" es global, "any:" cualquier sucursal y "@param:" la sucursal del
+// parámetro de ruta param. Un permiso sin prefijo se evalúa en ?sucursal.
+// La precedencia es ! > && > ||.
+func Parse(expr string) (Rule, error) {
+	p := &parser{tokens: tokenize(expr)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("permissions: empty expression")
+	}
+	rule, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("permissions: %q: %w", expr, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("permissions: %q: unexpected %q", expr, p.tokens[p.pos])
+	}
+	return rule, nil
+}
+
+// MustParse es como Parse pero entra en pánico; pensado para declarar rutas.
+func MustParse(expr string) Rule {
+	rule, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+func tokenize(expr string) []string {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		switch c := expr[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(expr[i:], "&&"), strings.HasPrefix(expr[i:], "||"):
+			tokens = append(tokens, expr[i:i+2])
+			i += 2
+		case c == '(' || c == ')' || c == '!' || c == '&' || c == '|':
+			tokens = append(tokens, string(c))
+			i++
+		default:
+			start := i
+			for i < len(expr) && !strings.ContainsRune(" \t\n\r()!&|", rune(expr[i])) {
+				i++
+			}
+			tokens = append(tokens, expr[start:i])
+		}
+	}
+	return tokens
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) parseOr() (Rule, error) {
+	rules, err := p.parseList("||", p.parseAnd)
+	if err != nil || len(rules) == 1 {
+		return first(rules), err
+	}
+	return Any(rules...), nil
+}
+
+func (p *parser) parseAnd() (Rule, error) {
+	rules, err := p.parseList("&&", p.parseUnary)
+	if err != nil || len(rules) == 1 {
+		return first(rules), err
+	}
+	return All(rules...), nil
+}
+
+func (p *parser) parseList(sep string, next func() (Rule, error)) ([]Rule, error) {
+	var rules []Rule
+	for {
+		rule, err := next()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+		if p.peek() != sep {
+			return rules, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *parser) parseUnary() (Rule, error) {
+	switch token := p.peek(); token {
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case "!":
+		p.pos++
+		rule, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(rule), nil
+	case "(":
+		p.pos++
+		rule, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return rule, nil
+	case ")", "&&", "||", "&", "|":
+		return nil, fmt.Errorf("unexpected %q", token)
+	default:
+		p.pos++
+		return parsePermission(token)
+	}
+}
+
+func parsePermission(token string) (Rule, error) {
+	if id, ok := strings.CutPrefix(token, "g:"); ok {
+		return nonEmpty(token, id, Global)
+	}
+	if id, ok := strings.CutPrefix(token, "any:"); ok {
+		return nonEmpty(token, id, AnyBranch)
+	}
+	if rest, ok := strings.CutPrefix(token, "@"); ok {
+		param, id, found := strings.Cut(rest, ":")
+		if !found || param == "" || id == "" {
+			return nil, fmt.Errorf("invalid branch permission %q, expected @param:permission", token)
+		}
+		return InBranchParam(param, id), nil
+	}
+	return nonEmpty(token, token, Perm)
+}
+
+func nonEmpty(token, id string, build func(string) Rule) (Rule, error) {
+	if id == "" {
+		return nil, fmt.Errorf("invalid permission %q", token)
+	}
+	return build(id), nil
+}
+
+func first(rules []Rule) Rule {
+	if len(rules) == 0 {
+		return nil
+	}
+	return rules[0]
+}
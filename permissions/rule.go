@@ -0,0 +1,277 @@
+package permissions
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/entities"
+	"github.com/user0608/goones/errs"
+)
+
+// Params resuelve parámetros de la petición (p. ej. c.Param en echo o un campo del mensaje en gRPC).
+type Params func(name string) string
+
+// Rule es una expresión de permisos evaluada contra entities.Session.Permissions.
+type Rule interface {
+	// missing devuelve la parte de la regla que no se cumple, o "" si se cumple.
+	missing(ev *evaluation) string
+	String() string
+}
+
+// adminPermission cumple cualquier permiso en la sucursal que evalúa la regla.
+const adminPermission = "admin"
+
+type evaluation struct {
+	session  entities.Session
+	sucursal string
+	params   Params
+	// literal desactiva el reemplazo por admin; lo usa Not para que admin no la incumpla
+	literal bool
+}
+
+// has busca el permiso en una sucursal que cumpla match.
+func (ev *evaluation) has(permission string, match func(branches []string) bool) bool {
+	for _, perm := range ev.session.Permissions {
+		if perm.ID == permission && match(perm.CompanyBrances) {
+			return true
+		}
+	}
+	return false
+}
+
+// adminIn indica si la sesión tiene admin en branch.
+func (ev *evaluation) adminIn(branch string) bool {
+	return !ev.literal && ev.has(adminPermission, inBranch(branch))
+}
+
+func inBranch(branch string) func([]string) bool {
+	return func(branches []string) bool {
+		for _, b := range branches {
+			if b == branch {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type permRule struct{ id string }
+
+// Perm requiere el permiso en la sucursal de la petición (?sucursal).
+func Perm(id string) Rule { return permRule{id: id} }
+
+func (r permRule) missing(ev *evaluation) string {
+	if ev.has(r.id, inBranch(ev.sucursal)) || ev.adminIn(ev.sucursal) {
+		return ""
+	}
+	return r.String()
+}
+
+func (r permRule) String() string { return r.id }
+
+type globalRule struct{ id string }
+
+// Global requiere el permiso sin importar la sucursal; equivale al prefijo "g:".
+// admin solo lo cumple en la sucursal de la petición (?sucursal).
+func Global(id string) Rule { return globalRule{id: id} }
+
+func (r globalRule) missing(ev *evaluation) string {
+	if ev.has(r.id, func([]string) bool { return true }) || ev.adminIn(ev.sucursal) {
+		return ""
+	}
+	return r.String()
+}
+
+func (r globalRule) String() string { return "g:" + r.id }
+
+type anyBranchRule struct{ id string }
+
+// AnyBranch requiere el permiso asignado en al menos una sucursal.
+func AnyBranch(id string) Rule { return anyBranchRule{id: id} }
+
+func (r anyBranchRule) missing(ev *evaluation) string {
+	if ev.has(r.id, func(branches []string) bool { return len(branches) > 0 }) || ev.adminIn(ev.sucursal) {
+		return ""
+	}
+	return r.String()
+}
+
+func (r anyBranchRule) String() string { return "any:" + r.id }
+
+type branchParamRule struct{ param, id string }
+
+// InBranchParam requiere el permiso en la sucursal indicada por el parámetro param
+// (p. ej. /planillas/:sucursal) en lugar de ?sucursal.
+func InBranchParam(param, id string) Rule { return branchParamRule{param: param, id: id} }
+
+func (r branchParamRule) missing(ev *evaluation) string {
+	var branch string
+	if ev.params != nil {
+		branch = identitysdk.RemovePrefix(ev.params(r.param))
+	}
+	if branch != "" && (ev.has(r.id, inBranch(branch)) || ev.adminIn(branch)) {
+		return ""
+	}
+	return r.String()
+}
+
+func (r branchParamRule) String() string { return "@" + r.param + ":" + r.id }
+
+type allRule []Rule
+
+// All requiere que se cumplan todas las reglas.
+func All(rules ...Rule) Rule { return allRule(rules) }
+
+func (r allRule) missing(ev *evaluation) string {
+	return strings.Join(r.failing(ev), " && ")
+}
+
+func (r allRule) failing(ev *evaluation) []string {
+	var parts []string
+	for _, rule := range r {
+		if m := rule.missing(ev); m != "" {
+			parts = append(parts, group(rule, m))
+		}
+	}
+	return parts
+}
+
+func (r allRule) String() string { return strings.Join(ruleStrings(r), " && ") }
+
+type anyRule []Rule
+
+// Any requiere que se cumpla al menos una de las reglas.
+func Any(rules ...Rule) Rule { return anyRule(rules) }
+
+func (r anyRule) missing(ev *evaluation) string {
+	return strings.Join(r.failing(ev), " || ")
+}
+
+// failing devuelve lo que falta en cada alternativa, o nil si alguna se cumple.
+func (r anyRule) failing(ev *evaluation) []string {
+	parts := make([]string, 0, len(r))
+	for _, rule := range r {
+		m := rule.missing(ev)
+		if m == "" {
+			return nil
+		}
+		parts = append(parts, group(rule, m))
+	}
+	return parts
+}
+
+func (r anyRule) String() string { return strings.Join(ruleStrings(r), " || ") }
+
+type notRule struct{ rule Rule }
+
+// Not se cumple cuando la regla no se cumple.
+func Not(rule Rule) Rule { return notRule{rule: rule} }
+
+func (r notRule) missing(ev *evaluation) string {
+	literal := *ev
+	literal.literal = true
+	if r.rule.missing(&literal) != "" {
+		return ""
+	}
+	return r.String()
+}
+
+func (r notRule) String() string { return "!" + group(r.rule, r.rule.String()) }
+
+func ruleStrings(rules []Rule) []string {
+	out := make([]string, len(rules))
+	for i, rule := range rules {
+		out[i] = group(rule, rule.String())
+	}
+	return out
+}
+
+// group agrega paréntesis cuando s es una regla compuesta anidada.
+func group(rule Rule, s string) string {
+	switch rule.(type) {
+	case allRule, anyRule:
+		if strings.Contains(s, " ") {
+			return "(" + s + ")"
+		}
+	}
+	return s
+}
+
+// FromList convierte la lista de CheckPermissions (todas requeridas, "g:" global) en una regla.
+func FromList(permissions []string) Rule {
+	rules := make([]Rule, 0, len(permissions))
+	for _, permission := range permissions {
+		if id, ok := strings.CutPrefix(permission, "g:"); ok {
+			rules = append(rules, Global(id))
+			continue
+		}
+		rules = append(rules, Perm(permission))
+	}
+	return All(rules...)
+}
+
+// Missing evalúa la regla y devuelve lo que falta para cumplirla, o "" si se cumple.
+// "admin" cumple cada permiso en la sucursal de la petición (?sucursal) o, con
+// InBranchParam, en la sucursal del parámetro; admin en otra sucursal no cumple
+// Global ni AnyBranch.
+func Missing(ctx context.Context, rule Rule, params Params) string {
+	if rule == nil {
+		return ""
+	}
+	_, sucursal := identitysdk.Empresa_Sucursal(ctx)
+	session, _ := identitysdk.ReadSession(ctx)
+	ev := &evaluation{session: session, sucursal: sucursal, params: params}
+	// en el nivel superior no se agrupa: las requeridas se separan por coma
+	if all, ok := rule.(allRule); ok {
+		return strings.Join(all.failing(ev), ", ")
+	}
+	return rule.missing(ev)
+}
+
+// Check devuelve un error Forbidden que indica exactamente qué parte de la regla no se cumple.
+func Check(ctx context.Context, rule Rule, params Params) error {
+	missing := Missing(ctx, rule, params)
+	if missing == "" {
+		return nil
+	}
+	return errs.ForbiddenDirect("No tienes permiso para realizar esta acción. Permisos requeridos: " + missing)
+}
+
+// Referenced devuelve los permisos usados en la regla, sin prefijos ni repetidos.
+func Referenced(rule Rule) []string {
+	seen := map[string]bool{}
+	var ids []string
+	var walk func(Rule)
+	walk = func(rule Rule) {
+		var id string
+		switch r := rule.(type) {
+		case permRule:
+			id = r.id
+		case globalRule:
+			id = r.id
+		case anyBranchRule:
+			id = r.id
+		case branchParamRule:
+			id = r.id
+		case notRule:
+			walk(r.rule)
+		case allRule:
+			for _, child := range r {
+				walk(child)
+			}
+		case anyRule:
+			for _, child := range r {
+				walk(child)
+			}
+		}
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if rule != nil {
+		walk(rule)
+	}
+	return ids
+}
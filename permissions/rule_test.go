@@ -0,0 +1,109 @@
+package permissions_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/entities"
+	"github.com/sfperusacdev/identitysdk/permissions"
+)
+
+func sessionCtx(sucursal string, perms ...entities.Permission) context.Context {
+	ctx := identitysdk.CtxWithSucursal(context.Background(), sucursal)
+	return identitysdk.CtxWithSession(ctx, entities.Session{Username: "usuario", Permissions: perms})
+}
+
+func params(values map[string]string) permissions.Params {
+	return func(name string) string { return values[name] }
+}
+
+func TestParseAndEvaluate(t *testing.T) {
+	ctx := sessionCtx("s1",
+		entities.Permission{ID: "planilla.ver", CompanyBrances: []string{"s2"}},
+		entities.Permission{ID: "planilla.editar", CompanyBrances: []string{"s3"}},
+		entities.Permission{ID: "reportes.ver"},
+	)
+	pathParams := params(map[string]string{"sucursal": "empresa.s3"})
+
+	cases := []struct {
+		expr    string
+		missing string
+	}{
+		{expr: "planilla.aprobar || admin", missing: "planilla.aprobar || admin"},
+		{expr: "any:planilla.ver", missing: ""},
+		{expr: "planilla.ver", missing: "planilla.ver"},
+		{expr: "g:reportes.ver && any:reportes.ver", missing: "any:reportes.ver"},
+		{expr: "@sucursal:planilla.editar", missing: ""},
+		{expr: "@otra:planilla.editar", missing: "@otra:planilla.editar"},
+		{expr: "!planilla.ver && !any:planilla.ver", missing: "!any:planilla.ver"},
+		{expr: "a || (b && any:planilla.ver) || c && g:reportes.ver", missing: "a || b || c"},
+		{expr: "(x && y) || z", missing: "(x && y) || z"},
+	}
+	for _, tc := range cases {
+		rule, err := permissions.Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.expr, err)
+		}
+		if got := permissions.Missing(ctx, rule, pathParams); got != tc.missing {
+			t.Fatalf("%q: expected missing %q, got %q", tc.expr, tc.missing, got)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{"", "a &&", "(a || b", "a b", "@sucursal", "g:", "a & b"} {
+		if _, err := permissions.Parse(expr); err == nil {
+			t.Fatalf("%q: expected error", expr)
+		}
+	}
+}
+
+func TestAdminBypassAndListCompatibility(t *testing.T) {
+	admin := sessionCtx("s1", entities.Permission{ID: "admin", CompanyBrances: []string{"s1"}})
+	if err := permissions.Check(admin, permissions.Perm("planilla.aprobar"), nil); err != nil {
+		t.Fatalf("admin should bypass rules: %v", err)
+	}
+	branchRule := permissions.InBranchParam("sucursal", "planilla.aprobar")
+	if err := permissions.Check(admin, branchRule, params(map[string]string{"sucursal": "empresa.s3"})); err == nil {
+		t.Fatal("admin in ?sucursal must not grant a rule resolved to another branch")
+	}
+	adminS3 := sessionCtx("s1", entities.Permission{ID: "admin", CompanyBrances: []string{"s3"}})
+	if err := permissions.Check(adminS3, branchRule, params(map[string]string{"sucursal": "empresa.s3"})); err != nil {
+		t.Fatalf("admin in the resolved branch should pass: %v", err)
+	}
+	if err := permissions.Check(admin, permissions.Global("auditoria.ver"), nil); err != nil {
+		t.Fatalf("admin in ?sucursal should pass global rules: %v", err)
+	}
+	for _, ctx := range []context.Context{adminS3, sessionCtx("", entities.Permission{ID: "admin", CompanyBrances: []string{"s3"}})} {
+		for _, rule := range []permissions.Rule{permissions.Global("auditoria.ver"), permissions.AnyBranch("auditoria.ver")} {
+			if err := permissions.Check(ctx, rule, nil); err == nil {
+				t.Fatalf("admin in another branch must not grant %s", rule)
+			}
+		}
+	}
+	if err := permissions.Check(admin, permissions.MustParse("!planilla.bloqueada"), nil); err != nil {
+		t.Fatalf("admin should not fail negated rules: %v", err)
+	}
+
+	ctx := sessionCtx("s1", entities.Permission{ID: "a", CompanyBrances: []string{"s1"}})
+	err := permissions.Check(ctx, permissions.FromList([]string{"a", "b", "g:c"}), nil)
+	if err == nil || !strings.HasSuffix(err.Error(), "Permisos requeridos: b, g:c") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReferencedDefinitions(t *testing.T) {
+	rule := permissions.MustParse("!g:planilla.ver && (any:planilla.aprobar || @sucursal:planilla.ver)")
+	ids := permissions.Referenced(rule)
+	if strings.Join(ids, ",") != "planilla.ver,planilla.aprobar" {
+		t.Fatalf("unexpected referenced permissions: %v", ids)
+	}
+
+	permissions.Describe("planilla.aprobar", "Aprobar planillas")
+	definitions := permissions.Definitions(append(ids, "planilla.aprobar"))
+	if len(definitions) != 2 || definitions[0].ID != "planilla.aprobar" || definitions[0].Description != "Aprobar planillas" {
+		t.Fatalf("unexpected definitions: %+v", definitions)
+	}
+}
@@ -81,6 +81,12 @@ func (c *PgConnection) Conn(ctx context.Context) *gorm.DB {
 	return c.conn.WithContext(ctx)
 }
 
+// InTx indica si ctx lleva la transacción abierta por WithTx.
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(contextConnectionKey).(*gorm.DB)
+	return ok
+}
+
 func (c *PgConnection) WithTx(ctx context.Context, txFunc func(ctx context.Context) error) error {
 	if txFunc == nil {
 		return nil
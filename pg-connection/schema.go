@@ -0,0 +1,34 @@
+package PgConnection
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/user0608/goones/errs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Schema crea una sola vez las tablas internas de un store. El script corre
+// en una conexión propia y no en la transacción del llamador, para que un
+// rollback no deshaga la tabla; si falla se vuelve a intentar en la siguiente
+// llamada. El valor cero está listo para usarse.
+type Schema struct {
+	ready atomic.Bool
+}
+
+// Ensure ejecuta script si aún no se ejecutó; sin base de datos no hace nada.
+func (s *Schema) Ensure(manager StorageManager, script string) error {
+	if s.ready.Load() {
+		return nil
+	}
+	tx := manager.Conn(context.Background())
+	if tx == nil {
+		return nil // skip
+	}
+	if err := tx.Session(&gorm.Session{Logger: logger.Discard}).Exec(script).Error; err != nil {
+		return errs.Pgf(err)
+	}
+	s.ready.Store(true)
+	return nil
+}
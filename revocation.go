@@ -0,0 +1,71 @@
+package identitysdk
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	apikeycache "github.com/sfperusacdev/identitysdk/internal/apikey_cache"
+	"github.com/sfperusacdev/identitysdk/internal/revocation"
+	sessioncache "github.com/sfperusacdev/identitysdk/internal/session_cache"
+)
+
+// RevokedToken identifica un token revocado por su claim id. ExpiresAt (unix)
+// indica hasta cuándo se mantiene en la lista; si es 0 se usa una retención por defecto.
+type RevokedToken struct {
+	ID        string `json:"id"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// RevokeTokens descarta de las cachés las sesiones y api keys con esos ids
+// y las rechaza hasta que expiren.
+func RevokeTokens(ctx context.Context, tokens ...RevokedToken) {
+	for _, token := range tokens {
+		id := strings.TrimSpace(token.ID)
+		if id == "" {
+			continue
+		}
+		var until time.Time
+		if token.ExpiresAt > 0 {
+			until = time.Unix(token.ExpiresAt, 0)
+		}
+		revocation.Default.RevokeToken(ctx, id, until)
+		sessioncache.DefaultCache.Delete(ctx, id)
+		apikeycache.DefaultCache.Delete(ctx, id)
+	}
+	if len(tokens) > 0 {
+		slog.Info("tokens revoked", "count", len(tokens))
+	}
+}
+
+// RevokeUsers invalida las sesiones y api keys cacheadas de los usuarios; la
+// siguiente petición de cada uno se vuelve a validar contra identity. La
+// revocación se conserva durante la vida máxima de los tokens (SetMaxTokenLifetime).
+func RevokeUsers(ctx context.Context, usernames ...string) {
+	now := time.Now()
+	for _, username := range usernames {
+		if username = strings.TrimSpace(username); username != "" {
+			revocation.Default.RevokeUser(ctx, username, now)
+		}
+	}
+	if len(usernames) > 0 {
+		slog.Info("user sessions revoked", "count", len(usernames))
+	}
+}
+
+// SetMaxTokenLifetime define la vida máxima de los tokens que emite identity;
+// por defecto 24 horas. Las revocaciones por usuario se conservan ese tiempo.
+func SetMaxTokenLifetime(d time.Duration) {
+	revocation.Default.SetUserRetention(d)
+}
+
+// isTokenRevoked es la única consulta de revocación del token por validación;
+// las cachés de sesiones y api keys confían en ella.
+func isTokenRevoked(ctx context.Context, token string) bool {
+	id, err := sessioncache.TokenID(token)
+	if err != nil {
+		return false
+	}
+	return revocation.Default.IsTokenRevoked(ctx, id)
+}
@@ -0,0 +1,104 @@
+package identitysdk_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/entities"
+)
+
+func unsignedToken(t *testing.T, id string) string {
+	t.Helper()
+	payload, err := json.Marshal(map[string]any{"id": id, "exp": time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "e30." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+func TestRevocationEvictsCachedSessions(t *testing.T) {
+	var checks atomic.Int32
+	identity := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"type": "success",
+			"data": entities.JwtData{Jwt: entities.Jwt{Empresa: "sfperu", Username: "revocado"}},
+		})
+	}))
+	defer identity.Close()
+
+	previous := identitysdk.GetIdentityServer()
+	identitysdk.SetIdentityServer(identity.URL)
+	t.Cleanup(func() { identitysdk.SetIdentityServer(previous) })
+
+	ctx := context.Background()
+	byUser := unsignedToken(t, "revocation-user")
+	byID := unsignedToken(t, "revocation-token")
+
+	for _, token := range []string{byUser, byID, byUser, byID} {
+		if _, err := identitysdk.ValidateTokenWithCache(ctx, token); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if checks.Load() != 2 {
+		t.Fatalf("expected 2 identity calls, got %d", checks.Load())
+	}
+
+	identitysdk.RevokeUsers(ctx, "revocado")
+	time.Sleep(time.Millisecond)
+	if _, err := identitysdk.ValidateTokenWithCache(ctx, byUser); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checks.Load() != 3 {
+		t.Fatalf("expected user revocation to force revalidation, got %d calls", checks.Load())
+	}
+
+	identitysdk.RevokeTokens(ctx, identitysdk.RevokedToken{ID: "revocation-token"})
+	if _, err := identitysdk.ValidateTokenWithCache(ctx, byID); err == nil {
+		t.Fatal("expected revoked token to be rejected")
+	}
+}
+
+func TestRevokeUsersEvictsCachedApiKeys(t *testing.T) {
+	var checks atomic.Int32
+	identity := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"type": "success",
+			"data": entities.ApikeyData{Apikey: entities.Apikey{Empresa: "sfperu"}},
+		})
+	}))
+	defer identity.Close()
+
+	previous := identitysdk.GetIdentityServer()
+	identitysdk.SetIdentityServer(identity.URL)
+	t.Cleanup(func() { identitysdk.SetIdentityServer(previous) })
+
+	payload, _ := json.Marshal(map[string]any{"id": "revocation-apikey", "username": "integrador"})
+	apikey := "e30." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+	ctx := context.Background()
+	for range 2 {
+		if _, err := identitysdk.ValidateApiKeyWithCache(ctx, apikey); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if checks.Load() != 1 {
+		t.Fatalf("expected 1 identity call, got %d", checks.Load())
+	}
+
+	identitysdk.RevokeUsers(ctx, "integrador")
+	time.Sleep(time.Millisecond)
+	if _, err := identitysdk.ValidateApiKeyWithCache(ctx, apikey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checks.Load() != 2 {
+		t.Fatalf("expected user revocation to force api key revalidation, got %d calls", checks.Load())
+	}
+}
@@ -2,6 +2,7 @@ package sark_services
 
 import (
 	"github.com/sfperusacdev/identitysdk/sark_services/asistencia"
+	"github.com/sfperusacdev/identitysdk/sark_services/contratos"
 	identityservice "github.com/sfperusacdev/identitysdk/sark_services/identity"
 	"github.com/sfperusacdev/identitysdk/sark_services/storage"
 	"github.com/sfperusacdev/identitysdk/sark_services/variables"
@@ -12,6 +13,7 @@ type SarkBridgeService struct {
 	Variables  *variables.VariablesService
 	Storage    *storage.StorageService
 	Asistencia *asistencia.AsistenciaService
+	Contratos  *contratos.ContratosService
 }
 
 func NewSarkBridgeService(
@@ -19,11 +21,13 @@ func NewSarkBridgeService(
 	Variables *variables.VariablesService,
 	Storage *storage.StorageService,
 	Asistencia *asistencia.AsistenciaService,
+	Contratos *contratos.ContratosService,
 ) *SarkBridgeService {
 	return &SarkBridgeService{
 		Identity:   Identity,
 		Variables:  Variables,
 		Storage:    Storage,
 		Asistencia: Asistencia,
+		Contratos:  Contratos,
 	}
 }
@@ -1,8 +1,15 @@
 package contratos
 
+import contratospb "github.com/sfperusacdev/identitysdk/grpc/gen/contratos"
+
 type ContratosService struct {
+	trabajadoresGrpc contratospb.TrabajadoresGRPCServiceClient
 }
 
-func NewContratosService() *ContratosService {
-	return &ContratosService{}
+func NewContratosService(
+	trabajadoresGrpc contratospb.TrabajadoresGRPCServiceClient,
+) *ContratosService {
+	return &ContratosService{
+		trabajadoresGrpc: trabajadoresGrpc,
+	}
 }
@@ -0,0 +1,85 @@
+package contratos
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+	contratospb "github.com/sfperusacdev/identitysdk/grpc/gen/contratos"
+	"google.golang.org/grpc"
+)
+
+type TrabajadoresFilter struct {
+	SoloActivos bool
+	// PlanillaID vacío para todas las planillas
+	PlanillaID string
+	// UpdatedSince en cero devuelve todos los trabajadores
+	UpdatedSince time.Time
+}
+
+func (f TrabajadoresFilter) request(ctx context.Context) *contratospb.StreamTrabajadoresRequest {
+	request := &contratospb.StreamTrabajadoresRequest{
+		CompanyCode: identitysdk.Empresa(ctx),
+		SoloActivos: f.SoloActivos,
+		PlanillaId:  f.PlanillaID,
+	}
+	if !f.UpdatedSince.IsZero() {
+		request.UpdatedSince = f.UpdatedSince.Format(time.RFC3339)
+	}
+	return request
+}
+
+// Trabajadores recorre el plantel de la empresa del contexto sin cargarlo
+// completo en memoria; si se corta el recorrido se cancela el stream.
+//
+//	for trabajador, err := range s.Trabajadores(ctx, contratos.TrabajadoresFilter{SoloActivos: true}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (s *ContratosService) Trabajadores(ctx context.Context, filter TrabajadoresFilter) iter.Seq2[*contratospb.Trabajadores_Item, error] {
+	return func(yield func(*contratospb.Trabajadores_Item, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stream, err := s.trabajadoresGrpc.StreamTrabajadores(ctx, filter.request(ctx))
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		receive(stream, yield)
+	}
+}
+
+// FastResumenTrabajadores igual que Trabajadores pero solo con los datos de identificación.
+func (s *ContratosService) FastResumenTrabajadores(ctx context.Context, filter TrabajadoresFilter) iter.Seq2[*contratospb.FastResumenTrabajadores_Trabajador, error] {
+	return func(yield func(*contratospb.FastResumenTrabajadores_Trabajador, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stream, err := s.trabajadoresGrpc.StreamFastResumenTrabajadores(ctx, filter.request(ctx))
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		receive(stream, yield)
+	}
+}
+
+func receive[T any](stream grpc.ServerStreamingClient[T], yield func(*T, error) bool) {
+	for {
+		item, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if !yield(item, nil) {
+			return
+		}
+	}
+}
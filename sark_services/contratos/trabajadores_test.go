@@ -0,0 +1,133 @@
+package contratos_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	contratospb "github.com/sfperusacdev/identitysdk/grpc/gen/contratos"
+	"github.com/sfperusacdev/identitysdk/sark_services/contratos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type trabajadoresServer struct {
+	contratospb.UnimplementedTrabajadoresGRPCServiceServer
+	total    int
+	failAt   int
+	requests chan *contratospb.StreamTrabajadoresRequest
+	canceled chan struct{}
+}
+
+func (s *trabajadoresServer) StreamTrabajadores(req *contratospb.StreamTrabajadoresRequest, stream grpc.ServerStreamingServer[contratospb.Trabajadores_Item]) error {
+	s.requests <- req
+	for i := range s.total {
+		if s.failAt > 0 && i == s.failAt {
+			return status.Error(codes.Internal, "boom")
+		}
+		if err := stream.Send(&contratospb.Trabajadores_Item{Codigo: fmt.Sprintf("T%03d", i)}); err != nil {
+			close(s.canceled)
+			return err
+		}
+	}
+	return nil
+}
+
+func newService(t *testing.T, server *trabajadoresServer) *contratos.ContratosService {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	contratospb.RegisterTrabajadoresGRPCServiceServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return contratos.NewContratosService(contratospb.NewTrabajadoresGRPCServiceClient(conn))
+}
+
+func newServer(total int) *trabajadoresServer {
+	return &trabajadoresServer{
+		total:    total,
+		requests: make(chan *contratospb.StreamTrabajadoresRequest, 1),
+		canceled: make(chan struct{}),
+	}
+}
+
+func TestTrabajadoresStreamsAllItemsWithFilter(t *testing.T) {
+	server := newServer(500)
+	service := newService(t, server)
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	count := 0
+	for item, err := range service.Trabajadores(context.Background(), contratos.TrabajadoresFilter{
+		SoloActivos:  true,
+		PlanillaID:   "EMP",
+		UpdatedSince: since,
+	}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("T%03d", count); item.GetCodigo() != want {
+			t.Fatalf("expected %s, got %s", want, item.GetCodigo())
+		}
+		count++
+	}
+	if count != 500 {
+		t.Fatalf("expected 500 items, got %d", count)
+	}
+	req := <-server.requests
+	if !req.GetSoloActivos() || req.GetPlanillaId() != "EMP" || req.GetUpdatedSince() != "2026-01-02T03:04:05Z" {
+		t.Fatalf("unexpected request %v", req)
+	}
+}
+
+func TestTrabajadoresBreakCancelsStream(t *testing.T) {
+	// más mensajes de los que caben en la ventana de flujo para que el servidor quede bloqueado
+	server := newServer(1_000_000)
+	service := newService(t, server)
+
+	for item, err := range service.Trabajadores(context.Background(), contratos.TrabajadoresFilter{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.GetCodigo() == "T002" {
+			break
+		}
+	}
+	select {
+	case <-server.canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected server stream to be canceled")
+	}
+}
+
+func TestTrabajadoresYieldsStreamError(t *testing.T) {
+	server := newServer(10)
+	server.failAt = 3
+	service := newService(t, server)
+
+	count := 0
+	var lastErr error
+	for _, err := range service.Trabajadores(context.Background(), contratos.TrabajadoresFilter{}) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		count++
+	}
+	if count != 3 || status.Code(lastErr) != codes.Internal {
+		t.Fatalf("expected 3 items and Internal error, got %d (%v)", count, lastErr)
+	}
+}
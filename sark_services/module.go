@@ -2,6 +2,7 @@ package sark_services
 
 import (
 	"github.com/sfperusacdev/identitysdk/sark_services/asistencia"
+	"github.com/sfperusacdev/identitysdk/sark_services/contratos"
 	bridgeidentity "github.com/sfperusacdev/identitysdk/sark_services/identity"
 	"github.com/sfperusacdev/identitysdk/sark_services/storage"
 	"github.com/sfperusacdev/identitysdk/sark_services/variables"
@@ -20,6 +21,7 @@ var Module = fx.Module("identitysdk/sark_services",
 		variables.NewVariablesService,
 		storage.NewStorageService,
 		asistencia.NewAsistenciaService,
+		contratos.NewContratosService,
 		NewSarkBridgeService,
 	),
 )
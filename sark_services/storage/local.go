@@ -14,11 +14,17 @@ type LocalFileStore struct {
 }
 
 var _ FileStorer = (*LocalFileStore)(nil)
+var _ Pinger = (*LocalFileStore)(nil)
 
 func NewLocalFileStore(basePath string) *LocalFileStore {
 	return &LocalFileStore{basePath: basePath}
 }
 
+// Ping crea el directorio base si no existe, igual que la primera escritura.
+func (l *LocalFileStore) Ping(ctx context.Context) error {
+	return os.MkdirAll(l.basePath, 0755)
+}
+
 func (l *LocalFileStore) getFullPath(filePath string) string {
 	return filepath.Join(l.basePath, filePath)
 }
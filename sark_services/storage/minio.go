@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"path"
@@ -19,6 +20,7 @@ type MinioFileStore struct {
 }
 
 var _ FileStorer = (*MinioFileStore)(nil)
+var _ Pinger = (*MinioFileStore)(nil)
 
 func NewMinioFileStoreWithClient(ctx context.Context, bucket string, client *minio.Client) (*MinioFileStore, error) {
 	bucket = strings.Trim(bucket, "/")
@@ -30,6 +32,17 @@ func NewMinioFileStoreWithClient(ctx context.Context, bucket string, client *min
 	}, nil
 }
 
+func (s *MinioFileStore) Ping(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucketName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s not found", s.bucketName)
+	}
+	return nil
+}
+
 func (s *MinioFileStore) getFullPath(filepath string) string {
 	return path.Join(s.subdirectory, filepath)
 }
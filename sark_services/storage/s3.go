@@ -21,6 +21,7 @@ type S3FileStore struct {
 }
 
 var _ FileStorer = (*S3FileStore)(nil)
+var _ Pinger = (*S3FileStore)(nil)
 
 func NewS3FileStoreWithClient(ctx context.Context, bucket string, client *s3.Client) (*S3FileStore, error) {
 	bucket = strings.Trim(bucket, "/")
@@ -32,6 +33,11 @@ func NewS3FileStoreWithClient(ctx context.Context, bucket string, client *s3.Cli
 	}, nil
 }
 
+func (s *S3FileStore) Ping(ctx context.Context) error {
+	_, err := s.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(s.bucketName)})
+	return err
+}
+
 func (s *S3FileStore) getFullPath(filepath string) string {
 	return path.Join(s.subdirectory, filepath)
 }
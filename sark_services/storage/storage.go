@@ -15,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/minio/minio-go/v7"
 	miniocredentials "github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/sfperusacdev/identitysdk/health"
 	"github.com/sfperusacdev/identitysdk/sark_services/variables"
 )
 
@@ -48,6 +49,21 @@ func (s *StorageService) CreateGlobal(ctx context.Context, bucket string) (FileS
 	return s.CreateWith(ctx, bucket, s.variables.Global.Read)
 }
 
+// ReadinessCheck crea el storage del servicio para el bucket y verifica que sea
+// accesible; se registra con health.Register para incluirlo en /readyz.
+func (s *StorageService) ReadinessCheck(bucket string) health.Check {
+	return func(ctx context.Context) error {
+		storer, err := s.Create(ctx, bucket)
+		if err != nil {
+			return err
+		}
+		if pinger, ok := storer.(Pinger); ok {
+			return pinger.Ping(ctx)
+		}
+		return nil
+	}
+}
+
 func (s *StorageService) CreateWith(ctx context.Context, bucket string, readVariable ReadVariableFunc) (FileStorer, error) {
 	driver, err := readOptionalVariable(ctx, readVariable, "STORAGE_DRIVER")
 	if err != nil {
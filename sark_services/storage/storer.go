@@ -29,6 +29,11 @@ type Lister interface {
 	List(ctx context.Context, prefix string) ([]string, error)
 }
 
+// Pinger lo implementan los drivers; verifica que el bucket o directorio sea accesible.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
 type FileStorer interface {
 	Reader
 	Writer
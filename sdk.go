@@ -10,6 +10,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/sfperusacdev/identitysdk/entities"
+	"github.com/sfperusacdev/identitysdk/tracing"
 	"github.com/user0608/goones/answer"
 	"github.com/user0608/goones/errs"
 )
@@ -23,6 +24,8 @@ const jwt_token_key = keyType("jwt-token-context-key")
 const domain_key = keyType("domain_key")
 const sucursal_codigo_key = keyType("sucursal_codigo_key")
 const request_origin_key = keyType("request_origin")
+const operation_key = keyType("operation")
+const request_id_key = keyType("request_id")
 
 type JwtMiddleware echo.MiddlewareFunc
 
@@ -310,6 +313,27 @@ func CtxWithRequestOrigin(ctx context.Context, origin string) context.Context {
 	return context.WithValue(ctx, request_origin_key, origin)
 }
 
+// CtxWithOperation guarda la operación en curso: la ruta HTTP ("POST /api/v1/...")
+// o el método gRPC; la usan la auditoría y los logs.
+func CtxWithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operation_key, operation)
+}
+
+func Operation(c context.Context) string {
+	operation, _ := c.Value(operation_key).(string)
+	return operation
+}
+
+// CtxWithRequestID guarda el identificador de la petición (cabecera X-Request-ID).
+func CtxWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, request_id_key, requestID)
+}
+
+func RequestID(c context.Context) string {
+	requestID, _ := c.Value(request_id_key).(string)
+	return requestID
+}
+
 func CtxWithSucursal(ctx context.Context, sucursal string) context.Context {
 	return context.WithValue(ctx, sucursal_codigo_key, sucursal)
 }
@@ -436,6 +460,13 @@ func CloneContext(ctx context.Context) context.Context {
 	newCtx = context.WithValue(newCtx, sucursal_codigo_key, sucursal)
 	newCtx = context.WithValue(newCtx, jwt_token_key, Token(ctx))
 	newCtx = context.WithValue(newCtx, request_origin_key, RequestOrigin(ctx))
+	if operation := Operation(ctx); operation != "" {
+		newCtx = context.WithValue(newCtx, operation_key, operation)
+	}
+	if requestID := RequestID(ctx); requestID != "" {
+		newCtx = context.WithValue(newCtx, request_id_key, requestID)
+	}
+	newCtx = tracing.ContextWithSpanContext(newCtx, tracing.SpanContextFromContext(ctx))
 
 	if session, ok := ReadSession(ctx); ok {
 		newCtx = context.WithValue(newCtx, jwt_session_key, session)
@@ -9,6 +9,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/sfperusacdev/identitysdk/xreq"
 )
 
 const (
@@ -67,7 +69,7 @@ func GetServiceURL(ctx context.Context, companyCode string, resourceCode string)
 	}
 
 	req.URL.RawQuery = queryParams.Encode()
-	res, err := http.DefaultClient.Do(req)
+	res, err := xreq.DefaultClient().Do(req)
 	if err != nil {
 		return "", err
 	}
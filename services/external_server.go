@@ -3,15 +3,18 @@ package services
 import (
 	"context"
 	"errors"
+	"log/slog"
 
 	"github.com/sfperusacdev/identitysdk"
 	"github.com/sfperusacdev/identitysdk/configs"
+	"github.com/sfperusacdev/identitysdk/sark_services/contratos"
 )
 
 var ErrNotFound = errors.New("the record you are looking for was not found")
 
 type ExternalBridgeService struct {
 	configProvider configs.GeneralServiceConfigProvider
+	contratos      *contratos.ContratosService
 }
 
 func NewExternalBridgeService(
@@ -22,6 +25,15 @@ func NewExternalBridgeService(
 	}
 }
 
+// UseContratosService habilita los RPC con streaming de contratos; sin él se usa HTTP.
+func (s *ExternalBridgeService) UseContratosService(service *contratos.ContratosService) {
+	if service == nil {
+		slog.Warn("ContratosService is nil, operation skipped")
+		return
+	}
+	s.contratos = service
+}
+
 func (*ExternalBridgeService) readCompanyAndToken(ctx context.Context) (string, string) {
 	var company = identitysdk.Empresa(ctx)
 	var token = identitysdk.Token(ctx)
@@ -3,14 +3,12 @@ package services
 import (
 	"context"
 	"fmt"
-	"log/slog"
 	"os"
 	"strings"
 
 	"github.com/sfperusacdev/identitysdk"
 	integracioncache "github.com/sfperusacdev/identitysdk/internal/integracion_cache"
 	"github.com/sfperusacdev/identitysdk/xreq"
-	"github.com/user0608/ifdevmode"
 )
 
 // IntegracionExternaCodigo devuelve el codigo de la compania en el sistema externo
@@ -23,9 +21,7 @@ func (s *ExternalBridgeService) IntegracionExternaCodigo(ctx context.Context, co
 	}
 	var cachedValue = integracioncache.DefaultCache.Get(ctx, company)
 	if cachedValue != nil {
-		if ifdevmode.Yes() {
-			slog.Info("IntegracionExternaCodigo read from cache")
-		}
+		identitysdk.Logger(ctx).Debug("IntegracionExternaCodigo read from cache")
 		return cachedValue.ExternalReff, nil
 	}
 	var baseUrl = identitysdk.GetIdentityServer()
@@ -91,9 +87,7 @@ func (s *ExternalBridgeService) IntegracionExternaURl(ctx context.Context, compa
 	}
 	var cachedValue = integracioncache.DefaultCache.Get(ctx, company)
 	if cachedValue != nil {
-		if ifdevmode.Yes() {
-			slog.Info("IntegracionExternaURl read from cache")
-		}
+		identitysdk.Logger(ctx).Debug("IntegracionExternaURl read from cache")
 		val, readOnly := s.integracionExternaURlSplit(cachedValue.IntegrationURL)
 		return val, readOnly, nil
 	}
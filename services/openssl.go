@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/sfperusacdev/identitysdk"
+	identitygrpc "github.com/sfperusacdev/identitysdk/grpc"
 	"github.com/sfperusacdev/identitysdk/xreq"
 )
 
@@ -70,6 +71,21 @@ func (s *ExternalBridgeService) IssueCompanyCertificate(ctx context.Context, dom
 	return &apiresponse.Data, nil
 }
 
+// CompanyKeyPairSource uses the company certificate issued by identity as the
+// gRPC TLS key pair; the certificate must be issued beforehand.
+func (s *ExternalBridgeService) CompanyKeyPairSource(domain string) identitygrpc.KeyPairSource {
+	return func(ctx context.Context) ([]byte, []byte, error) {
+		cert, err := s.GetCompanyCertificate(ctx, domain)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cert.Certificate == "" || cert.PrivateKey == "" {
+			return nil, nil, fmt.Errorf("company %s has no issued certificate", domain)
+		}
+		return []byte(cert.Certificate), []byte(cert.PrivateKey), nil
+	}
+}
+
 // GetCompanyCertificate retrieves the existing certificate for the authenticated company.
 func (s *ExternalBridgeService) GetCompanyCertificate(ctx context.Context, domain string) (*Certificate, error) {
 	var apiresponse struct {
@@ -12,42 +12,95 @@ import (
 
 	"github.com/sfperusacdev/identitysdk"
 	"github.com/sfperusacdev/identitysdk/entities"
+	"github.com/sfperusacdev/identitysdk/sark_services/contratos"
 	"github.com/sfperusacdev/identitysdk/xreq"
 	"github.com/user0608/goones/errs"
 	"github.com/user0608/goones/types"
 )
 
 func (s *ExternalBridgeService) GetTrabajadores(ctx context.Context, incluirInactivos bool) ([]entities.ResumenTrabajadorDto, error) {
-	var company, token = s.readCompanyAndToken(ctx)
-	baseurl, err := identitysdk.GetContratosServiceURL(ctx, company)
+	if s.contratos != nil {
+		response, received, err := s.streamTrabajadores(ctx, incluirInactivos)
+		if err == nil {
+			return response, nil
+		}
+		// un error a mitad del stream no se puede completar por HTTP
+		if received > 0 {
+			return nil, err
+		}
+		slog.Warn("trabajadores stream not available, falling back to http", "empresa", identitysdk.Empresa(ctx), "error", err)
+	}
+	return s.getTrabajadoresHTTP(ctx, incluirInactivos)
+}
+
+func (s *ExternalBridgeService) streamTrabajadores(ctx context.Context, incluirInactivos bool) ([]entities.ResumenTrabajadorDto, int, error) {
+	var response []entities.ResumenTrabajadorDto
+	filter := contratos.TrabajadoresFilter{SoloActivos: !incluirInactivos}
+	for itm, err := range s.contratos.Trabajadores(ctx, filter) {
+		if err != nil {
+			return nil, len(response), err
+		}
+		var deletedAt *time.Time
+		if itm.GetDeletedAt() != "" {
+			if value, err := time.Parse(time.RFC3339, itm.GetDeletedAt()); err == nil {
+				deletedAt = &value
+			}
+		}
+		response = append(response, entities.ResumenTrabajadorDto{
+			Codigo:          itm.GetCodigo(),
+			Nombres:         itm.GetNombres(),
+			ApellidoPaterno: itm.GetApellidoPaterno(),
+			ApellidoMaterno: itm.GetApellidoMaterno(),
+			Documento: entities.ResumenTrabajadorDocumentoIdentidadDto{
+				Tipo:   itm.GetTipoDocumento(),
+				Numero: itm.GetDni(),
+			},
+			Cargo: entities.ResumenTrabajadorCargoDto{
+				Codigo:      itm.GetCargoCodigo(),
+				Descripcion: itm.GetCargo(),
+			},
+			PlanillaCodigo: itm.GetPlanillaId(),
+			FechaIngreso:   itm.GetFechaIngreso(),
+			Email:          itm.GetEmail(),
+			Telefono:       itm.GetTelefono(),
+			Sexo:           itm.GetSexo(),
+			DeletedAt:      deletedAt,
+		})
+	}
+	if response == nil {
+		response = []entities.ResumenTrabajadorDto{}
+	}
+	return response, len(response), nil
+}
+
+type trabajadorHTTPItem struct {
+	Codigo                 string     `json:"codigo"`
+	Dni                    string     `json:"dni"`
+	Nombres                string     `json:"nombres"`
+	ApellidoMaterno        string     `json:"apellido_materno"`
+	ApellidoPaterno        string     `json:"apellido_paterno"`
+	TipoDocumentoIdentidad string     `json:"tipo_documento_identidad"`
+	DocumentoIdentidad     string     `json:"documento_identidad"`
+	CargoCodigo            string     `json:"cargo_codigo"`
+	Cargo                  string     `json:"cargo"`
+	PlanillaCodigo         string     `json:"planilla_codigo"`
+	FechaIngreso           string     `json:"fecha_ingreso"`
+	ImageLocation          string     `json:"image_location"`
+	Email                  string     `json:"email"`
+	Telefono               string     `json:"telefono"`
+	Edad                   string     `json:"edad"`
+	Estado                 bool       `json:"estado"`
+	Sexo                   string     `json:"sexo"`
+	DeletedAt              *time.Time `json:"deleted_at"`
+	IsDisabled             bool       `json:"is_disabled"`
+}
+
+func (s *ExternalBridgeService) getTrabajadoresHTTP(ctx context.Context, incluirInactivos bool) ([]entities.ResumenTrabajadorDto, error) {
+	baseurl, err := identitysdk.GetContratosServiceURL(ctx, identitysdk.Empresa(ctx))
 	if err != nil {
 		slog.Error("error trying to retrieve `contratos` service url", "error", err)
 		return nil, err
 	}
-	var apiresponse struct {
-		Message string `json:"message"`
-		Data    []struct {
-			Codigo                 string     `json:"codigo"`
-			Dni                    string     `json:"dni"`
-			Nombres                string     `json:"nombres"`
-			ApellidoMaterno        string     `json:"apellido_materno"`
-			ApellidoPaterno        string     `json:"apellido_paterno"`
-			TipoDocumentoIdentidad string     `json:"tipo_documento_identidad"`
-			DocumentoIdentidad     string     `json:"documento_identidad"`
-			CargoCodigo            string     `json:"cargo_codigo"`
-			Cargo                  string     `json:"cargo"`
-			PlanillaCodigo         string     `json:"planilla_codigo"`
-			FechaIngreso           string     `json:"fecha_ingreso"`
-			ImageLocation          string     `json:"image_location"`
-			Email                  string     `json:"email"`
-			Telefono               string     `json:"telefono"`
-			Edad                   string     `json:"edad"`
-			Estado                 bool       `json:"estado"`
-			Sexo                   string     `json:"sexo"`
-			DeletedAt              *time.Time `json:"deleted_at"`
-			IsDisabled             bool       `json:"is_disabled"`
-		} `json:"data"`
-	}
 	var enpointPath = "/v1/fotocheck/trabajadores/json"
 
 	var queryParams = make(url.Values)
@@ -55,17 +108,16 @@ func (s *ExternalBridgeService) GetTrabajadores(ctx context.Context, incluirInac
 		queryParams.Set("incluir_inactivos", "yes")
 	}
 
-	if err := xreq.MakeRequest(ctx,
-		baseurl, enpointPath,
-		xreq.WithAuthorization(token),
-		xreq.WithUnmarshalResponseInto(&apiresponse),
+	items, err := xreq.Do[xreq.NoBody, []trabajadorHTTPItem](ctx,
+		baseurl, http.MethodGet, enpointPath, xreq.NoBody{},
 		xreq.WithQueryParams(queryParams),
-	); err != nil {
+	)
+	if err != nil {
 		return nil, err
 	}
 
-	var response = make([]entities.ResumenTrabajadorDto, 0, len(apiresponse.Data))
-	for _, itm := range apiresponse.Data {
+	var response = make([]entities.ResumenTrabajadorDto, 0, len(items))
+	for _, itm := range items {
 		response = append(response, entities.ResumenTrabajadorDto{
 			Codigo:          itm.Codigo,
 			Nombres:         itm.Nombres,
@@ -167,29 +219,19 @@ type TrabajadorAsisteciaItem struct {
 func (s *ExternalBridgeService) TrabajadoresConAsistencia(ctx context.Context,
 	desde, hasta types.DateOnly,
 ) ([]TrabajadorAsisteciaItem, error) {
-	company, token := s.readCompanyAndToken(ctx)
-	baseURL, err := identitysdk.GetAsistenciaServiceURL(ctx, company)
+	baseURL, err := identitysdk.GetAsistenciaServiceURL(ctx, identitysdk.Empresa(ctx))
 	if err != nil {
 		slog.Error("error retrieving service URL", "error", err)
 		return nil, err
 	}
 
-	var response struct {
-		Message string                    `json:"message"`
-		Data    []TrabajadorAsisteciaItem `json:"data"`
-	}
-
-	if err := xreq.MakeRequest(
+	return xreq.Do[xreq.NoBody, []TrabajadorAsisteciaItem](
 		ctx,
 		baseURL,
+		http.MethodGet,
 		"/v2/api/trabajadores/con-marcas/fecha",
-		xreq.WithAuthorization(token),
+		xreq.NoBody{},
 		xreq.WithQueryParam("desde", desde.String()),
 		xreq.WithQueryParam("hasta", hasta.String()),
-		xreq.WithUnmarshalResponseInto(&response),
-	); err != nil {
-		return nil, err
-	}
-
-	return response.Data, nil
+	)
 }
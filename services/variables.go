@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/cache"
 	"github.com/sfperusacdev/identitysdk/entities"
 	variablecache "github.com/sfperusacdev/identitysdk/internal/variable_cache"
 	"github.com/sfperusacdev/identitysdk/xreq"
@@ -23,6 +24,7 @@ func (s *ExternalBridgeService) ReadVariable(ctx context.Context, variableName s
 // Deprecated: use bridge.Variables.Read instead.
 func (s *ExternalBridgeService) ReadCompanyVariable(ctx context.Context, company string, variableName string) (string, error) {
 	var cachedValue = variablecache.DefaultCache.GetVariable(ctx, company, variableName)
+	cache.ObserveLookup(cache.NameVariables, cachedValue != nil)
 	if cachedValue != nil {
 		return strings.TrimSpace(*cachedValue), nil
 	}
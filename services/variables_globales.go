@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/cache"
 	"github.com/sfperusacdev/identitysdk/entities"
 	variablecache "github.com/sfperusacdev/identitysdk/internal/variable_cache"
 	"github.com/sfperusacdev/identitysdk/xreq"
@@ -14,6 +15,7 @@ import (
 func (s *ExternalBridgeService) ReadVariableGlobal(ctx context.Context, variableName string) (string, error) {
 	var company = "____global____system____domain____"
 	var cachedValue = variablecache.DefaultCache.GetVariable(ctx, company, variableName)
+	cache.ObserveLookup(cache.NameVariables, cachedValue != nil)
 	if cachedValue != nil {
 		return strings.TrimSpace(*cachedValue), nil
 	}
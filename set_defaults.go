@@ -1,5 +1,12 @@
 package identitysdk
 
+import (
+	"context"
+	"strings"
+
+	"github.com/sfperusacdev/identitysdk/xreq"
+)
+
 var (
 	identityAddress string
 	accessToken     string
@@ -10,3 +17,14 @@ func GetIdentityServer() string        { return identityAddress }
 
 func SetAccessToken(token string) { accessToken = token }
 func GetAccessToken() string      { return accessToken }
+
+func init() {
+	// xreq.Do reenvía el token del usuario o, en procesos sin usuario, el access token del servicio
+	xreq.SetCredentialsFunc(func(ctx context.Context) xreq.Credentials {
+		token := Token(ctx)
+		if strings.HasPrefix(token, "####") {
+			token = ""
+		}
+		return xreq.Credentials{Token: token, AccessToken: accessToken}
+	})
+}
@@ -0,0 +1,72 @@
+package setup
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+
+	"github.com/sfperusacdev/identitysdk/configs"
+	"github.com/sfperusacdev/identitysdk/helpers/properties/models"
+	"github.com/sfperusacdev/identitysdk/httpapi"
+	connection "github.com/sfperusacdev/identitysdk/pg-connection"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+// openAPICommand construye las rutas con los mismos módulos de Run, sin base de
+// datos ni identity, y escribe el documento OpenAPI 3 en stdout.
+func (s *Service) openAPICommand(opts []fx.Option) *cobra.Command {
+	return &cobra.Command{
+		Use:   "openapi",
+		Short: "Prints the OpenAPI 3 document of the registered HTTP routes (--config is optional)",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := s.writeOpenAPI(cmd, opts, os.Stdout); err != nil {
+				slog.Error("generating openapi document", "error", err)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func (s *Service) writeOpenAPI(cmd *cobra.Command, opts []fx.Option, w io.Writer) error {
+	var gsc configs.GeneralServiceConfigProvider = &configs.GeneralServiceConfig{}
+	if configPath := configs.ConfigPath(cmd.Flag("config").Value.String()); configPath != "" {
+		s.configPath = &configPath
+		loaded, _, err := s.configs()
+		if err != nil {
+			return err
+		}
+		gsc = loaded
+	}
+	storage, err := connection.SkipStorage()
+	if err != nil {
+		return err
+	}
+
+	var catalog httpapi.RouteCatalog
+	appOpts := s.appOptions(gsc, storage, []models.DetailedSystemProperty{}, opts)
+	appOpts = append(appOpts, fx.Populate(&catalog), fx.NopLogger)
+	if err := fx.New(appOpts...).Err(); err != nil {
+		return err
+	}
+	if len(catalog) == 0 {
+		return errors.New("no http routes registered")
+	}
+
+	title := s.options.details.Name
+	if title == "" {
+		title = path.Base(os.Args[0])
+	}
+	document := httpapi.BuildOpenAPI(httpapi.OpenAPIInfo{
+		Title:       title,
+		Description: s.options.details.Description,
+		Version:     s.version,
+	}, catalog)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
@@ -0,0 +1,155 @@
+package setup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/configs"
+	identitygrpc "github.com/sfperusacdev/identitysdk/grpc"
+	"github.com/sfperusacdev/identitysdk/httpapi"
+	"github.com/sfperusacdev/identitysdk/permissions"
+	"github.com/sfperusacdev/identitysdk/xreq"
+	"go.uber.org/fx"
+)
+
+const permissionCatalogPath = "/api/v1/internal/system/permissions"
+
+const (
+	catalogMinRetry = 5 * time.Second
+	catalogMaxRetry = 5 * time.Minute
+)
+
+// referencedPermissions reúne los permisos usados por las rutas HTTP y los métodos gRPC.
+func referencedPermissions(routes httpapi.RouteCatalog, methods identitygrpc.MethodCatalog) []permissions.Definition {
+	var ids []string
+	for _, route := range routes {
+		ids = append(ids, route.ReferencedPermissions()...)
+	}
+	ids = append(ids, methods.ReferencedPermissions()...)
+	return permissions.Definitions(ids)
+}
+
+// publishPermissionCatalog publica en identity los permisos que usa el
+// servicio sin retrasar el arranque: corre en segundo plano y, si identity no
+// responde, reintenta con backoff hasta lograrlo o hasta que el servicio se
+// detenga.
+func (s *Service) publishPermissionCatalog(
+	lc fx.Lifecycle,
+	c configs.GeneralServiceConfigProvider,
+	routes httpapi.RouteCatalog,
+	methods identitygrpc.MethodCatalog,
+) {
+	if s.options.details.Name == "" {
+		return
+	}
+	if c.IdentityAccessToken() == "" {
+		slog.Warn("Failed to publish permission catalog: missing access token")
+		return
+	}
+	definitions := referencedPermissions(routes, methods)
+	if len(definitions) == 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				s.retryPermissionCatalog(ctx, c, definitions)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}
+
+func (s *Service) retryPermissionCatalog(
+	ctx context.Context,
+	c configs.GeneralServiceConfigProvider,
+	definitions []permissions.Definition,
+) {
+	wait := catalogMinRetry
+	for {
+		err := s.sendPermissionCatalog(ctx, c, definitions)
+		if err == nil {
+			slog.Info("Permission catalog published", "permissions", len(definitions))
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("Failed to publish permission catalog, retrying", "retry_in", wait, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, catalogMaxRetry)
+	}
+}
+
+// sendPermissionCatalog advierte sobre los permisos que identity todavía no
+// conoce y publica el catálogo.
+func (s *Service) sendPermissionCatalog(
+	ctx context.Context,
+	c configs.GeneralServiceConfigProvider,
+	definitions []permissions.Definition,
+) error {
+	accessToken := c.IdentityAccessToken()
+	var known struct {
+		Data []string `json:"data"`
+	}
+	if err := xreq.MakeRequest(
+		ctx,
+		c.Identity(),
+		permissionCatalogPath,
+		xreq.WithAccessToken(accessToken),
+		xreq.WithUnmarshalResponseInto(&known),
+	); err != nil {
+		return fmt.Errorf("fetch identity permissions: %w", err)
+	}
+	knownSet := make(map[string]bool, len(known.Data))
+	for _, id := range known.Data {
+		knownSet[id] = true
+	}
+	var unknown []string
+	for _, definition := range definitions {
+		if !knownSet[definition.ID] {
+			unknown = append(unknown, definition.ID)
+		}
+	}
+	if len(unknown) > 0 {
+		slog.Warn("Permissions referenced in code are unknown to identity", "permissions", unknown)
+	}
+
+	var buff bytes.Buffer
+	if err := json.NewEncoder(&buff).Encode(map[string]any{
+		"service":     s.options.details.Name,
+		"permissions": definitions,
+	}); err != nil {
+		return err
+	}
+	return xreq.MakeRequest(
+		ctx,
+		c.Identity(),
+		permissionCatalogPath,
+		xreq.WithMethod(http.MethodPost),
+		xreq.WithRequestBody(&buff),
+		xreq.WithJsonContentType(),
+		xreq.WithAccessToken(accessToken),
+	)
+}
@@ -15,37 +15,53 @@ import (
 	"path"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/pressly/goose/v3"
 	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/cache"
 	"github.com/sfperusacdev/identitysdk/configs"
 	identitygrpc "github.com/sfperusacdev/identitysdk/grpc"
 	grpcclient "github.com/sfperusacdev/identitysdk/grpc/client"
+	"github.com/sfperusacdev/identitysdk/health"
+	"github.com/sfperusacdev/identitysdk/helpers/audit"
 	"github.com/sfperusacdev/identitysdk/helpers/docxtopdf"
+	"github.com/sfperusacdev/identitysdk/helpers/domainexecutor"
+	"github.com/sfperusacdev/identitysdk/helpers/eventbus"
 	"github.com/sfperusacdev/identitysdk/helpers/facecropper"
 	"github.com/sfperusacdev/identitysdk/helpers/fotocheck"
+	"github.com/sfperusacdev/identitysdk/helpers/idempotency"
+	"github.com/sfperusacdev/identitysdk/helpers/loglevel"
 	"github.com/sfperusacdev/identitysdk/helpers/monitoring"
+	"github.com/sfperusacdev/identitysdk/helpers/outbox"
 	"github.com/sfperusacdev/identitysdk/helpers/properties"
 	"github.com/sfperusacdev/identitysdk/helpers/properties/models"
 	propertiesfx "github.com/sfperusacdev/identitysdk/helpers/properties/properties_fx"
 	propsprovider "github.com/sfperusacdev/identitysdk/helpers/properties/props_provider"
+	"github.com/sfperusacdev/identitysdk/helpers/ratelimit"
+	"github.com/sfperusacdev/identitysdk/helpers/revocation"
+	"github.com/sfperusacdev/identitysdk/helpers/scheduler"
 	"github.com/sfperusacdev/identitysdk/helpers/scripting"
 	"github.com/sfperusacdev/identitysdk/helpers/signpdf"
 	"github.com/sfperusacdev/identitysdk/helpers/staging"
 	"github.com/sfperusacdev/identitysdk/helpers/sunat"
 	"github.com/sfperusacdev/identitysdk/helpers/workflows"
 	"github.com/sfperusacdev/identitysdk/httpapi"
+	"github.com/sfperusacdev/identitysdk/jwks"
+	"github.com/sfperusacdev/identitysdk/logging"
 	"github.com/sfperusacdev/identitysdk/mmsql"
 	connection "github.com/sfperusacdev/identitysdk/pg-connection"
 	identitybridge "github.com/sfperusacdev/identitysdk/sark_services"
+	"github.com/sfperusacdev/identitysdk/sark_services/contratos"
+	"github.com/sfperusacdev/identitysdk/sark_services/storage"
 	"github.com/sfperusacdev/identitysdk/testdb"
+	"github.com/sfperusacdev/identitysdk/tracing"
 	"github.com/sfperusacdev/identitysdk/utils/sql/sqlreader"
 	"github.com/sfperusacdev/identitysdk/utils/sql/sqlviews"
 
 	identitysdk_services "github.com/sfperusacdev/identitysdk/services"
 	"github.com/sfperusacdev/identitysdk/xreq"
 	"github.com/spf13/cobra"
-	"github.com/user0608/ifdevmode"
 	"github.com/user0608/numeroaletras"
 	"go.uber.org/fx"
 	"gopkg.in/yaml.v2"
@@ -67,6 +83,19 @@ type ServiceOptions struct {
 	storedProceduresDir           fs.FS
 	storageManagerProvider        StorageManagerProvider
 	externalBridgeServiceProvider ExternalBridgeServiceProvider
+	cacheStore                    cache.Store
+	rateLimitStore                ratelimit.Store
+	traceExporter                 tracing.Exporter
+	shutdownDelay                 time.Duration
+	storageReadinessBucket        string
+	identityReadiness             bool
+	// módulos opcionales: cada uno crea sus tablas y tareas en segundo plano solo si se activa
+	audit       bool
+	idempotency bool
+	taskRoutes  bool
+	scheduler   bool
+	outbox      bool
+	eventBus    bool
 }
 
 type ServiceOption func(*ServiceOptions)
@@ -131,6 +160,108 @@ func WithExternalBridgeServiceProvider(provider ExternalBridgeServiceProvider) S
 	}
 }
 
+// WithCacheStore comparte sesiones validadas, api keys y revocaciones entre
+// réplicas usando el store indicado (por ejemplo cache.NewRedisStore).
+func WithCacheStore(store cache.Store) ServiceOption {
+	return func(o *ServiceOptions) {
+		if store == nil {
+			slog.Warn("Cache store is nil, operation skipped")
+			return
+		}
+		o.cacheStore = store
+	}
+}
+
+// WithRateLimitStore comparte los límites de httpapi.RateLimited entre réplicas,
+// por ejemplo con ratelimit.NewRedisStore.
+func WithRateLimitStore(store ratelimit.Store) ServiceOption {
+	return func(o *ServiceOptions) {
+		if store == nil {
+			slog.Warn("Rate limit store is nil, operation skipped")
+			return
+		}
+		o.rateLimitStore = store
+	}
+}
+
+// WithTraceExporter exporta los spans de httpapi, gRPC y xreq,
+// por ejemplo con tracing.NewStdoutExporter(os.Stdout).
+func WithTraceExporter(exporter tracing.Exporter) ServiceOption {
+	return func(o *ServiceOptions) {
+		if exporter == nil {
+			slog.Warn("Trace exporter is nil, operation skipped")
+			return
+		}
+		o.traceExporter = exporter
+	}
+}
+
+// WithShutdownDelay tiempo que /readyz responde 503 antes de detener los
+// servidores, para que el balanceador deje de enviar tráfico nuevo.
+func WithShutdownDelay(delay time.Duration) ServiceOption {
+	return func(o *ServiceOptions) {
+		if delay <= 0 {
+			slog.Warn("Shutdown delay is not positive, operation skipped")
+			return
+		}
+		o.shutdownDelay = delay
+	}
+}
+
+// WithStorageReadiness agrega a /readyz el storage del servicio (STORAGE_DRIVER)
+// para el bucket indicado.
+func WithStorageReadiness(bucket string) ServiceOption {
+	return func(o *ServiceOptions) {
+		bucket = strings.TrimSpace(bucket)
+		if bucket == "" {
+			slog.Warn("Storage readiness bucket is empty, operation skipped")
+			return
+		}
+		o.storageReadinessBucket = bucket
+	}
+}
+
+// WithIdentityReadiness agrega identity a /readyz. Por defecto no se incluye
+// porque una caída suya sacaría a todas las réplicas del balanceador; úselo
+// solo si el servicio no puede atender nada sin identity.
+func WithIdentityReadiness() ServiceOption {
+	return func(o *ServiceOptions) { o.identityReadiness = true }
+}
+
+// WithAudit guarda la auditoría en la tabla _audit_log y expone su consulta;
+// sin él audit.Record devuelve audit.ErrNoStore.
+func WithAudit() ServiceOption {
+	return func(o *ServiceOptions) { o.audit = true }
+}
+
+// WithIdempotency guarda las respuestas de las rutas idempotentes en la tabla
+// _idempotency_keys; sin él se guardan en la memoria de cada réplica.
+func WithIdempotency() ServiceOption {
+	return func(o *ServiceOptions) { o.idempotency = true }
+}
+
+// WithTaskRoutes expone el estado de las tareas de domainexecutor en /api/v1/_/tasks.
+func WithTaskRoutes() ServiceOption {
+	return func(o *ServiceOptions) { o.taskRoutes = true }
+}
+
+// WithScheduler ejecuta los jobs registrados con scheduler.AsJob.
+func WithScheduler() ServiceOption {
+	return func(o *ServiceOptions) { o.scheduler = true }
+}
+
+// WithOutbox entrega los mensajes de outbox.Add a las suscripciones
+// registradas con outbox.AsSubscription.
+func WithOutbox() ServiceOption {
+	return func(o *ServiceOptions) { o.outbox = true }
+}
+
+// WithEventBus distribuye los eventos de eventbus.Publish entre los servicios
+// de la misma base de datos.
+func WithEventBus() ServiceOption {
+	return func(o *ServiceOptions) { o.eventBus = true }
+}
+
 func WithDetails(serviceID, description string) ServiceOption {
 	return func(o *ServiceOptions) {
 		o.details = ServiceDetails{
@@ -161,6 +292,22 @@ func NewService(
 		apply(options)
 	}
 
+	if options.cacheStore != nil {
+		identitysdk.SetCacheStore(options.cacheStore)
+	}
+
+	if options.rateLimitStore != nil {
+		ratelimit.SetDefaultStore(options.rateLimitStore)
+	}
+
+	if options.traceExporter != nil {
+		tracing.SetExporter(options.traceExporter)
+	}
+
+	if options.shutdownDelay > 0 {
+		health.SetDrainDelay(options.shutdownDelay)
+	}
+
 	if options.details.Name != "" {
 		xreq.SetDefaultXOrigin(fmt.Sprintf("internal:%s", options.details.Name))
 	}
@@ -380,12 +527,10 @@ func (s *Service) drop_views(db *sql.DB, files []DbViewFile) error {
 				)
 				return err
 			}
-			ifdevmode.Do(func() {
-				slog.Info(
-					"view dropped",
-					"view", view,
-				)
-			}, ifdevmode.WithSyncExecution())
+			slog.Debug(
+				"view dropped",
+				"view", view,
+			)
 		}
 		num += len(f.Views)
 	}
@@ -408,12 +553,10 @@ func (s *Service) recovery_view(db *sql.DB, files []DbViewFile) error {
 			)
 			return err
 		}
-		ifdevmode.Do(func() {
-			slog.Info(
-				"view file executed",
-				"file", f.FileName,
-			)
-		}, ifdevmode.WithSyncExecution())
+		slog.Debug(
+			"view file executed",
+			"file", f.FileName,
+		)
 	}
 	if len(files) > 0 {
 		slog.Info(
@@ -473,6 +616,7 @@ func (s *Service) migrationCommand(use, shortDesc, migrationType string) *cobra.
 }
 
 func (s *Service) setupIdentity(c configs.GeneralServiceConfigProvider) error {
+	s.setupHTTPClient(c)
 	identitysdk.SetIdentityServer(c.Identity())
 	identitysdk.SetAccessToken(c.IdentityAccessToken())
 
@@ -481,9 +625,164 @@ func (s *Service) setupIdentity(c configs.GeneralServiceConfigProvider) error {
 		return err
 	}
 	slog.Info("Identity server OK!!")
+	s.setupTokenVerification(c)
 	return nil
 }
 
+// setupLogging aplica la sección logging; el nivel debug se activa con
+// logging.level o, por empresa, con el endpoint de loglevel.
+func (s *Service) setupLogging(c configs.GeneralServiceConfigProvider) {
+	var cfg configs.LoggingConfig
+	if provider, ok := c.(configs.LoggingConfigProvider); ok {
+		cfg = provider.Logging()
+	}
+	if err := logging.Setup(logging.Config{Format: cfg.Format, Level: cfg.Level}); err != nil {
+		slog.Warn("invalid logging config, using defaults", "error", err)
+	}
+}
+
+// grpcTLS arma las credenciales del servidor y de los clientes gRPC según la
+// sección grpc_tls; devuelve nil en el lado que no esté habilitado.
+func (s *Service) grpcTLS(
+	lc fx.Lifecycle,
+	c configs.GeneralServiceConfigProvider,
+	bridge *identitysdk_services.ExternalBridgeService,
+) (identitygrpc.ServerCredentials, identitygrpc.ClientCredentials, error) {
+	provider, ok := c.(configs.GRPCTLSConfigProvider)
+	if !ok {
+		return nil, nil, nil
+	}
+	cfg := provider.GRPCTLS()
+	if !cfg.Server.Enabled && !cfg.Client.Enabled {
+		return nil, nil, nil
+	}
+	clientAuth, err := identitygrpc.ParseClientAuth(cfg.Server.ClientAuth)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts := identitygrpc.TLSOptions{
+		ClientAuth:     clientAuth,
+		ServerName:     cfg.Client.ServerName,
+		ReloadInterval: cfg.ReloadInterval,
+	}
+	switch {
+	case cfg.Company != "":
+		opts.KeyPair = bridge.CompanyKeyPairSource(cfg.Company)
+	case cfg.CertFile != "" && cfg.KeyFile != "":
+		opts.KeyPair = identitygrpc.FileKeyPairSource(cfg.CertFile, cfg.KeyFile)
+	case cfg.Server.Enabled:
+		return nil, nil, errors.New("grpc_tls: server requires cert_file and key_file or company")
+	}
+	if cfg.CAFile != "" {
+		opts.CA = identitygrpc.FileCASource(cfg.CAFile)
+	}
+	tlsProvider, err := identitygrpc.NewTLSProvider(context.Background(), opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			tlsProvider.Start()
+			return nil
+		},
+		OnStop: health.OnShutdown(health.PhaseCleanup, "grpc-tls-reloader", func(context.Context) error {
+			return tlsProvider.Close()
+		}),
+	})
+
+	var serverCreds identitygrpc.ServerCredentials
+	var clientCreds identitygrpc.ClientCredentials
+	if cfg.Server.Enabled {
+		serverCreds = tlsProvider.ServerCredentials()
+	}
+	if cfg.Client.Enabled {
+		clientCreds = tlsProvider.ClientCredentials()
+	}
+	return serverCreds, clientCreds, nil
+}
+
+// defaultStopTimeout es el StopTimeout por defecto de fx.
+const defaultStopTimeout = 15 * time.Second
+
+// registerHealthChecks agrega la base de datos a /readyz. Identity solo se
+// incluye con WithIdentityReadiness: se verifica una vez al iniciar y una caída
+// suya no debe sacar a todas las réplicas del balanceador; las sesiones en
+// caché siguen sirviendo.
+func (s *Service) registerHealthChecks(connectionManager connection.StorageManager) {
+	if s.options.identityReadiness {
+		health.Register("identity", identitysdk.IdentityServerCheckHealthWithContext)
+	}
+	health.Register("database", func(ctx context.Context) error {
+		conn := connectionManager.Conn(ctx)
+		if conn == nil {
+			// almacenamiento deshabilitado
+			return nil
+		}
+		db, err := conn.DB()
+		if err != nil {
+			return err
+		}
+		return db.PingContext(ctx)
+	})
+}
+
+// registerStorageCheck agrega a /readyz el storage de WithStorageReadiness.
+func (s *Service) registerStorageCheck(storageService *storage.StorageService) {
+	health.Register("storage", storageService.ReadinessCheck(s.options.storageReadinessBucket))
+}
+
+func (s *Service) setupHTTPClient(c configs.GeneralServiceConfigProvider) {
+	provider, ok := c.(configs.HTTPClientConfigProvider)
+	if !ok {
+		return
+	}
+	cfg := provider.HTTPClient()
+	clientConfig := xreq.DefaultClientConfig()
+	if cfg.Timeout > 0 {
+		clientConfig.Timeout = cfg.Timeout
+	}
+	if cfg.MaxRetries != nil {
+		clientConfig.MaxRetries = *cfg.MaxRetries
+	}
+	if cfg.RetryBaseDelay > 0 {
+		clientConfig.RetryBaseDelay = cfg.RetryBaseDelay
+	}
+	if cfg.RetryMaxDelay > 0 {
+		clientConfig.RetryMaxDelay = cfg.RetryMaxDelay
+	}
+	if cfg.BreakerThreshold != nil {
+		clientConfig.BreakerThreshold = *cfg.BreakerThreshold
+	}
+	if cfg.BreakerCooldown > 0 {
+		clientConfig.BreakerCooldown = cfg.BreakerCooldown
+	}
+	xreq.SetDefaultClient(xreq.NewClient(clientConfig))
+}
+
+func (s *Service) setupTokenVerification(c configs.GeneralServiceConfigProvider) {
+	provider, ok := c.(configs.TokenVerificationConfigProvider)
+	if !ok {
+		return
+	}
+	cfg := provider.TokenVerification()
+	identitysdk.SetMaxTokenLifetime(cfg.MaxTokenLifetime)
+	if cfg.Mode != configs.TokenVerificationJWKS {
+		return
+	}
+
+	keySet := jwks.NewKeySet(cfg.JwksURL, jwks.WithHTTPClient(xreq.DefaultClient().StandardClient()))
+	if err := keySet.Refresh(context.Background()); err != nil {
+		slog.Warn("Failed to fetch identity JWKS, keys will be fetched on demand", "url", cfg.JwksURL, "error", err)
+	}
+	opts := []jwks.VerifierOption{jwks.WithAudience(cfg.Audience...)}
+	if cfg.Leeway > 0 {
+		opts = append(opts, jwks.WithLeeway(cfg.Leeway))
+	}
+	identitysdk.SetTokenVerifier(jwks.NewVerifier(keySet, opts...))
+	identitysdk.SetSessionRefreshInterval(cfg.SessionRefresh)
+	slog.Info("Offline token verification enabled", "jwks", cfg.JwksURL)
+}
+
 func (s *Service) publishServiceDetails(c configs.GeneralServiceConfigProvider) {
 	var accessToken = c.IdentityAccessToken()
 	if s.options.details.Name == "" {
@@ -528,6 +827,7 @@ func (s *Service) Run(opts ...fx.Option) error {
 			slog.Error("Error loading configs", "error", err)
 			os.Exit(1)
 		}
+		s.setupLogging(gsc)
 
 		automigration, err := cmd.Flags().GetBool("auto")
 		if err != nil {
@@ -583,69 +883,120 @@ func (s *Service) Run(opts ...fx.Option) error {
 			systemProperties = entries
 		}
 
-		opts = append([]fx.Option{fx.Invoke(s.setupIdentity)}, opts...)
-		opts = append(
-			opts,
-			fx.Supply(systemProperties),
-			fx.Provide(
-				func() configs.ConfigPath {
-					if s.configPath == nil {
-						return ""
-					}
-					return *s.configPath
-				},
-				func() configs.GeneralServiceConfigProvider { return gsc },
-				func() connection.StorageManager { return connectionManager },
-				func(c configs.GeneralServiceConfigProvider) httpapi.ServeURLString {
-					return httpapi.ServeURLString(c.ListenAddress())
-				},
-				func(c configs.GeneralServiceConfigProvider) identitygrpc.ServeAddress {
-					return identitygrpc.ServeAddress(c.GRPCAddress())
-				},
-			),
-
-			fx.Provide(s.options.externalBridgeServiceProvider),
-			// tools
-			grpcclient.Module,
-			fx.Provide(staging.NewStagingFilesArea),
-			fx.Provide(facecropper.NewFaceCropService),
-			fx.Provide(docxtopdf.NewDocxTemplateToPdfService),
-			fx.Provide(fotocheck.NewFotocheckBuilder),
-			fx.Provide(scripting.NewScriptCommonService),
-			fx.Provide(workflows.NewDocumentWorkflowStateManager),
-			fx.Provide(mmsql.NewStoredProcedureStore(s.options.storedProceduresDir)),
-			fx.Provide(
-				fx.Annotate(
-					propsprovider.NewSystemPropsPgProvider,
-					fx.As(new(properties.SystemPropsProvider)),
-				),
-				fx.Annotate(
-					numeroaletras.NewNumeroALetras,
-					fx.As(new(sunat.NumeroALetras)),
-				),
-				fx.Annotate(
-					signpdf.NewPopplerPDFInspector,
-					fx.As(new(signpdf.PDFInspector)),
-				),
-				fx.Annotate(
-					signpdf.NewPyhankoPDFSigner,
-					fx.As(new(signpdf.PDFSigner)),
-				),
-			),
-			propertiesfx.Module,
-			identitybridge.Module,
-			monitoring.Module,
-			identitygrpc.Module,
-			httpapi.Module,
-			fx.Invoke(s.publishServiceDetails, identitygrpc.StartServer, httpapi.StartWebServer),
-		)
-		app := fx.New(opts...)
+		appOpts := append([]fx.Option{fx.Invoke(s.setupIdentity)}, s.appOptions(gsc, connectionManager, systemProperties, opts)...)
+		s.registerHealthChecks(connectionManager)
+		if s.options.storageReadinessBucket != "" {
+			appOpts = append(appOpts, fx.Invoke(s.registerStorageCheck))
+		}
+		appOpts = append(appOpts, fx.Invoke(s.publishServiceDetails, s.publishPermissionCatalog, identitygrpc.StartServer, httpapi.StartWebServer))
+		// health.Lifecycle va al final: marca el servicio listo después de arrancar
+		// todo y, al detenerse, su OnStop es el primero en ejecutarse
+		appOpts = append(appOpts, fx.Invoke(health.Lifecycle))
+		if s.options.shutdownDelay > 0 {
+			appOpts = append(appOpts, fx.StopTimeout(s.options.shutdownDelay+defaultStopTimeout))
+		}
+		app := fx.New(appOpts...)
 		app.Run()
 		slog.Info("Application stopped")
 	}
+	s.Command.AddCommand(s.openAPICommand(opts))
 	if err := s.Command.Execute(); err != nil {
 		slog.Error("command execution failed", "error", err)
 		return err
 	}
 	return nil
 }
+
+// appOptions arma los módulos comunes de la aplicación, sin los Invoke de arranque.
+func (s *Service) appOptions(
+	gsc configs.GeneralServiceConfigProvider,
+	connectionManager connection.StorageManager,
+	systemProperties []models.DetailedSystemProperty,
+	opts []fx.Option,
+) []fx.Option {
+	options := append(
+		append([]fx.Option{}, opts...),
+		fx.Supply(systemProperties),
+		fx.Provide(
+			func() configs.ConfigPath {
+				if s.configPath == nil {
+					return ""
+				}
+				return *s.configPath
+			},
+			func() configs.GeneralServiceConfigProvider { return gsc },
+			func() connection.StorageManager { return connectionManager },
+			func(c configs.GeneralServiceConfigProvider) httpapi.ServeURLString {
+				return httpapi.ServeURLString(c.ListenAddress())
+			},
+			func(c configs.GeneralServiceConfigProvider) identitygrpc.ServeAddress {
+				return identitygrpc.ServeAddress(c.GRPCAddress())
+			},
+		),
+
+		fx.Provide(s.options.externalBridgeServiceProvider),
+		fx.Invoke(func(bridge *identitysdk_services.ExternalBridgeService, service *contratos.ContratosService) {
+			bridge.UseContratosService(service)
+		}),
+		fx.Provide(s.grpcTLS),
+		// tools
+		grpcclient.Module,
+		fx.Provide(staging.NewStagingFilesArea),
+		fx.Provide(facecropper.NewFaceCropService),
+		fx.Provide(docxtopdf.NewDocxTemplateToPdfService),
+		fx.Provide(fotocheck.NewFotocheckBuilder),
+		fx.Provide(scripting.NewScriptCommonService),
+		fx.Provide(workflows.NewDocumentWorkflowStateManager),
+		fx.Provide(mmsql.NewStoredProcedureStore(s.options.storedProceduresDir)),
+		fx.Provide(
+			fx.Annotate(
+				propsprovider.NewSystemPropsPgProvider,
+				fx.As(new(properties.SystemPropsProvider)),
+			),
+			fx.Annotate(
+				numeroaletras.NewNumeroALetras,
+				fx.As(new(sunat.NumeroALetras)),
+			),
+			fx.Annotate(
+				signpdf.NewPopplerPDFInspector,
+				fx.As(new(signpdf.PDFInspector)),
+			),
+			fx.Annotate(
+				signpdf.NewPyhankoPDFSigner,
+				fx.As(new(signpdf.PDFSigner)),
+			),
+		),
+		propertiesfx.Module,
+		identitybridge.Module,
+		monitoring.Module,
+		revocation.Module,
+		loglevel.Module,
+		identitygrpc.Module,
+		httpapi.Module,
+	)
+	return append(options, s.optionalModules()...)
+}
+
+// optionalModules devuelve los módulos activados con sus ServiceOption.
+func (s *Service) optionalModules() []fx.Option {
+	var modules []fx.Option
+	if s.options.audit {
+		modules = append(modules, audit.Module)
+	}
+	if s.options.idempotency {
+		modules = append(modules, idempotency.Module)
+	}
+	if s.options.taskRoutes {
+		modules = append(modules, domainexecutor.Module)
+	}
+	if s.options.scheduler {
+		modules = append(modules, scheduler.Module)
+	}
+	if s.options.outbox {
+		modules = append(modules, outbox.Module)
+	}
+	if s.options.eventBus {
+		modules = append(modules, eventbus.Module)
+	}
+	return modules
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/sfperusacdev/identitysdk"
 	"github.com/sfperusacdev/identitysdk/binds"
+	"github.com/sfperusacdev/identitysdk/health"
 	"github.com/sfperusacdev/identitysdk/helpers/domainexecutor"
 	"github.com/sfperusacdev/identitysdk/httpapi"
 	"github.com/sfperusacdev/identitysdk/setup/sqlsyncdata/usecase"
@@ -23,7 +24,7 @@ var _ httpapi.Route = (*GetTableSqlInfoHandler)(nil)
 
 func NewGetTableSqlInfoHandler(lc fx.Lifecycle, usecase *usecase.SQLTableUsecase) *GetTableSqlInfoHandler {
 	executor := domainexecutor.NewDefault()
-	lc.Append(fx.Hook{OnStop: executor.Shutdown})
+	lc.Append(fx.Hook{OnStop: health.OnShutdown(health.PhaseWorkers, "sqlsyncdata-statement-executor", executor.Shutdown)})
 
 	return &GetTableSqlInfoHandler{
 		usecase:  usecase,
@@ -3,10 +3,12 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sfperusacdev/identitysdk"
 	"github.com/sfperusacdev/identitysdk/binds"
+	"github.com/sfperusacdev/identitysdk/health"
 	"github.com/sfperusacdev/identitysdk/helpers/domainexecutor"
 	"github.com/sfperusacdev/identitysdk/httpapi"
 	"github.com/sfperusacdev/identitysdk/setup/sqlsyncdata/usecase"
@@ -21,10 +23,11 @@ type SqlTableSyncDataHandler struct {
 }
 
 var _ httpapi.Route = (*SqlTableSyncDataHandler)(nil)
+var _ httpapi.Idempotent = (*SqlTableSyncDataHandler)(nil)
 
 func NewSqlTableSyncDataHandler(lc fx.Lifecycle, usecase *usecase.SQLTableUsecase) *SqlTableSyncDataHandler {
 	executor := domainexecutor.NewDefault()
-	lc.Append(fx.Hook{OnStop: executor.Shutdown})
+	lc.Append(fx.Hook{OnStop: health.OnShutdown(health.PhaseWorkers, "sqlsyncdata-sync-executor", executor.Shutdown)})
 	return &SqlTableSyncDataHandler{
 		usecase:  usecase,
 		executor: executor,
@@ -35,6 +38,11 @@ func (h *SqlTableSyncDataHandler) GetPath() string {
 	return "/v1/sync_data/sync"
 }
 
+// IdempotencyRetention permite a los clientes móviles reintentar la sincronización sin duplicarla.
+func (h *SqlTableSyncDataHandler) IdempotencyRetention() time.Duration {
+	return 24 * time.Hour
+}
+
 func (h *SqlTableSyncDataHandler) HandleRequest(c echo.Context) error {
 	var ctx = c.Request().Context()
 	var domain = identitysdk.Empresa(ctx)
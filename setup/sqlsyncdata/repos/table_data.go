@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"strings"
 
 	"github.com/sfperusacdev/identitysdk/setup/sqlsyncdata/descriptor"
 	"github.com/user0608/goones/errs"
@@ -26,6 +27,35 @@ func (r *SQLTableRepository) GetTableData(ctx context.Context, domain string, de
 	return rows, nil
 }
 
+// GetRowsByKeys devuelve las filas guardadas con las mismas primary keys que rows.
+func (r *SQLTableRepository) GetRowsByKeys(ctx context.Context, tableName string, primaryKeys []string, rows []map[string]any) ([]map[string]any, error) {
+	var found = []map[string]any{}
+	if len(rows) == 0 || len(primaryKeys) == 0 {
+		return found, nil
+	}
+	db := r.manager.Conn(ctx)
+	if db == nil {
+		return nil, errs.BadRequestDirect("Pg connection is not oppend")
+	}
+	columns := make([]string, len(primaryKeys))
+	for i, pk := range primaryKeys {
+		columns[i] = db.Statement.Quote(pk)
+	}
+	keys := make([][]any, 0, len(rows))
+	for _, row := range rows {
+		key := make([]any, len(primaryKeys))
+		for i, pk := range primaryKeys {
+			key[i] = row[pk]
+		}
+		keys = append(keys, key)
+	}
+	rs := db.Table(tableName).Where("("+strings.Join(columns, ", ")+") IN ?", keys).Find(&found)
+	if rs.Error != nil {
+		return nil, errs.Pgf(rs.Error)
+	}
+	return found, nil
+}
+
 func (r *SQLTableRepository) InsertData(ctx context.Context, tableName string, rows []map[string]any) error {
 	if len(rows) == 0 {
 		return nil
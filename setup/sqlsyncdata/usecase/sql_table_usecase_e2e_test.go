@@ -2,9 +2,12 @@ package usecase_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/helpers/audit"
 	connection "github.com/sfperusacdev/identitysdk/pg-connection"
 	"github.com/sfperusacdev/identitysdk/setup/sqlsyncdata/repos"
 	"github.com/sfperusacdev/identitysdk/setup/sqlsyncdata/usecase"
@@ -222,6 +225,44 @@ type syncItemRow struct {
 	SyncAt int64  `gorm:"column:sync_at"`
 }
 
+func TestSQLTableUsecase_SyncTable_AuditsChangedRowsInsideTx(t *testing.T) {
+	ctx := identitysdk.CtxWithDomain(context.Background(), "acme")
+	storage := testdb.NewPostgresStorage(t)
+	createSyncItemsTable(t, ctx, storage, "sync_audit_items")
+	insertSyncItem(t, ctx, storage, "sync_audit_items", "acme.item", "server version", 200)
+	auditStore := audit.NewPgStore(storage)
+	audit.SetStore(auditStore)
+	t.Cleanup(func() { audit.SetStore(nil) })
+
+	tableUsecase := newTableUsecase(t, storage, usecase.TableDescriptors{
+		{Table: "sync_audit_items", Columns: []string{"name"}},
+	})
+	sync := func() {
+		err := storage.WithTx(ctx, func(ctx context.Context) error {
+			_, err := tableUsecase.SyncTable(ctx, "acme", usecase.TableSyncRequest{
+				TableName: "sync_audit_items",
+				Payload: []map[string]any{
+					{"id": "acme.item", "name": "client version"},
+				},
+			})
+			return err
+		})
+		require.NoError(t, err)
+	}
+	sync()
+	// la misma fila sin cambios no vuelve a auditarse aunque cambie sync_at
+	sync()
+
+	entries, err := auditStore.Query(ctx, audit.Filter{Empresa: "acme", Entity: "sync_audit_items"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	var changes map[string]audit.Change
+	require.NoError(t, json.Unmarshal(entries[0].Changes, &changes))
+	require.Equal(t, map[string]audit.Change{
+		"name": {Old: "server version", New: "client version"},
+	}, changes)
+}
+
 func newTableUsecase(
 	t *testing.T,
 	storage connection.StorageManager,
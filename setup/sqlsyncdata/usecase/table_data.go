@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/sfperusacdev/identitysdk/helpers/audit"
 	"github.com/user0608/goones/errs"
 )
 
@@ -55,6 +57,44 @@ func (s *SQLTableUsecase) composePrimaryKey(tableName string, primaryKeys []stri
 	return keyBuilder.String()
 }
 
+// auditVolatileColumns cambian en cada sincronización y no se auditan.
+var auditVolatileColumns = []string{"sync_at"}
+
+// auditEvents compara cada fila recibida con la guardada antes del upsert y
+// descarta las que no cambiaron.
+func (s *SQLTableUsecase) auditEvents(tableName string, primaryKeys []string, previous, rows []map[string]any) []audit.Event {
+	byKey := make(map[string]map[string]any, len(previous))
+	for _, row := range previous {
+		byKey[s.composePrimaryKey(tableName, primaryKeys, row)] = auditFields(row)
+	}
+	events := make([]audit.Event, 0, len(rows))
+	for _, row := range rows {
+		key := s.composePrimaryKey(tableName, primaryKeys, row)
+		event := audit.Event{
+			Action:    "sqlsyncdata.upsert",
+			Entity:    tableName,
+			EntityKey: key,
+			New:       auditFields(row),
+		}
+		if old, found := byKey[key]; found {
+			event.Old = old
+		}
+		if diff, err := audit.Diff(event.Old, event.New); err == nil && len(diff) == 0 {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func auditFields(row map[string]any) map[string]any {
+	fields := maps.Clone(row)
+	for _, column := range auditVolatileColumns {
+		delete(fields, column)
+	}
+	return fields
+}
+
 func (s *SQLTableUsecase) SyncTable(ctx context.Context, domain string, req TableSyncRequest) (*TableSyncResponse, error) {
 	nowMillis := time.Now().UnixMilli()
 
@@ -115,10 +155,22 @@ func (s *SQLTableUsecase) SyncTable(ctx context.Context, domain string, req Tabl
 		}
 	}
 
+	var previous []map[string]any
+	if audit.Enabled() {
+		previous, err = s.repository.GetRowsByKeys(ctx, req.TableName, primaryKeys, req.Payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.repository.InsertData(ctx, req.TableName, req.Payload); err != nil {
 		return nil, err
 	}
 
+	if audit.Enabled() {
+		audit.RecordOrLog(ctx, s.auditEvents(req.TableName, primaryKeys, previous, req.Payload)...)
+	}
+
 	rowsToReturn := make([]map[string]any, 0, len(existingRows))
 	for _, row := range existingRows {
 		id := s.composePrimaryKey(req.TableName, primaryKeys, row)
@@ -0,0 +1,75 @@
+package identitysdk
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/cache"
+	"github.com/sfperusacdev/identitysdk/entities"
+	sessioncache "github.com/sfperusacdev/identitysdk/internal/session_cache"
+	"github.com/sfperusacdev/identitysdk/jwks"
+	"github.com/user0608/goones/errs"
+)
+
+var (
+	tokenVerifier          *jwks.Verifier
+	sessionRefreshInterval = 5 * time.Minute
+)
+
+// SetTokenVerifier activa la verificación local de tokens (firma, exp, nbf, aud)
+// con las llaves JWKS de identity. Con nil se vuelve a validar cada token contra identity.
+func SetTokenVerifier(verifier *jwks.Verifier) { tokenVerifier = verifier }
+
+// SetSessionRefreshInterval define cada cuánto se refresca desde identity la
+// sesión (permisos, sucursales) de un token verificado localmente.
+func SetSessionRefreshInterval(d time.Duration) {
+	if d > 0 {
+		sessionRefreshInterval = d
+	}
+}
+
+func validateTokenOffline(ctx context.Context, token string) (*entities.JwtData, error) {
+	if _, err := tokenVerifier.Verify(ctx, token); err != nil {
+		if errors.Is(err, jwks.ErrKeySetUnavailable) {
+			slog.Warn("jwks unavailable, falling back to identity token validation", "error", err)
+			return validateTokenRemote(ctx, token)
+		}
+		slog.Warn("offline token verification failed", "error", err)
+		return nil, errs.BadRequestDirect("[close] token inválido")
+	}
+
+	cached, storedAt := sessioncache.DefaultCache.Lookup(ctx, token)
+	fresh := cached != nil && time.Since(storedAt) < sessionRefreshInterval
+	cache.ObserveLookup(cache.NameSession, fresh)
+	if fresh {
+		return cached, nil
+	}
+
+	jwtData, unavailable, err := checkTokenRemote(ctx, token)
+	if err != nil {
+		if unavailable && cached != nil {
+			slog.Warn("identity unavailable, serving stale session",
+				"empresa", cached.Jwt.Empresa,
+				"usuario", cached.Jwt.Username,
+				"stored_at", storedAt,
+			)
+			return cached, nil
+		}
+		return nil, err
+	}
+	sessioncache.DefaultCache.Set(ctx, token, *jwtData)
+	return jwtData, nil
+}
+
+func validateTokenRemote(ctx context.Context, token string) (*entities.JwtData, error) {
+	jwtData, err := ValidateToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if jwtData != nil {
+		sessioncache.DefaultCache.Set(ctx, token, *jwtData)
+	}
+	return jwtData, nil
+}
@@ -0,0 +1,64 @@
+package identitysdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/entities"
+	"github.com/sfperusacdev/identitysdk/jwks"
+	"github.com/sfperusacdev/identitysdk/jwks/jwkstest"
+)
+
+func TestValidateTokenWithCacheOffline(t *testing.T) {
+	keys := jwkstest.NewServer(t)
+
+	var checks atomic.Int32
+	identity := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"type": "success",
+			"data": entities.JwtData{Jwt: entities.Jwt{Empresa: "sfperu", Username: "kevin"}},
+		})
+	}))
+	defer identity.Close()
+
+	previous := identitysdk.GetIdentityServer()
+	identitysdk.SetIdentityServer(identity.URL)
+	identitysdk.SetTokenVerifier(jwks.NewVerifier(jwks.NewKeySet(keys.URL())))
+	identitysdk.SetSessionRefreshInterval(time.Hour)
+	t.Cleanup(func() {
+		identitysdk.SetIdentityServer(previous)
+		identitysdk.SetTokenVerifier(nil)
+	})
+
+	token := keys.Sign(t, nil)
+	for range 3 {
+		data, err := identitysdk.ValidateTokenWithCache(context.Background(), token)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if data.Jwt.Username != "kevin" {
+			t.Fatalf("unexpected session data: %+v", data.Jwt)
+		}
+	}
+	if checks.Load() != 1 {
+		t.Fatalf("expected a single identity call, got %d", checks.Load())
+	}
+
+	identity.Close()
+	identitysdk.SetSessionRefreshInterval(time.Nanosecond)
+	if _, err := identitysdk.ValidateTokenWithCache(context.Background(), token); err != nil {
+		t.Fatalf("expected stale session while identity is down, got %v", err)
+	}
+
+	forged := keys.Sign(t, map[string]any{"exp": time.Now().Add(-time.Hour).Unix()})
+	if _, err := identitysdk.ValidateTokenWithCache(context.Background(), forged); err == nil {
+		t.Fatal("expected expired token to be rejected")
+	}
+}
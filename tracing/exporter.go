@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// Exporter recibe los spans terminados; no debe bloquear al llamador.
+type Exporter interface {
+	ExportSpan(span SpanData)
+}
+
+type ExporterFunc func(SpanData)
+
+func (f ExporterFunc) ExportSpan(span SpanData) { f(span) }
+
+type nopExporter struct{}
+
+func (nopExporter) ExportSpan(SpanData) {}
+
+var (
+	defaultExporterMu sync.RWMutex
+	defaultExporter   Exporter = nopExporter{}
+)
+
+// SetExporter cambia el exporter global; nil desactiva la exportación.
+func SetExporter(exporter Exporter) {
+	if exporter == nil {
+		exporter = nopExporter{}
+	}
+	defaultExporterMu.Lock()
+	defer defaultExporterMu.Unlock()
+	defaultExporter = exporter
+}
+
+func DefaultExporter() Exporter {
+	defaultExporterMu.RLock()
+	defer defaultExporterMu.RUnlock()
+	return defaultExporter
+}
+
+// StdoutExporter escribe cada span como una línea JSON.
+type StdoutExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	return &StdoutExporter{w: w}
+}
+
+func (e *StdoutExporter) ExportSpan(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := json.NewEncoder(e.w).Encode(span); err != nil {
+		slog.Warn("failed to export span", "name", span.Name, "error", err)
+	}
+}
+
+// InMemoryExporter guarda los spans para revisarlos en pruebas.
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+func (e *InMemoryExporter) ExportSpan(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]SpanData(nil), e.spans...)
+}
+
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
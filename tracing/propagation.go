@@ -0,0 +1,36 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+)
+
+// Extract lee traceparent/tracestate de las cabeceras; si no son válidas devuelve ctx.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return ExtractValues(ctx, header.Get(HeaderTraceParent), header.Get(HeaderTraceState))
+}
+
+// ExtractValues es Extract para transportes que no usan http.Header (metadata gRPC).
+func ExtractValues(ctx context.Context, traceParent, traceState string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	sc, err := ParseTraceParent(traceParent)
+	if err != nil {
+		return ctx
+	}
+	sc.TraceState = traceState
+	return ContextWithSpanContext(ctx, sc)
+}
+
+// Inject escribe el contexto de traza de ctx en las cabeceras salientes.
+func Inject(ctx context.Context, header http.Header) {
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	header.Set(HeaderTraceParent, sc.TraceParent())
+	if sc.TraceState != "" {
+		header.Set(HeaderTraceState, sc.TraceState)
+	}
+}
@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type SpanKind string
+
+const (
+	SpanKindInternal SpanKind = "internal"
+	SpanKindServer   SpanKind = "server"
+	SpanKindClient   SpanKind = "client"
+)
+
+// Span es una operación medida; se exporta al llamar End.
+type Span struct {
+	mu         sync.Mutex
+	name       string
+	kind       SpanKind
+	context    SpanContext
+	parent     SpanID
+	start      time.Time
+	end        time.Time
+	attributes map[string]any
+	err        string
+	ended      bool
+}
+
+// SpanData es la copia inmutable de un span terminado que recibe el Exporter.
+type SpanData struct {
+	Name       string         `json:"name"`
+	Kind       SpanKind       `json:"kind"`
+	TraceID    TraceID        `json:"trace_id"`
+	SpanID     SpanID         `json:"span_id"`
+	ParentID   SpanID         `json:"parent_id"`
+	Start      time.Time      `json:"start"`
+	End        time.Time      `json:"end"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+func (d SpanData) Duration() time.Duration { return d.End.Sub(d.Start) }
+
+// Start abre un span hijo del que está en ctx, o la raíz de una traza nueva.
+// El contexto devuelto lleva el span para propagarlo.
+func Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	parent := SpanContextFromContext(ctx)
+	sc := SpanContext{SpanID: newSpanID(), Flags: flagSampled}
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID
+		sc.Flags = parent.Flags
+		sc.TraceState = parent.TraceState
+	} else {
+		sc.TraceID = newTraceID()
+	}
+	span := &Span{
+		name:    name,
+		kind:    kind,
+		context: sc,
+		parent:  parent.SpanID,
+		start:   time.Now(),
+	}
+	return ContextWithSpanContext(ctx, sc), span
+}
+
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.context
+}
+
+func (s *Span) SetAttribute(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.attributes == nil {
+		s.attributes = map[string]any{}
+	}
+	s.attributes[key] = value
+}
+
+// SetError marca el span como fallido; un error nil no cambia nada.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err.Error()
+}
+
+// End cierra el span y lo entrega al exporter si la traza está muestreada.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	attributes := make(map[string]any, len(s.attributes))
+	for key, value := range s.attributes {
+		attributes[key] = value
+	}
+	data := SpanData{
+		Name:       s.name,
+		Kind:       s.kind,
+		TraceID:    s.context.TraceID,
+		SpanID:     s.context.SpanID,
+		ParentID:   s.parent,
+		Start:      s.start,
+		End:        s.end,
+		Attributes: attributes,
+		Error:      s.err,
+	}
+	s.mu.Unlock()
+
+	if s.context.Sampled() {
+		DefaultExporter().ExportSpan(data)
+	}
+}
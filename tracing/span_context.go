@@ -0,0 +1,112 @@
+// Package tracing propaga el contexto de traza W3C (traceparent) entre los
+// servicios y exporta los spans a un Exporter configurable.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+)
+
+var ErrInvalidTraceParent = errors.New("tracing: invalid traceparent")
+
+type (
+	TraceID [16]byte
+	SpanID  [8]byte
+)
+
+func (t TraceID) IsValid() bool  { return t != TraceID{} }
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+func (s SpanID) IsValid() bool   { return s != SpanID{} }
+func (s SpanID) String() string  { return hex.EncodeToString(s[:]) }
+
+func (t TraceID) MarshalText() ([]byte, error) { return []byte(t.String()), nil }
+func (s SpanID) MarshalText() ([]byte, error)  { return []byte(s.String()), nil }
+
+const flagSampled = 0x01
+
+// SpanContext es la parte de la traza que viaja entre servicios.
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Flags      byte
+	TraceState string
+	// Remote indica que el span padre vino de otro proceso.
+	Remote bool
+}
+
+func (sc SpanContext) IsValid() bool { return sc.TraceID.IsValid() && sc.SpanID.IsValid() }
+func (sc SpanContext) Sampled() bool { return sc.Flags&flagSampled != 0 }
+
+// TraceParent devuelve la cabecera en formato "00-<trace-id>-<span-id>-<flags>".
+func (sc SpanContext) TraceParent() string {
+	if !sc.IsValid() {
+		return ""
+	}
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + hex.EncodeToString([]byte{sc.Flags})
+}
+
+// ParseTraceParent interpreta la cabecera traceparent (versión 00).
+func ParseTraceParent(value string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		(parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+	var sc SpanContext
+	if !decodeHex(parts[1], sc.TraceID[:]) || !decodeHex(parts[2], sc.SpanID[:]) {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+	var flags [1]byte
+	if !decodeHex(parts[3], flags[:]) || !sc.IsValid() {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+	sc.Flags = flags[0]
+	sc.Remote = true
+	return sc, nil
+}
+
+func decodeHex(value string, dst []byte) bool {
+	if len(value) != hex.EncodedLen(len(dst)) || strings.ToLower(value) != value {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(value))
+	return err == nil
+}
+
+func newTraceID() (id TraceID) {
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func newSpanID() (id SpanID) {
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+type ctxKey struct{}
+
+// ContextWithSpanContext guarda el contexto de traza junto a los valores de identidad.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	if !sc.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, sc)
+}
+
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	sc, _ := ctx.Value(ctxKey{}).(SpanContext)
+	return sc
+}
+
+// TraceParent devuelve la cabecera traceparent del contexto o "" si no hay traza.
+func TraceParent(ctx context.Context) string {
+	return SpanContextFromContext(ctx).TraceParent()
+}
@@ -0,0 +1,81 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sfperusacdev/identitysdk"
+	"github.com/sfperusacdev/identitysdk/tracing"
+	"github.com/sfperusacdev/identitysdk/xreq"
+)
+
+const incoming = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	sc, err := tracing.ParseTraceParent(incoming)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sc.Sampled() || !sc.Remote || sc.TraceParent() != incoming {
+		t.Fatalf("unexpected span context: %+v", sc)
+	}
+	for _, invalid := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		if _, err := tracing.ParseTraceParent(invalid); err == nil {
+			t.Fatalf("%q: expected error", invalid)
+		}
+	}
+}
+
+func TestPropagationThroughCloneContextAndXreq(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracing.SetExporter(exporter)
+	t.Cleanup(func() { tracing.SetExporter(nil) })
+
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(tracing.HeaderTraceParent)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	header := http.Header{}
+	header.Set(tracing.HeaderTraceParent, incoming)
+	ctx, span := tracing.Start(tracing.Extract(context.Background(), header), "job", tracing.SpanKindServer)
+
+	cloned := identitysdk.CloneContext(ctx)
+	if err := xreq.MakeRequest(cloned, server.URL, "/"); err != nil {
+		t.Fatal(err)
+	}
+	span.End()
+
+	outgoing, err := tracing.ParseTraceParent(received)
+	if err != nil {
+		t.Fatalf("invalid propagated traceparent %q: %v", received, err)
+	}
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected client and server spans, got %d", len(spans))
+	}
+	client, root := spans[0], spans[1]
+	if root.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || root.ParentID.String() != "00f067aa0ba902b7" {
+		t.Fatalf("server span must continue the incoming trace: %+v", root)
+	}
+	if client.Kind != tracing.SpanKindClient || client.TraceID != root.TraceID || client.ParentID != root.SpanID {
+		t.Fatalf("client span must be a child of the server span: %+v", client)
+	}
+	if outgoing.TraceID != root.TraceID || outgoing.SpanID != client.SpanID {
+		t.Fatalf("outgoing traceparent must reference the client span, got %s", received)
+	}
+	if client.Attributes["http.status_code"] != http.StatusOK {
+		t.Fatalf("unexpected attributes: %+v", client.Attributes)
+	}
+}
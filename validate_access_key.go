@@ -1,31 +1,21 @@
 package identitysdk
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/sfperusacdev/identitysdk/xreq"
-	"github.com/user0608/goones/errs"
 )
 
-func ValidateAccessKey(ctx context.Context, access_token string) error {
-	var buff bytes.Buffer
-
-	if err := json.NewEncoder(&buff).
-		Encode(map[string]string{
-			"access_token": access_token,
-		}); err != nil {
-		slog.Error("failed to encode API key payload", "error", err)
-		return errs.InternalErrorDirect(errs.ErrInternal)
+func ValidateAccessKey(ctx context.Context, access_token string) (err error) {
+	defer observeIdentityCall("check-access-token", time.Now(), &err)
+	payload := map[string]string{
+		"access_token": access_token,
+	}
+	if _, err := xreq.Do[any, xreq.NoBody](ctx, identityAddress, http.MethodPost, "/v1/check-access-token", payload); err != nil {
+		err, _ = identityError(err)
+		return err
 	}
-	return xreq.MakeRequest(ctx,
-		identityAddress,
-		"/v1/check-access-token",
-		xreq.WithMethod(http.MethodPost),
-		xreq.WithJsonContentType(),
-		xreq.WithRequestBody(&buff),
-	)
+	return nil
 }
@@ -1,89 +1,69 @@
 package identitysdk
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"log/slog"
+	"errors"
 	"net/http"
-	"net/url"
+	"time"
 
+	"github.com/sfperusacdev/identitysdk/cache"
 	"github.com/sfperusacdev/identitysdk/entities"
 	sessioncache "github.com/sfperusacdev/identitysdk/internal/session_cache"
+	"github.com/sfperusacdev/identitysdk/xreq"
 	"github.com/user0608/goones/errs"
-	"github.com/user0608/ifdevmode"
 )
 
 func ValidateTokenWithCache(ctx context.Context, token string) (*entities.JwtData, error) {
+	if isTokenRevoked(ctx, token) {
+		return nil, errs.BadRequestDirect("[close] sesión revocada")
+	}
+	if tokenVerifier != nil {
+		return validateTokenOffline(ctx, token)
+	}
 	var cacheData = sessioncache.DefaultCache.Get(ctx, token)
+	cache.ObserveLookup(cache.NameSession, cacheData != nil)
 	if cacheData != nil {
-		if ifdevmode.Yes() {
-			slog.Info("Session data read from cache",
-				"empresa", cacheData.Jwt.Empresa,
-				"usuario", cacheData.Jwt.Username,
-				"trabajador", cacheData.Jwt.TabajadorCodigo,
-				"UsuarioReff", cacheData.Jwt.UsuarioReff,
-			)
-		}
+		Logger(ctx).Debug("Session data read from cache",
+			"empresa", cacheData.Jwt.Empresa,
+			"usuario", cacheData.Jwt.Username,
+			"trabajador", cacheData.Jwt.TabajadorCodigo,
+			"UsuarioReff", cacheData.Jwt.UsuarioReff,
+		)
 		return cacheData, nil
 	}
-	jwtData, err := ValidateToken(ctx, token)
-	if err != nil {
-		return nil, err
-	}
-	if jwtData != nil {
-		sessioncache.DefaultCache.Set(ctx, token, *jwtData)
-	}
-	return jwtData, nil
+	return validateTokenRemote(ctx, token)
 }
 
 func ValidateToken(ctx context.Context, token string) (*entities.JwtData, error) {
-	hostURL, err := url.JoinPath(identityAddress, "/v1/check-token")
-	if err != nil {
-		slog.Error("failed to construct token validation URL", "error", err)
-		return nil, errs.InternalErrorDirect(errs.ErrInternal)
-	}
+	jwtData, _, err := checkTokenRemote(ctx, token)
+	return jwtData, err
+}
 
-	var buff bytes.Buffer
+// checkTokenRemote valida el token contra identity; unavailable indica que identity no respondió.
+func checkTokenRemote(ctx context.Context, token string) (_ *entities.JwtData, unavailable bool, err error) {
+	defer observeIdentityCall("check-token", time.Now(), &err)
 	payload := struct {
 		Token string `json:"token"`
 	}{Token: token}
-
-	if err := json.NewEncoder(&buff).Encode(&payload); err != nil {
-		slog.Error("failed to encode token payload", "error", err)
-		return nil, errs.InternalErrorDirect(errs.ErrInternal)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hostURL, &buff)
-	if err != nil {
-		slog.Error("failed to create HTTP request", "url", hostURL, "error", err)
-		return nil, errs.InternalErrorDirect(errs.ErrInternal)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := http.DefaultClient.Do(req)
+	data, err := xreq.Do[any, entities.JwtData](ctx, identityAddress, http.MethodPost, "/v1/check-token", payload, xreq.WithIdempotent())
 	if err != nil {
-		slog.Error("failed to send token validation request", "url", hostURL, "error", err)
-		return nil, errs.InternalErrorDirect("Auth server no responde")
-	}
-	defer res.Body.Close()
-
-	var response struct {
-		Type    string           `json:"type"`
-		Message string           `json:"message"`
-		Data    entities.JwtData `json:"data"`
+		err, unavailable = identityError(err)
+		return nil, unavailable, err
 	}
+	return &data, false, nil
+}
 
-	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
-		slog.Error("failed to decode token validation response", "url", hostURL, "error", err)
-		return nil, errs.InternalErrorDirect(errs.ErrInternal)
+// identityError conserva el mensaje de identity cuando rechaza la credencial y
+// devuelve unavailable cuando identity no respondió o falló (5xx).
+func identityError(err error) (_ error, unavailable bool) {
+	if responseErr, ok := xreq.AsResponseError(err); ok {
+		if responseErr.StatusCode >= http.StatusInternalServerError {
+			return errs.InternalErrorDirect(responseErr.Message), true
+		}
+		return errs.BadRequestDirect(responseErr.Message), false
 	}
-
-	if res.StatusCode != http.StatusOK {
-		slog.Warn("token validation failed", "url", hostURL, "status", res.StatusCode, "message", response.Message)
-		return nil, errs.BadRequestDirect(response.Message)
+	if errors.Is(err, xreq.ErrUnavailable) {
+		return errs.InternalErrorDirect("Auth server no responde"), true
 	}
-
-	return &response.Data, nil
+	return errs.InternalErrorDirect(errs.ErrInternal), false
 }
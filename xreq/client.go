@@ -0,0 +1,458 @@
+package xreq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/metrics"
+	"github.com/sfperusacdev/identitysdk/tracing"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+type ClientConfig struct {
+	// Timeout por intento; se puede reemplazar por llamada con WithCallTimeout.
+	Timeout time.Duration
+	// MaxRetries reintentos adicionales, solo para llamadas idempotentes.
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// BreakerThreshold fallas consecutivas por host que abren el circuito; 0 lo desactiva.
+	// Solo cuentan errores de red, timeouts y 502/503/504.
+	BreakerThreshold int
+	// BreakerCooldown tiempo que el circuito permanece abierto antes de dejar pasar una prueba.
+	BreakerCooldown time.Duration
+}
+
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		Timeout:          30 * time.Second,
+		MaxRetries:       2,
+		RetryBaseDelay:   100 * time.Millisecond,
+		RetryMaxDelay:    2 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+type Outcome string
+
+const (
+	OutcomeSuccess      Outcome = "success"
+	OutcomeClientError  Outcome = "client_error"
+	OutcomeServerError  Outcome = "server_error"
+	OutcomeNetworkError Outcome = "network_error"
+	OutcomeTimeout      Outcome = "timeout"
+	OutcomeCircuitOpen  Outcome = "circuit_open"
+)
+
+// Event describe el resultado de un intento.
+type Event struct {
+	Host       string
+	Method     string
+	StatusCode int
+	Outcome    Outcome
+	Attempt    int
+	Duration   time.Duration
+}
+
+type MetricsRecorder interface {
+	Record(Event)
+}
+
+type MetricsFunc func(Event)
+
+func (f MetricsFunc) Record(e Event) { f(e) }
+
+type ClientOption func(*Client)
+
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		if rt != nil {
+			c.transport = rt
+		}
+	}
+}
+
+func WithMetrics(m MetricsRecorder) ClientOption {
+	return func(c *Client) {
+		if m != nil {
+			c.metrics = append(c.metrics, m)
+		}
+	}
+}
+
+// Client es el cliente HTTP compartido para identity y los servicios internos.
+type Client struct {
+	cfg       ClientConfig
+	transport http.RoundTripper
+	metrics   []MetricsRecorder
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+func NewClient(cfg ClientConfig, opts ...ClientOption) *Client {
+	defaults := DefaultClientConfig()
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.RetryBaseDelay <= 0 {
+		cfg.RetryBaseDelay = defaults.RetryBaseDelay
+	}
+	if cfg.RetryMaxDelay < cfg.RetryBaseDelay {
+		cfg.RetryMaxDelay = max(defaults.RetryMaxDelay, cfg.RetryBaseDelay)
+	}
+	if cfg.BreakerCooldown <= 0 {
+		cfg.BreakerCooldown = defaults.BreakerCooldown
+	}
+	c := &Client{
+		cfg:       cfg,
+		transport: http.DefaultTransport,
+		breakers:  map[string]*breaker{},
+	}
+	for _, apply := range opts {
+		apply(c)
+	}
+	return c
+}
+
+var (
+	defaultClientMu sync.RWMutex
+	defaultClient   = NewClient(DefaultClientConfig())
+)
+
+func SetDefaultClient(c *Client) {
+	if c == nil {
+		return
+	}
+	defaultClientMu.Lock()
+	defer defaultClientMu.Unlock()
+	defaultClient = c
+}
+
+func DefaultClient() *Client {
+	defaultClientMu.RLock()
+	defer defaultClientMu.RUnlock()
+	return defaultClient
+}
+
+// StandardClient expone el cliente como *http.Client para librerías que lo requieran.
+func (c *Client) StandardClient() *http.Client {
+	return &http.Client{Transport: roundTripperFunc(c.Do)}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+type ctxKey int
+
+const (
+	timeoutKey ctxKey = iota
+	idempotentKey
+)
+
+// WithCallTimeout reemplaza el timeout por intento para las llamadas hechas con ctx.
+func WithCallTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey, d)
+}
+
+// Idempotent marca las llamadas hechas con ctx como reintentables aunque el método no lo sea (p. ej. POST de validación).
+func Idempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey, true)
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	}
+	if req.Header.Get("Idempotency-Key") != "" {
+		return true
+	}
+	marked, _ := req.Context().Value(idempotentKey).(bool)
+	return marked
+}
+
+// Do ejecuta la petición aplicando timeout, circuit breaker y reintentos;
+// propaga la traza del contexto en la cabecera traceparent.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.Start(req.Context(), "HTTP "+req.Method, tracing.SpanKindClient)
+	defer span.End()
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.url", req.URL.Scheme+"://"+req.URL.Host+req.URL.Path)
+
+	req = req.Clone(ctx)
+	tracing.Inject(ctx, req.Header)
+	res, err := c.do(req)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	span.SetAttribute("http.status_code", res.StatusCode)
+	return res, nil
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	br := c.breaker(host)
+
+	attempts := 1
+	if isIdempotent(req) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil) {
+		attempts += c.cfg.MaxRetries
+	}
+	timeout := c.cfg.Timeout
+	if d, ok := req.Context().Value(timeoutKey).(time.Duration); ok && d > 0 {
+		timeout = d
+	}
+
+	for attempt := 1; ; attempt++ {
+		if !br.allow() {
+			c.record(Event{Host: host, Method: req.Method, Outcome: OutcomeCircuitOpen, Attempt: attempt})
+			return nil, fmt.Errorf("%s: %w", host, ErrCircuitOpen)
+		}
+
+		attemptReq := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				br.release()
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		ctx, cancel := context.WithTimeout(attemptReq.Context(), timeout)
+		started := time.Now()
+		res, err := c.transport.RoundTrip(attemptReq.WithContext(ctx))
+		event := Event{Host: host, Method: req.Method, Attempt: attempt, Duration: time.Since(started)}
+
+		retryable := false
+		switch {
+		case err != nil:
+			event.Outcome = OutcomeNetworkError
+			if errors.Is(err, context.DeadlineExceeded) || isTimeout(err) {
+				event.Outcome = OutcomeTimeout
+			}
+			if req.Context().Err() != nil {
+				// la cancelación del llamador no dice nada del host
+				br.release()
+				c.record(event)
+				cancel()
+				return nil, err
+			}
+			br.failure()
+			retryable = true
+		case res.StatusCode >= http.StatusInternalServerError:
+			event.StatusCode, event.Outcome = res.StatusCode, OutcomeServerError
+			retryable = res.StatusCode == http.StatusBadGateway ||
+				res.StatusCode == http.StatusServiceUnavailable ||
+				res.StatusCode == http.StatusGatewayTimeout
+			if retryable {
+				br.failure()
+			} else {
+				// un 500 es una respuesta del servicio, no un host caído
+				br.success()
+			}
+		case res.StatusCode == http.StatusTooManyRequests:
+			event.StatusCode, event.Outcome = res.StatusCode, OutcomeClientError
+			br.success()
+			retryable = true
+		default:
+			event.StatusCode, event.Outcome = res.StatusCode, OutcomeSuccess
+			if res.StatusCode >= http.StatusBadRequest {
+				event.Outcome = OutcomeClientError
+			}
+			br.success()
+		}
+		c.record(event)
+
+		if !retryable || attempt >= attempts {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+			return res, nil
+		}
+
+		delay := c.backoff(attempt)
+		if res != nil {
+			if retryAfter := parseRetryAfter(res.Header.Get("Retry-After")); retryAfter > 0 {
+				delay = min(retryAfter, c.cfg.RetryMaxDelay)
+			}
+			io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+			res.Body.Close()
+		}
+		slog.Warn("retrying http request", "host", host, "method", req.Method, "attempt", attempt, "outcome", event.Outcome, "delay", delay)
+
+		cancel()
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.cfg.RetryBaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > c.cfg.RetryMaxDelay {
+		ceiling = c.cfg.RetryMaxDelay
+	}
+	return time.Duration(rand.Int64N(int64(ceiling)) + 1)
+}
+
+var attemptDuration = metrics.NewHistogram(
+	"http_client_request_duration_seconds",
+	"Latencia de cada intento del cliente HTTP compartido por host, método y resultado.",
+	nil,
+	"host", "method", "outcome",
+)
+
+func (c *Client) record(e Event) {
+	outcomeStats.Record(e)
+	attemptDuration.With(e.Host, e.Method, string(e.Outcome)).Observe(e.Duration.Seconds())
+	for _, m := range c.metrics {
+		m.Record(e)
+	}
+}
+
+func (c *Client) breaker(host string) *breaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	br, found := c.breakers[host]
+	if !found {
+		br = &breaker{threshold: c.cfg.BreakerThreshold, cooldown: c.cfg.BreakerCooldown}
+		c.breakers[host] = br
+	}
+	return br
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// breaker es un circuit breaker por host: se abre tras threshold fallas
+// consecutivas (errores de red, timeouts y 502/503/504) y, pasado el cooldown, deja pasar una sola petición de prueba.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func (b *breaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if time.Since(b.openedAt) < b.cooldown || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+}
+
+// release libera la petición de prueba sin contarla como éxito ni como falla.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+	b.probing = false
+}
+
+var outcomeStats = NewCounterMetrics()
+
+// OutcomeStats devuelve los intentos por resultado de todos los clientes del proceso.
+func OutcomeStats() map[Outcome]uint64 { return outcomeStats.Snapshot() }
+
+// CounterMetrics acumula en memoria la cantidad de intentos por resultado.
+type CounterMetrics struct {
+	mu     sync.Mutex
+	counts map[Outcome]uint64
+}
+
+func NewCounterMetrics() *CounterMetrics {
+	return &CounterMetrics{counts: map[Outcome]uint64{}}
+}
+
+func (m *CounterMetrics) Record(e Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[e.Outcome]++
+}
+
+func (m *CounterMetrics) Snapshot() map[Outcome]uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[Outcome]uint64, len(m.counts))
+	for k, v := range m.counts {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,214 @@
+package xreq_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sfperusacdev/identitysdk/xreq"
+)
+
+func testClientConfig() xreq.ClientConfig {
+	return xreq.ClientConfig{
+		Timeout:          time.Second,
+		MaxRetries:       2,
+		RetryBaseDelay:   time.Millisecond,
+		RetryMaxDelay:    5 * time.Millisecond,
+		BreakerThreshold: 3,
+		BreakerCooldown:  time.Hour,
+	}
+}
+
+func TestClientRetriesIdempotentCalls(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	metrics := xreq.NewCounterMetrics()
+	client := xreq.NewClient(testClientConfig(), xreq.WithMetrics(metrics))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Fatalf("expected success after 3 calls, got status %d after %d calls", res.StatusCode, calls.Load())
+	}
+	stats := metrics.Snapshot()
+	if stats[xreq.OutcomeServerError] != 2 || stats[xreq.OutcomeSuccess] != 1 {
+		t.Fatalf("unexpected metrics: %v", stats)
+	}
+}
+
+func TestClientDoesNotRetryNonIdempotentCalls(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := testClientConfig()
+	cfg.BreakerThreshold = 10
+	client := xreq.NewClient(cfg)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{}`))
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res.Body.Close()
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single call, got %d", calls.Load())
+	}
+
+	req, _ = http.NewRequestWithContext(xreq.Idempotent(context.Background()), http.MethodPost, server.URL, strings.NewReader(`{}`))
+	res, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res.Body.Close()
+	if calls.Load() != 4 {
+		t.Fatalf("expected marked POST to be retried, got %d calls", calls.Load())
+	}
+}
+
+func TestClientTimeoutAndCircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	metrics := xreq.NewCounterMetrics()
+	client := xreq.NewClient(testClientConfig(), xreq.WithMetrics(metrics))
+	ctx := xreq.WithCallTimeout(context.Background(), 20*time.Millisecond)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if metrics.Snapshot()[xreq.OutcomeTimeout] != 3 {
+		t.Fatalf("expected 3 timeouts, got %v", metrics.Snapshot())
+	}
+
+	_, err := client.Do(req)
+	if !errors.Is(err, xreq.ErrCircuitOpen) {
+		t.Fatalf("expected open circuit, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("open circuit must fail fast, got %d calls", calls.Load())
+	}
+}
+
+func TestClientBreakerReleasesCancelledProbe(t *testing.T) {
+	var calls atomic.Int32
+	probing := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusInternalServerError)
+		case 2:
+			close(probing)
+			<-r.Context().Done()
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	cfg := testClientConfig()
+	cfg.MaxRetries = 0
+	cfg.BreakerThreshold = 1
+	cfg.BreakerCooldown = 10 * time.Millisecond
+	client := xreq.NewClient(cfg)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res.Body.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	// el llamador cancela la petición de prueba antes de que el host responda
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-probing
+		cancel()
+	}()
+	probe, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := client.Do(probe); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled probe, got %v", err)
+	}
+
+	res, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("expected a new probe after the cancelled one, got %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Fatalf("unexpected status %d after %d calls", res.StatusCode, calls.Load())
+	}
+}
+
+func TestMakeRequestUsesDefaultClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	metrics := xreq.NewCounterMetrics()
+	previous := xreq.DefaultClient()
+	xreq.SetDefaultClient(xreq.NewClient(testClientConfig(), xreq.WithMetrics(metrics)))
+	t.Cleanup(func() { xreq.SetDefaultClient(previous) })
+
+	var out struct {
+		OK bool `json:"ok"`
+	}
+	if err := xreq.MakeRequest(context.Background(), server.URL, "/", xreq.WithUnmarshalResponseInto(&out)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !out.OK || metrics.Snapshot()[xreq.OutcomeSuccess] != 1 {
+		t.Fatalf("expected request through default client, got %v", metrics.Snapshot())
+	}
+}
+
+func TestClientBreakerIgnoresInternalServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := xreq.NewClient(testClientConfig())
+	for i := 0; i < 5; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		res, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("call %d: a 500 must not open the circuit, got %v", i, err)
+		}
+		res.Body.Close()
+	}
+	if calls.Load() != 5 {
+		t.Fatalf("expected 5 calls without retries, got %d", calls.Load())
+	}
+}
@@ -0,0 +1,73 @@
+package xreq
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/user0608/goones/errs"
+)
+
+// ErrUnavailable indica que el servicio remoto no respondió: error de red,
+// timeout o circuito abierto.
+var ErrUnavailable = errors.New("service unavailable")
+
+// ResponseError respuesta no exitosa de un servicio remoto. Error y Unwrap
+// delegan en el errs equivalente al estado, así el usuario recibe el mismo
+// mensaje y answer.Err responde con el mismo código, sin exponer el endpoint.
+type ResponseError struct {
+	StatusCode int
+	Message    string
+	Endpoint   string
+	Method     string
+}
+
+func (e *ResponseError) Error() string { return e.Errs().Error() }
+
+func (e *ResponseError) Unwrap() error { return e.Errs() }
+
+// Errs convierte el estado en la categoría de errs: 404 NotFound, 401 y 403
+// Forbidden, otros 4xx BadRequest y 5xx Internal. Un envelope de error con
+// estado exitoso se considera BadRequest.
+func (e *ResponseError) Errs() error {
+	message := e.Message
+	if message == "" {
+		message = fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return errs.NotFoundf("%s", message)
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return errs.ForbiddenDirect(message)
+	case e.StatusCode < http.StatusInternalServerError:
+		return errs.BadRequestDirect(message)
+	default:
+		return errs.InternalErrorDirect(message)
+	}
+}
+
+// AsResponseError busca un ResponseError en la cadena de err.
+func AsResponseError(err error) (*ResponseError, bool) {
+	var responseErr *ResponseError
+	ok := errors.As(err, &responseErr)
+	return responseErr, ok
+}
+
+// StatusCode devuelve el estado HTTP del servicio remoto o 0 si err no es una respuesta.
+func StatusCode(err error) int {
+	if responseErr, ok := AsResponseError(err); ok {
+		return responseErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound indica si el servicio remoto respondió 404.
+func IsNotFound(err error) bool { return StatusCode(err) == http.StatusNotFound }
+
+// ToErrs reemplaza un ResponseError por su errs equivalente; otros errores no cambian.
+func ToErrs(err error) error {
+	if responseErr, ok := AsResponseError(err); ok {
+		return responseErr.Errs()
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package xreq_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sfperusacdev/identitysdk/xreq"
+	"github.com/user0608/goones/errs"
+)
+
+func TestMakeRequestPreservesStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"type":"error","message":"trabajador no encontrado"}`))
+	}))
+	defer server.Close()
+
+	err := xreq.MakeRequest(context.Background(), server.URL, "/v1/trabajadores/T001")
+
+	responseErr, ok := xreq.AsResponseError(err)
+	if !ok {
+		t.Fatalf("expected *xreq.ResponseError, got %T", err)
+	}
+	if responseErr.StatusCode != http.StatusNotFound || responseErr.Method != http.MethodGet ||
+		responseErr.Endpoint != server.URL+"/v1/trabajadores/T001" || responseErr.Message != "trabajador no encontrado" {
+		t.Fatalf("unexpected response error %+v", responseErr)
+	}
+	if !xreq.IsNotFound(err) {
+		t.Fatal("expected IsNotFound")
+	}
+	var e *errs.Error
+	if !errors.As(err, &e) || e.Code != http.StatusNotFound {
+		t.Fatalf("expected errs code 404, got %v", err)
+	}
+	if converted := xreq.ToErrs(err); !errors.As(converted, &e) || e.Code != http.StatusNotFound {
+		t.Fatalf("expected ToErrs to return errs code 404, got %v", converted)
+	}
+}
+
+func TestMakeRequestUnmarshalDataInto(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rechazo" {
+			w.Write([]byte(`{"type":"error","message":"periodo cerrado"}`))
+			return
+		}
+		w.Write([]byte(`{"type":"success","message":"ok","data":{"name":"ana"}}`))
+	}))
+	defer server.Close()
+
+	var data greeting
+	if err := xreq.MakeRequest(context.Background(), server.URL, "/", xreq.WithUnmarshalDataInto(&data)); err != nil {
+		t.Fatal(err)
+	}
+	if data.Name != "ana" {
+		t.Fatalf("expected data to be decoded, got %+v", data)
+	}
+
+	err := xreq.MakeRequest(context.Background(), server.URL, "/rechazo", xreq.WithUnmarshalDataInto(&data))
+	var e *errs.Error
+	if !errors.As(err, &e) || e.Code != http.StatusBadRequest || e.Message != "periodo cerrado" {
+		t.Fatalf("expected error envelope to become a bad request, got %v", err)
+	}
+}
+
+func TestMakeRequestUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	err := xreq.MakeRequest(context.Background(), server.URL, "/", xreq.WithMethod(http.MethodPost))
+	if !errors.Is(err, xreq.ErrUnavailable) || xreq.StatusCode(err) != 0 {
+		t.Fatalf("expected ErrUnavailable, got %v", err)
+	}
+}
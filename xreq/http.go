@@ -5,10 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/user0608/goones/errs"
 )
@@ -23,6 +25,9 @@ type RequestOptions struct {
 	Headers      http.Header
 	RequestBody  XReqBody
 	ResponseBody any // ResponseBody is decoded from a JSON response body.
+	ResponseData any // ResponseData is decoded from the data field of the response envelope.
+	Timeout      time.Duration
+	Idempotent   bool
 }
 
 type RequestOption func(*RequestOptions)
@@ -33,6 +38,20 @@ func WithMethod(method string) RequestOption {
 	}
 }
 
+// WithTimeout overrides the client's per-attempt timeout for this call.
+func WithTimeout(d time.Duration) RequestOption {
+	return func(o *RequestOptions) {
+		o.Timeout = d
+	}
+}
+
+// WithIdempotent allows retries for non idempotent methods, e.g. a POST that only reads.
+func WithIdempotent() RequestOption {
+	return func(o *RequestOptions) {
+		o.Idempotent = true
+	}
+}
+
 func WithAuthorization(token string) RequestOption {
 	return WithHeader("Authorization", token)
 }
@@ -88,6 +107,14 @@ func WithUnmarshalResponseInto(a any) RequestOption {
 	}
 }
 
+// WithUnmarshalDataInto decodes the data field of the SARK {type,message,data}
+// envelope into the provided variable; an envelope of type "error" returns a *ResponseError.
+func WithUnmarshalDataInto(a any) RequestOption {
+	return func(o *RequestOptions) {
+		o.ResponseData = a
+	}
+}
+
 func WithJsonContentType() RequestOption {
 	return WithHeader("Content-Type", "application/json")
 }
@@ -141,19 +168,69 @@ var defaultXOrigin string
 func SetDefaultXOrigin(xorigin string) { defaultXOrigin = xorigin }
 
 func MakeRequest(ctx context.Context, baseUrl, endpointPath string, opts ...RequestOption) error {
+	options := &RequestOptions{
+		Method: http.MethodGet,
+	}
+	for _, apply := range opts {
+		apply(options)
+	}
+	req, err := newRequest(ctx, baseUrl, endpointPath, options)
+	if err != nil {
+		return err
+	}
+	endpoint := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+
+	res, err := DefaultClient().Do(req)
+	if err != nil {
+		slog.Error("error on request", "error", err, "endpoint", endpoint, "method", options.Method)
+		return errs.InternalError(fmt.Errorf("%w: %w", ErrUnavailable, err), "falló la petición %s a %s", options.Method, endpoint)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return responseError(res, options.Method, endpoint)
+	}
+
+	if options.ResponseData != nil {
+		envelope := Envelope[any]{Data: options.ResponseData}
+		if err := json.NewDecoder(res.Body).Decode(&envelope); err != nil && !errors.Is(err, io.EOF) {
+			slog.Error("error json decoding response", "error", err, "basepath", baseUrl, "path", endpointPath)
+			return errs.InternalError(err, "falló la decodificación de la respuesta JSON de %s%s", baseUrl, endpointPath)
+		}
+		if envelope.Type == "error" {
+			slog.Error("service response error", "status", res.StatusCode, "message", envelope.Message, "endpoint", endpoint, "method", options.Method)
+			return &ResponseError{StatusCode: res.StatusCode, Message: envelope.Message, Endpoint: endpoint, Method: options.Method}
+		}
+		return nil
+	}
+
+	if options.ResponseBody != nil {
+		jsonDecoder := json.NewDecoder(res.Body)
+		if err := jsonDecoder.Decode(options.ResponseBody); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			slog.Error("error json decoding response", "error", err, "basepath", baseUrl, "path", endpointPath)
+			return errs.InternalError(
+				err,
+				"falló la decodificación de la respuesta JSON de %s%s",
+				baseUrl,
+				endpointPath,
+			)
+		}
+	}
+	return nil
+}
+
+// newRequest arma la petición con los parámetros, cabeceras y timeout de options.
+func newRequest(ctx context.Context, baseUrl, endpointPath string, options *RequestOptions) (*http.Request, error) {
 	if baseUrl == "" {
 		err := errors.New("base URL cannot be empty")
 		slog.Error(
 			"validating base URL",
 			"error", err,
 		)
-		return err
-	}
-	options := &RequestOptions{
-		Method: http.MethodGet,
-	}
-	for _, apply := range opts {
-		apply(options)
+		return nil, err
 	}
 	endpoint, err := url.JoinPath(baseUrl, endpointPath)
 	if err != nil {
@@ -162,16 +239,22 @@ func MakeRequest(ctx context.Context, baseUrl, endpointPath string, opts ...Requ
 			"error", err,
 			"baseurl", baseUrl,
 		)
-		return errs.InternalError(err, "falló la construcción de la URL para %s%s", baseUrl, endpointPath)
+		return nil, errs.InternalError(err, "falló la construcción de la URL para %s%s", baseUrl, endpointPath)
 	}
 	if options.RequestBody.Error != nil {
-		return errs.InternalError(
+		return nil, errs.InternalError(
 			options.RequestBody.Error,
 			"no se pudo preparar el body de la solicitud para %s %s",
 			options.Method,
 			endpoint,
 		)
 	}
+	if options.Timeout > 0 {
+		ctx = WithCallTimeout(ctx, options.Timeout)
+	}
+	if options.Idempotent {
+		ctx = Idempotent(ctx)
+	}
 	req, err := http.NewRequestWithContext(ctx, options.Method, endpoint, options.RequestBody.Reader)
 	if err != nil {
 		slog.Error(
@@ -179,7 +262,7 @@ func MakeRequest(ctx context.Context, baseUrl, endpointPath string, opts ...Requ
 			"error", err,
 			"endpoint", endpoint,
 		)
-		return errs.InternalError(err, "falló la creación de la request para %s", endpoint)
+		return nil, errs.InternalError(err, "falló la creación de la request para %s", endpoint)
 	}
 
 	if options.QueryParams != nil {
@@ -201,54 +284,5 @@ func MakeRequest(ctx context.Context, baseUrl, endpointPath string, opts ...Requ
 			}
 		}
 	}
-
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		slog.Error("error on request", "error", err, "endpoint", endpoint, "method", options.Method)
-		return errs.InternalError(err, "falló la petición %s a %s", options.Method, endpoint)
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		body, readErr := io.ReadAll(res.Body)
-		if readErr != nil {
-			return errs.InternalError(readErr, "falló la lectura de la respuesta de error de %s", endpoint)
-		}
-
-		var apiResponse struct {
-			Message string `json:"message"`
-		}
-
-		message := string(body)
-		if err := json.Unmarshal(body, &apiResponse); err == nil && apiResponse.Message != "" {
-			message = apiResponse.Message
-		}
-
-		slog.Error(
-			"service response error",
-			"status", res.StatusCode,
-			"message", message,
-			"endpoint", endpoint,
-			"method", options.Method,
-		)
-
-		return errs.InternalErrorDirect(message)
-	}
-
-	if options.ResponseBody != nil {
-		jsonDecoder := json.NewDecoder(res.Body)
-		if err := jsonDecoder.Decode(options.ResponseBody); err != nil {
-			if errors.Is(err, io.EOF) {
-				return nil
-			}
-			slog.Error("error json decoding response", "error", err, "basepath", baseUrl, "path", endpointPath)
-			return errs.InternalError(
-				err,
-				"falló la decodificación de la respuesta JSON de %s%s",
-				baseUrl,
-				endpointPath,
-			)
-		}
-	}
-	return nil
+	return req, nil
 }
@@ -0,0 +1,242 @@
+package xreq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"sync"
+
+	"github.com/user0608/goones/errs"
+)
+
+// Envelope es la respuesta estándar de los servicios SARK.
+type Envelope[T any] struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Data    T      `json:"data"`
+}
+
+// NoBody se usa como Req o Resp de Do cuando la petición o la respuesta no tienen cuerpo.
+type NoBody struct{}
+
+type MultipartField struct {
+	Name  string
+	Value string
+}
+
+type MultipartFile struct {
+	Field    string
+	Filename string
+	// ContentType por defecto application/octet-stream
+	ContentType string
+	Content     io.Reader
+}
+
+// Multipart cuerpo multipart/form-data; los archivos se envían en streaming sin
+// cargarlos en memoria, por lo que la petición no se reintenta.
+type Multipart struct {
+	Fields []MultipartField
+	Files  []MultipartFile
+}
+
+func (m Multipart) reader() (io.Reader, string) {
+	pr, pw := io.Pipe()
+	body := &multipartBody{form: m, writer: multipart.NewWriter(pw), pr: pr, pw: pw}
+	return body, body.writer.FormDataContentType()
+}
+
+// multipartBody empieza a escribir el formulario en la primera lectura: si la
+// petición no llega a enviarse (URL inválida, breaker abierto) no queda una
+// goroutine bloqueada en el pipe. El transport lo cierra al terminar o fallar.
+type multipartBody struct {
+	form   Multipart
+	writer *multipart.Writer
+	pr     *io.PipeReader
+	pw     *io.PipeWriter
+	once   sync.Once
+}
+
+func (b *multipartBody) Read(p []byte) (int, error) {
+	b.once.Do(func() {
+		go func() {
+			b.pw.CloseWithError(b.form.write(b.writer))
+		}()
+	})
+	return b.pr.Read(p)
+}
+
+func (b *multipartBody) Close() error {
+	return b.pr.Close()
+}
+
+func (m Multipart) write(writer *multipart.Writer) error {
+	for _, field := range m.Fields {
+		if err := writer.WriteField(field.Name, field.Value); err != nil {
+			return err
+		}
+	}
+	for _, file := range m.Files {
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", multipart.FileContentDisposition(file.Field, file.Filename))
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, file.Content); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// Credentials se reenvían a los servicios internos cuando la llamada no define
+// Authorization ni X-Access-Token.
+type Credentials struct {
+	// Token del usuario que originó la petición; tiene prioridad
+	Token string
+	// AccessToken del servicio, para llamadas sin usuario
+	AccessToken string
+}
+
+type CredentialsFunc func(ctx context.Context) Credentials
+
+var (
+	credentialsMu sync.RWMutex
+	credentials   CredentialsFunc
+)
+
+// SetCredentialsFunc define de dónde lee Do las credenciales del contexto;
+// identitysdk lo registra al importarse.
+func SetCredentialsFunc(fn CredentialsFunc) {
+	credentialsMu.Lock()
+	defer credentialsMu.Unlock()
+	credentials = fn
+}
+
+func applyCredentials(ctx context.Context, options *RequestOptions) {
+	credentialsMu.RLock()
+	fn := credentials
+	credentialsMu.RUnlock()
+	if fn == nil {
+		return
+	}
+	if options.Headers.Get("Authorization") != "" || options.Headers.Get("X-Access-Token") != "" {
+		return
+	}
+	creds := fn(ctx)
+	switch {
+	case creds.Token != "":
+		WithAuthorization(creds.Token)(options)
+	case creds.AccessToken != "":
+		WithAccessToken(creds.AccessToken)(options)
+	}
+}
+
+func setBody(options *RequestOptions, body any) {
+	switch value := body.(type) {
+	case nil, NoBody, *NoBody:
+	case Multipart:
+		reader, contentType := value.reader()
+		options.RequestBody = XReqBody{Reader: reader}
+		WithHeader("Content-Type", contentType)(options)
+	case *Multipart:
+		setBody(options, *value)
+	case io.Reader:
+		options.RequestBody = XReqBody{Reader: value}
+		WithHeader("Content-Type", "application/octet-stream")(options)
+	default:
+		WithJSONBody(value)(options)
+	}
+}
+
+// Do llama a un servicio SARK y devuelve el campo data de la respuesta.
+//
+// body se envía como JSON, salvo NoBody (sin cuerpo), io.Reader (streaming) y
+// Multipart. Con Resp io.ReadCloser se devuelve el cuerpo sin decodificar y el
+// llamador debe cerrarlo. Los estados 4xx/5xx devuelven un *ResponseError. Si
+// no se indica Authorization ni X-Access-Token se reenvían las credenciales del contexto.
+//
+//	trabajador, err := xreq.Do[xreq.NoBody, TrabajadorDto](ctx, baseurl, http.MethodGet, "/v1/trabajadores/"+codigo, xreq.NoBody{})
+func Do[Req, Resp any](ctx context.Context, baseUrl, method, endpointPath string, body Req, opts ...RequestOption) (Resp, error) {
+	var resp Resp
+	options := &RequestOptions{Method: method}
+	setBody(options, body)
+	for _, apply := range opts {
+		apply(options)
+	}
+	applyCredentials(ctx, options)
+
+	req, err := newRequest(ctx, baseUrl, endpointPath, options)
+	if err != nil {
+		return resp, err
+	}
+	endpoint := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+
+	res, err := DefaultClient().Do(req)
+	if err != nil {
+		slog.Error("error on request", "error", err, "endpoint", endpoint, "method", method)
+		return resp, errs.InternalError(fmt.Errorf("%w: %w", ErrUnavailable, err), "falló la petición %s a %s", method, endpoint)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		defer res.Body.Close()
+		return resp, responseError(res, method, endpoint)
+	}
+
+	if target, ok := any(&resp).(*io.ReadCloser); ok {
+		*target = res.Body
+		return resp, nil
+	}
+	defer res.Body.Close()
+
+	if _, ok := any(resp).(NoBody); ok {
+		io.Copy(io.Discard, res.Body)
+		return resp, nil
+	}
+
+	var envelope Envelope[Resp]
+	if err := json.NewDecoder(res.Body).Decode(&envelope); err != nil {
+		if errors.Is(err, io.EOF) {
+			return resp, nil
+		}
+		slog.Error("error json decoding response", "error", err, "endpoint", endpoint, "method", method)
+		return resp, errs.InternalError(err, "falló la decodificación de la respuesta JSON de %s", endpoint)
+	}
+	if envelope.Type == "error" {
+		slog.Error("service response error", "status", res.StatusCode, "message", envelope.Message, "endpoint", endpoint, "method", method)
+		return resp, &ResponseError{StatusCode: res.StatusCode, Message: envelope.Message, Endpoint: endpoint, Method: method}
+	}
+	return envelope.Data, nil
+}
+
+// responseError lee el mensaje del envelope de error, o el cuerpo completo si no lo es.
+func responseError(res *http.Response, method, endpoint string) error {
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return errs.InternalError(err, "falló la lectura de la respuesta de error de %s", endpoint)
+	}
+	message := string(body)
+	var envelope Envelope[json.RawMessage]
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Message != "" {
+		message = envelope.Message
+	}
+	slog.Error(
+		"service response error",
+		"status", res.StatusCode,
+		"message", message,
+		"endpoint", endpoint,
+		"method", method,
+	)
+	return &ResponseError{StatusCode: res.StatusCode, Message: message, Endpoint: endpoint, Method: method}
+}
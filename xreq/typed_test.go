@@ -0,0 +1,136 @@
+package xreq_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sfperusacdev/identitysdk/xreq"
+	"github.com/user0608/goones/errs"
+)
+
+type tokenKey struct{}
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+func TestDoDecodesEnvelopeAndForwardsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in greeting
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"type":    "success",
+			"message": "ok",
+			"data":    greeting{Name: "hola " + in.Name + " " + r.Header.Get("Authorization") + r.Header.Get("X-Access-Token")},
+		})
+	}))
+	defer server.Close()
+
+	xreq.SetCredentialsFunc(func(ctx context.Context) xreq.Credentials {
+		token, _ := ctx.Value(tokenKey{}).(string)
+		return xreq.Credentials{Token: token, AccessToken: "service-key"}
+	})
+	t.Cleanup(func() { xreq.SetCredentialsFunc(nil) })
+
+	ctx := context.WithValue(context.Background(), tokenKey{}, "user-token")
+	out, err := xreq.Do[greeting, greeting](ctx, server.URL, http.MethodPost, "/saludo", greeting{Name: "ana"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != "hola ana user-token" {
+		t.Fatalf("unexpected response %q", out.Name)
+	}
+
+	out, err = xreq.Do[greeting, greeting](context.Background(), server.URL, http.MethodPost, "/saludo", greeting{Name: "ana"})
+	if err != nil || out.Name != "hola ana service-key" {
+		t.Fatalf("expected access token without user, got %q (%v)", out.Name, err)
+	}
+
+	out, err = xreq.Do[greeting, greeting](ctx, server.URL, http.MethodPost, "/saludo", greeting{Name: "ana"},
+		xreq.WithAuthorization("explicit"))
+	if err != nil || out.Name != "hola ana explicit" {
+		t.Fatalf("expected explicit authorization to win, got %q (%v)", out.Name, err)
+	}
+}
+
+func TestDoMapsStatusToErrs(t *testing.T) {
+	cases := map[int]int{
+		http.StatusNotFound:            http.StatusNotFound,
+		http.StatusUnauthorized:        http.StatusForbidden,
+		http.StatusConflict:            http.StatusBadRequest,
+		http.StatusUnprocessableEntity: http.StatusBadRequest,
+		http.StatusInternalServerError: http.StatusInternalServerError,
+	}
+	for status, want := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"type":"error","message":"no se pudo"}`))
+		}))
+		_, err := xreq.Do[xreq.NoBody, greeting](context.Background(), server.URL, http.MethodGet, "/", xreq.NoBody{})
+		server.Close()
+
+		var e *errs.Error
+		if !errors.As(err, &e) || e.Code != want || e.Message != "no se pudo" {
+			t.Fatalf("status %d: expected errs code %d, got %v", status, want, err)
+		}
+	}
+}
+
+func TestDoMultipartAndStreamingResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("archivo")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+		io.WriteString(w, r.FormValue("empresa")+"|"+header.Filename+"|"+string(content))
+	}))
+	defer server.Close()
+
+	body, err := xreq.Do[xreq.Multipart, io.ReadCloser](context.Background(), server.URL, http.MethodPost, "/upload", xreq.Multipart{
+		Fields: []xreq.MultipartField{{Name: "empresa", Value: "sfperu"}},
+		Files:  []xreq.MultipartFile{{Field: "archivo", Filename: "data.csv", Content: strings.NewReader("a,b,c")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != "sfperu|data.csv|a,b,c" {
+		t.Fatalf("unexpected response %q", raw)
+	}
+}
+
+func TestDoMultipartDoesNotLeakWhenNotSent(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for range 10 {
+		_, err := xreq.Do[xreq.Multipart, xreq.NoBody](context.Background(), "://invalida", http.MethodPost, "/upload", xreq.Multipart{
+			Fields: []xreq.MultipartField{{Name: "empresa", Value: "sfperu"}},
+		})
+		if err == nil {
+			t.Fatal("expected an invalid URL error")
+		}
+	}
+	if after := runtime.NumGoroutine(); after >= before+5 {
+		t.Fatalf("multipart writers left running: %d goroutines before, %d after", before, after)
+	}
+}